@@ -16,6 +16,7 @@ require (
 	github.com/paysuper/paysuper-proto/go/recurringpb v0.0.0-20200123205409-310033c3629d // indirect
 	github.com/paysuper/paysuper-proto/go/reporterpb v0.0.0-20200123200131-df93e6644cbd
 	github.com/paysuper/paysuper-tools v0.0.0-20200117101901-522574ce4d1c
+	github.com/prometheus/client_golang v1.2.1
 	github.com/streadway/amqp v0.0.0-20190827072141-edfb9018d271
 	github.com/stretchr/testify v1.4.0
 	go.mongodb.org/mongo-driver v1.2.1
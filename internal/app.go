@@ -1,46 +1,74 @@
 package internal
 
 import (
-	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"github.com/InVisionApp/go-health"
 	"github.com/InVisionApp/go-health/handlers"
 	protobufProto "github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/micro/go-micro"
 	"github.com/micro/go-plugins/client/selector/static"
 	"github.com/micro/go-plugins/wrapper/monitoring/prometheus"
-	awsWrapper "github.com/paysuper/paysuper-aws-manager"
 	"github.com/paysuper/paysuper-proto/go/billingpb"
-	"github.com/paysuper/paysuper-proto/go/reporterpb"
 	"github.com/paysuper/paysuper-reporter/internal/builder"
 	"github.com/paysuper/paysuper-reporter/internal/config"
 	"github.com/paysuper/paysuper-reporter/pkg"
 	reporterErrors "github.com/paysuper/paysuper-reporter/pkg/errors"
 	"github.com/paysuper/paysuper-reporter/pkg/proto"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/streadway/amqp"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.uber.org/zap"
 	rabbitmq "gopkg.in/ProtocolONE/rabbitmq.v1/pkg"
+	mongodb "gopkg.in/paysuper/paysuper-database-mongo.v2"
 	"io/ioutil"
 	"log"
 	"net/http"
 	"os"
+	"path/filepath"
 	"time"
 )
 
+var (
+	errFileReferenceMissing = errors.New("report file reference is missing in the post processing message")
+	errFileChecksumMismatch = errors.New("report file checksum does not match the uploaded file")
+)
+
 type Application struct {
-	cfg               *config.Config
-	log               *zap.Logger
-	s3                awsWrapper.AwsManagerInterface
-	s3Agreement       awsWrapper.AwsManagerInterface
-	centrifugo        CentrifugoInterface
-	documentGenerator DocumentGeneratorInterface
-	service           micro.Service
-	billing           billingpb.BillingService
-
-	generateReportBroker rabbitmq.BrokerInterface
-	postProcessBroker    rabbitmq.BrokerInterface
+	ctx        context.Context
+	cancel     context.CancelFunc
+	cfg        *config.Config
+	log        *zap.Logger
+	centrifugo CentrifugoInterface
+	renderers  *RendererRegistry
+	service    micro.Service
+	billing    billingpb.BillingService
+	db         mongodb.SourceInterface
+
+	reportFileRepository     ReportFileRepositoryInterface
+	legalHoldRepository      LegalHoldRepositoryInterface
+	erasureReceiptRepository ErasureReceiptRepositoryInterface
+
+	storages *StorageRouter
+
+	generateReportBroker      rabbitmq.BrokerInterface
+	generateInteractiveBroker rabbitmq.BrokerInterface
+	postProcessBroker         rabbitmq.BrokerInterface
+	deadLetterQueue           DeadLetterQueueInterface
+	retryQueue                RetryQueueInterface
+	retryPolicies             map[string]*RetryPolicy
+	consumers                 []ConsumerInterface
+	jobs                      *JobTracker
+	renderLimiter             *RenderLimiter
+	interactiveRenderLimiter  *RenderLimiter
+	retentionSweeper          *RetentionSweeper
+	downloadServer            *http.Server
+	metricsServer             *http.Server
 
 	fatalFn func(msg string, fields ...zap.Field)
 }
@@ -51,9 +79,12 @@ type appHealthCheck struct {
 
 func NewApplication() *Application {
 	app := &Application{}
+	app.ctx, app.cancel = context.WithCancel(context.Background())
 	app.initLogger()
 	app.initConfig()
-	app.initS3()
+	app.initDatabase()
+	app.initStorage()
+	app.initRetentionSweeper()
 	app.initCentrifugo()
 	app.initDocumentGenerator()
 	app.initMessageBroker()
@@ -83,9 +114,10 @@ func (app *Application) initHealth() {
 		app.fatalFn("Health check start failed", zap.Error(err))
 	}
 
-	app.log.Info("Health check listener started", zap.String("port", app.cfg.MetricsPort))
-
-	http.HandleFunc("/health", handlers.NewJSONHandlerFunc(h, nil))
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", handlers.NewJSONHandlerFunc(h, nil))
+	mux.Handle("/metrics", promhttp.Handler())
+	app.metricsServer = &http.Server{Addr: ":" + app.cfg.MetricsPort, Handler: mux}
 }
 
 func (app *Application) initLogger() {
@@ -115,29 +147,106 @@ func (app *Application) initConfig() {
 	zap.L().Info("Configuration parsed successfully...")
 }
 
-func (app *Application) initS3() {
-	var err error
+func (app *Application) initDatabase() {
+	db, err := mongodb.NewDatabase()
 
-	app.s3, err = awsWrapper.New()
 	if err != nil {
-		app.fatalFn("reports S3 initialization failed", zap.Error(err))
+		app.fatalFn("Database connection failed", zap.Error(err))
 	}
 
-	zap.L().Info("reports S3 initialization successfully...")
+	app.db = db
+	app.reportFileRepository = newReportFileRepository(db)
+	app.legalHoldRepository = newLegalHoldRepository(db)
+	app.erasureReceiptRepository = newErasureReceiptRepository(db)
+
+	if err = app.reportFileRepository.CreateIndexes(context.Background()); err != nil {
+		app.fatalFn("Database indexes creation failed", zap.Error(err))
+	}
+
+	zap.L().Info("Database initialization successfully...")
+}
+
+func (app *Application) initStorage() {
+	storage, err := newStorage(&app.cfg.Storage, &StorageBucket{
+		Name:        app.cfg.S3.BucketName,
+		AccessKeyId: app.cfg.S3.AccessKeyId,
+		SecretKey:   app.cfg.S3.SecretKey,
+		Region:      app.cfg.S3.Region,
+	})
 
-	awsOptions := []awsWrapper.Option{
-		awsWrapper.AccessKeyId(app.cfg.S3.AwsAccessKeyIdAgreement),
-		awsWrapper.SecretAccessKey(app.cfg.S3.AwsSecretAccessKeyAgreement),
-		awsWrapper.Region(app.cfg.S3.AwsRegionAgreement),
-		awsWrapper.Bucket(app.cfg.S3.AwsBucketAgreement),
+	if err != nil {
+		app.fatalFn("reports storage initialization failed", zap.Error(err))
 	}
-	app.s3Agreement, err = awsWrapper.New(awsOptions...)
+
+	agreementStorage, err := newStorage(&app.cfg.Storage, &StorageBucket{
+		Name:        app.cfg.S3.AwsBucketAgreement,
+		AccessKeyId: app.cfg.S3.AwsAccessKeyIdAgreement,
+		SecretKey:   app.cfg.S3.AwsSecretAccessKeyAgreement,
+		Region:      app.cfg.S3.AwsRegionAgreement,
+	})
 
 	if err != nil {
-		app.fatalFn("agreement S3 initialization failed", zap.Error(err))
+		app.fatalFn("agreement storage initialization failed", zap.Error(err))
 	}
 
-	zap.L().Info("agreement S3 initialization successfully...")
+	storages := map[string]StorageInterface{
+		pkg.StorageTargetReports:    storage,
+		pkg.StorageTargetAgreements: agreementStorage,
+	}
+
+	if app.cfg.Encryption.Enabled {
+		keys, err := newKeyProvider(&app.cfg.Encryption)
+
+		if err != nil {
+			app.fatalFn("encryption key provider initialization failed", zap.Error(err))
+		}
+
+		if app.cfg.Encryption.DownloadSecret == "" {
+			app.fatalFn("encryption download secret is empty")
+		}
+
+		for target, storage := range storages {
+			storages[target] = newEncryptedStorage(
+				storage,
+				keys,
+				app.cfg.Encryption.DownloadUrl,
+				app.cfg.Encryption.DownloadSecret,
+			)
+		}
+	}
+
+	app.storages, err = newStorageRouter(storages, app.cfg.Storage.Routes)
+
+	if err != nil {
+		app.fatalFn("storage routes initialization failed", zap.Error(err))
+	}
+
+	if app.cfg.Encryption.Enabled {
+		mux := http.NewServeMux()
+		mux.Handle(pkg.EncryptionDownloadPath, newDownloadHandler(app.storages, app.cfg.Encryption.DownloadSecret))
+		app.downloadServer = &http.Server{Addr: app.cfg.Encryption.DownloadAddr, Handler: mux}
+	}
+
+	zap.L().Info(
+		"Storage initialization successfully...",
+		zap.String("driver", app.cfg.Storage.Driver),
+		zap.Bool("encryption", app.cfg.Encryption.Enabled),
+	)
+}
+
+func (app *Application) initRetentionSweeper() {
+	app.retentionSweeper = newRetentionSweeper(
+		&app.cfg.Retention,
+		app.reportFileRepository,
+		app.legalHoldRepository,
+		app.storages,
+	)
+
+	zap.L().Info(
+		"Retention sweeper initialization successfully...",
+		zap.Int64("interval", app.cfg.Retention.SweepInterval),
+		zap.Bool("dry_run", app.cfg.Retention.DryRun),
+	)
 }
 
 func (app *Application) initCentrifugo() {
@@ -147,7 +256,25 @@ func (app *Application) initCentrifugo() {
 }
 
 func (app *Application) initDocumentGenerator() {
-	app.documentGenerator = newDocumentGenerator(&app.cfg.DG)
+	documentGenerator := newDocumentGenerator(&app.cfg.DG)
+	csvRenderer, err := newCsvRenderer(&app.cfg.Csv)
+
+	if err != nil {
+		app.fatalFn("CSV renderer initialization failed", zap.Error(err))
+	}
+
+	app.renderers = newRendererRegistry(app.cfg.Renderer.Default, app.cfg.Renderer.Routes)
+	app.renderers.Register(pkg.RendererJsReport, documentGenerator)
+	app.renderers.Register(pkg.RendererNative, newNativeRenderer(csvRenderer, newXlsxRenderer()))
+
+	if app.cfg.Renderer.HtmlPdfApiUrl != "" {
+		htmlPdfRenderer := newHtmlPdfRenderer(documentGenerator, app.cfg.Renderer.HtmlPdfApiUrl, app.cfg.DG.Timeout)
+		app.renderers.Register(pkg.RendererHtmlPdf, htmlPdfRenderer)
+	}
+
+	if err = app.renderers.Validate(); err != nil {
+		app.fatalFn("Renderers routing configuration is invalid", zap.Error(err))
+	}
 
 	zap.L().Info("Document generator initialization successfully...")
 }
@@ -165,13 +292,18 @@ func (app *Application) initMessageBroker() {
 	}
 
 	generateReportBroker.SetExchangeName(pkg.BrokerGenerateReportTopicName)
-	err = generateReportBroker.RegisterSubscriber(pkg.BrokerGenerateReportTopicName, app.ExecuteProcess)
+	generateInteractiveBroker, err := rabbitmq.NewBroker(app.cfg.BrokerAddress)
 
 	if err != nil {
-		app.fatalFn("Registration generate report subscriber function failed", zap.Error(err))
+		app.fatalFn(
+			"Creating interactive generate report broker failed",
+			zap.Error(err),
+			zap.String("DSN", app.cfg.BrokerAddress),
+		)
 		return
 	}
 
+	generateInteractiveBroker.SetExchangeName(pkg.BrokerGenerateReportInteractiveTopicName)
 	postProcessBroker, err := rabbitmq.NewBroker(app.cfg.BrokerAddress)
 
 	if err != nil {
@@ -184,15 +316,61 @@ func (app *Application) initMessageBroker() {
 	}
 
 	postProcessBroker.SetExchangeName(pkg.BrokerPostProcessTopicName)
-	err = postProcessBroker.RegisterSubscriber(pkg.BrokerPostProcessTopicName, app.ExecutePostProcess)
+
+	deadLetterQueue, err := newDeadLetterQueue(app.cfg.BrokerAddress)
 
 	if err != nil {
-		app.fatalFn("Registration post process subscriber function failed", zap.Error(err))
+		app.fatalFn(
+			"Creating dead letter queue failed",
+			zap.Error(err),
+			zap.String("DSN", app.cfg.BrokerAddress),
+		)
+		return
+	}
+
+	retryQueue, err := newRetryQueue(app.cfg.BrokerAddress)
+
+	if err != nil {
+		app.fatalFn(
+			"Creating retry queue failed",
+			zap.Error(err),
+			zap.String("DSN", app.cfg.BrokerAddress),
+		)
 		return
 	}
 
 	app.generateReportBroker = generateReportBroker
+	app.generateInteractiveBroker = generateInteractiveBroker
 	app.postProcessBroker = postProcessBroker
+	app.deadLetterQueue = deadLetterQueue
+	app.retryQueue = retryQueue
+	app.retryPolicies = newRetryPolicies(&app.cfg.Retry)
+	app.jobs = newJobTracker()
+	app.renderLimiter = newRenderLimiter(app.cfg.Worker.MaxFilesInMemory, app.cfg.Worker.MemoryLimit)
+	app.interactiveRenderLimiter = newRenderLimiter(app.cfg.Worker.InteractiveFilesInMemory, app.cfg.Worker.MemoryLimit)
+	app.consumers = []ConsumerInterface{
+		newConsumer(
+			app.cfg.BrokerAddress,
+			pkg.BrokerGenerateReportTopicName,
+			app.cfg.Worker.GenerateCount,
+			app.cfg.Worker.GeneratePrefetch,
+			app.consumeGenerateReport,
+		),
+		newConsumer(
+			app.cfg.BrokerAddress,
+			pkg.BrokerGenerateReportInteractiveTopicName,
+			app.cfg.Worker.InteractiveCount,
+			app.cfg.Worker.InteractivePrefetch,
+			app.consumeGenerateReport,
+		),
+		newConsumer(
+			app.cfg.BrokerAddress,
+			pkg.BrokerPostProcessTopicName,
+			app.cfg.Worker.PostProcessCount,
+			app.cfg.Worker.PostProcessPrefetch,
+			app.consumePostProcess,
+		),
+	}
 
 	zap.L().Info("Message brokers initialized successfully...")
 }
@@ -203,21 +381,29 @@ func (app *Application) Run() {
 		micro.Version(reporterpb.ServiceVersion),
 		micro.WrapHandler(prometheus.NewHandlerWrapper()),
 		micro.BeforeStart(func() error {
+			for _, consumer := range app.consumers {
+				consumer.Start()
+			}
+
+			app.retentionSweeper.Start(app.ctx)
+
 			go func() {
-				go func() {
-					err := app.generateReportBroker.Subscribe(nil)
+				if err := app.metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+					app.fatalFn("Health check listener start failed", zap.Error(err))
+				}
+			}()
+
+			app.log.Info("Health check listener started", zap.String("port", app.cfg.MetricsPort))
 
-					if err != nil {
-						app.fatalFn("Generate report subscriber start failed...", zap.Error(err))
+			if app.downloadServer != nil {
+				go func() {
+					if err := app.downloadServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+						app.fatalFn("Download server start failed", zap.Error(err))
 					}
 				}()
 
-				err := app.postProcessBroker.Subscribe(nil)
-
-				if err != nil {
-					app.fatalFn("Generate report subscriber start failed...", zap.Error(err))
-				}
-			}()
+				zap.L().Info("Download server started", zap.String("addr", app.downloadServer.Addr))
+			}
 
 			return nil
 		}),
@@ -252,6 +438,32 @@ func (app *Application) Run() {
 }
 
 func (app *Application) Stop() {
+	app.shutdown()
+
+	if app.retryQueue != nil {
+		if err := app.retryQueue.Close(); err != nil {
+			zap.L().Error("Retry queue close failed", zap.Error(err))
+		} else {
+			zap.L().Info("Retry queue connection closed")
+		}
+	}
+
+	if app.deadLetterQueue != nil {
+		if err := app.deadLetterQueue.Close(); err != nil {
+			zap.L().Error("Dead letter queue close failed", zap.Error(err))
+		} else {
+			zap.L().Info("Dead letter queue connection closed")
+		}
+	}
+
+	if app.db != nil {
+		if err := app.db.Close(); err != nil {
+			zap.L().Error("Database close failed", zap.Error(err))
+		} else {
+			zap.L().Info("Database connection closed")
+		}
+	}
+
 	if err := app.log.Sync(); err != nil {
 		app.fatalFn("Logger sync failed", zap.Error(err))
 	} else {
@@ -259,7 +471,101 @@ func (app *Application) Stop() {
 	}
 }
 
+// shutdown stops consuming of the new messages and waits for the report file jobs in progress. Jobs that
+// are not finished in the drain timeout are aborted and requeued.
+func (app *Application) shutdown() {
+	for _, consumer := range app.consumers {
+		consumer.Cancel()
+	}
+
+	if app.retentionSweeper != nil {
+		app.retentionSweeper.Stop()
+	}
+
+	if app.downloadServer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+
+		if err := app.downloadServer.Shutdown(ctx); err != nil {
+			zap.L().Error("Download server shutdown failed", zap.Error(err))
+		}
+
+		cancel()
+	}
+
+	if app.metricsServer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+
+		if err := app.metricsServer.Shutdown(ctx); err != nil {
+			zap.L().Error("Health check listener shutdown failed", zap.Error(err))
+		}
+
+		cancel()
+	}
+
+	if app.jobs != nil {
+		app.jobs.Stop()
+
+		if !app.jobs.Wait(time.Duration(app.cfg.Job.DrainTimeout) * time.Millisecond) {
+			zap.L().Warn("Report file jobs are not finished in the drain timeout, aborting", zap.Int("jobs", app.jobs.Count()))
+			app.cancel()
+
+			if !app.jobs.Wait(time.Duration(app.cfg.Job.AbortTimeout) * time.Millisecond) {
+				zap.L().Error("Report file jobs are not aborted in the timeout", zap.Int("jobs", app.jobs.Count()))
+			}
+		}
+
+		zap.L().Info("Report file jobs drained")
+	}
+
+	if app.cancel != nil {
+		app.cancel()
+	}
+
+	for _, consumer := range app.consumers {
+		if err := consumer.Close(); err != nil {
+			zap.L().Error("Broker consumer close failed", zap.Error(err))
+		}
+	}
+}
+
+func (app *Application) consumeGenerateReport(d amqp.Delivery) error {
+	payload := &reporterpb.ReportFile{}
+
+	if err := protobufProto.Unmarshal(d.Body, payload); err != nil {
+		return err
+	}
+
+	return app.ExecuteProcess(payload, d)
+}
+
+func (app *Application) consumePostProcess(d amqp.Delivery) error {
+	payload := &reporterpb.PostProcessRequest{}
+
+	if err := protobufProto.Unmarshal(d.Body, payload); err != nil {
+		return err
+	}
+
+	return app.ExecutePostProcess(payload, d)
+}
+
 func (app *Application) ExecuteProcess(payload *reporterpb.ReportFile, d amqp.Delivery) error {
+	topic, broker := app.getGenerateLane(payload)
+
+	if !app.jobs.Start() {
+		return app.requeue(broker, topic, payload, d, payload.Id)
+	}
+
+	defer app.jobs.Done()
+
+	ctx, cancel := getStageContext(app.ctx, app.cfg.Job.Timeout)
+	defer cancel()
+
+	if app.isJobCancelled(payload.Id, pkg.ReportFileStatusBuilding) {
+		return nil
+	}
+
+	app.setJobStatus(payload.Id, pkg.ReportFileStatusBuilding, nil, nil)
+
 	h := builder.NewBuilder(
 		app.service,
 		payload,
@@ -273,10 +579,21 @@ func (app *Application) ExecuteProcess(payload *reporterpb.ReportFile, d amqp.De
 			zap.Error(err),
 			zap.Any("payload", payload),
 		)
-		return app.getProcessResult(app.generateReportBroker, pkg.BrokerGenerateReportTopicName, payload, d)
+		return app.processFailed(
+			broker,
+			topic,
+			payload,
+			d,
+			payload.Id,
+			pkg.ReportFileStatusBuilding,
+			reporterErrors.ErrorHandlerNotFound,
+			err,
+		)
 	}
 
-	rawData, err := handler.Build()
+	buildCtx, buildCancel := getStageContext(ctx, app.cfg.Job.BuildTimeout)
+	rawData, err := handler.Build(buildCtx)
+	buildCancel()
 
 	if err != nil {
 		zap.L().Error(
@@ -284,18 +601,62 @@ func (app *Application) ExecuteProcess(payload *reporterpb.ReportFile, d amqp.De
 			zap.Error(err),
 			zap.Any("payload", payload),
 		)
-		return app.getProcessResult(app.generateReportBroker, pkg.BrokerGenerateReportTopicName, payload, d)
+		return app.processFailed(
+			broker,
+			topic,
+			payload,
+			d,
+			payload.Id,
+			pkg.ReportFileStatusBuilding,
+			reporterErrors.ErrorDocumentBuildFailed,
+			err,
+		)
 	}
 
+	if app.isJobCancelled(payload.Id, pkg.ReportFileStatusRendering) {
+		return nil
+	}
+
+	app.setJobStatus(payload.Id, pkg.ReportFileStatusRendering, nil, nil)
+
 	fileRequest := &proto.GeneratorPayload{
 		Template: &proto.GeneratorTemplate{
 			ShortId: payload.Template,
 			Recipe:  reportFileRecipes[payload.FileType],
 		},
-		Data: rawData,
+		Data:       rawData,
+		ReportType: payload.ReportType,
+		FileType:   payload.FileType,
 	}
 
-	file, err := app.documentGenerator.Render(fileRequest)
+	renderLimiter := app.getRenderLimiter(topic)
+	renderCtx, renderCancel := getStageContext(ctx, app.cfg.Job.RenderTimeout)
+	err = renderLimiter.Acquire(renderCtx)
+
+	if err != nil {
+		renderCancel()
+		zap.L().Error(
+			"Unable to acquire memory for the report rendering",
+			zap.Error(err),
+			zap.Any("payload", payload),
+		)
+		return app.processFailed(
+			broker,
+			topic,
+			payload,
+			d,
+			payload.Id,
+			pkg.ReportFileStatusRendering,
+			reporterErrors.ErrorDocumentGeneratorRender,
+			err,
+		)
+	}
+
+	// The rendered file is held in memory until it is uploaded to the storage
+	defer renderLimiter.Release()
+
+	file, err := app.renderers.Get(payload.ReportType, payload.FileType).Render(renderCtx, fileRequest)
+	renderCancel()
 
 	if err != nil {
 		zap.L().Error(
@@ -303,7 +664,16 @@ func (app *Application) ExecuteProcess(payload *reporterpb.ReportFile, d amqp.De
 			zap.Error(err),
 			zap.Any("payload", payload),
 		)
-		return app.getProcessResult(app.generateReportBroker, pkg.BrokerGenerateReportTopicName, payload, d)
+		return app.processFailed(
+			broker,
+			topic,
+			payload,
+			d,
+			payload.Id,
+			pkg.ReportFileStatusRendering,
+			reporterErrors.ErrorDocumentGeneratorRender,
+			err,
+		)
 	}
 
 	fileName := fmt.Sprintf(reporterpb.FileMask, payload.UserId, payload.Id, payload.FileType)
@@ -316,7 +686,16 @@ func (app *Application) ExecuteProcess(payload *reporterpb.ReportFile, d amqp.De
 				"Handler not implement method to get agreement name",
 				zap.Any("payload", payload),
 			)
-			return app.getProcessResult(app.generateReportBroker, pkg.BrokerGenerateReportTopicName, payload, d)
+			return app.processFailed(
+				broker,
+				topic,
+				payload,
+				d,
+				payload.Id,
+				pkg.ReportFileStatusRendering,
+				reporterErrors.ErrorAgreementNameFailed,
+				nil,
+			)
 		}
 
 		fileName, err = tHandler.GetAgreementName(payload.FileType)
@@ -327,11 +706,26 @@ func (app *Application) ExecuteProcess(payload *reporterpb.ReportFile, d amqp.De
 				zap.Error(err),
 				zap.Any("payload", payload),
 			)
-			return app.getProcessResult(app.generateReportBroker, pkg.BrokerGenerateReportTopicName, payload, d)
+			return app.processFailed(
+				broker,
+				topic,
+				payload,
+				d,
+				payload.Id,
+				pkg.ReportFileStatusRendering,
+				reporterErrors.ErrorAgreementNameFailed,
+				err,
+			)
 		}
 	}
 
-	filePath := os.TempDir() + string(os.PathSeparator) + fileName
+	if app.isJobCancelled(payload.Id, pkg.ReportFileStatusUploading) {
+		return nil
+	}
+
+	filePath := getReportTempFilePath(payload.Id, payload.FileType)
+	defer removeTempFile(filePath)
+
 	err = ioutil.WriteFile(filePath, file, 0644)
 
 	if err != nil {
@@ -340,7 +734,16 @@ func (app *Application) ExecuteProcess(payload *reporterpb.ReportFile, d amqp.De
 			zap.Error(err),
 			zap.Any("payload", payload),
 		)
-		return app.getProcessResult(app.generateReportBroker, pkg.BrokerGenerateReportTopicName, payload, d)
+		return app.processFailed(
+			broker,
+			topic,
+			payload,
+			d,
+			payload.Id,
+			pkg.ReportFileStatusRendering,
+			reporterErrors.ErrorTemporaryFileFailed,
+			err,
+		)
 	}
 
 	retentionTime := app.cfg.DocumentRetentionTime
@@ -349,34 +752,70 @@ func (app *Application) ExecuteProcess(payload *reporterpb.ReportFile, d amqp.De
 		retentionTime = int64(payload.RetentionTime)
 	}
 
-	awsManager := app.s3
-	in := &awsWrapper.UploadInput{
-		Body:     bytes.NewReader(file),
-		FileName: fileName,
-	}
-	ctx, _ := context.WithTimeout(context.Background(), time.Second*10)
+	app.setJobStatus(payload.Id, pkg.ReportFileStatusUploading, nil, nil)
 
-	if payload.ReportType == reporterpb.ReportTypeAgreement {
-		awsManager = app.s3Agreement
-	} else {
-		in.Expires = time.Now().Add(time.Duration(retentionTime) * time.Second)
-	}
-
-	_, err = awsManager.Upload(ctx, in)
+	dst := app.storages.Resolve(payload, fileName, time.Duration(retentionTime)*time.Second, time.Now())
+	uploadCtx, uploadCancel := getStageContext(ctx, app.cfg.Job.UploadTimeout)
+	err = dst.Storage.Upload(uploadCtx, dst.Key, file, dst.Options)
+	uploadCancel()
 
 	if err != nil {
 		zap.L().Error(
-			"Unable to upload report to the S3",
+			"Unable to upload report to the storage",
 			zap.Error(err),
 			zap.Any("payload", payload),
 		)
-		return app.getProcessResult(app.generateReportBroker, pkg.BrokerGenerateReportTopicName, payload, d)
+		return app.processFailed(
+			broker,
+			topic,
+			payload,
+			d,
+			payload.Id,
+			pkg.ReportFileStatusUploading,
+			reporterErrors.ErrorStorageUploadFailed,
+			err,
+		)
+	}
+
+	bucket := dst.Storage.Bucket()
+
+	var expiresAt *time.Time
+
+	if !dst.Options.Expires.IsZero() {
+		expiresAt = &dst.Options.Expires
 	}
 
+	// The file is recorded only to the active job, the upload of the job cancelled or erased meanwhile is deleted,
+	// so the erasure finds either the recorded file or nothing left in the storage
+	err = app.setJobFile(payload.Id, bucket, dst.Key, reportFileContentTypes[payload.FileType], int64(len(file)), expiresAt)
+
+	if err == mongo.ErrNoDocuments {
+		zap.L().Info("Report file job stopped, uploaded file deleted", zap.String("id", payload.Id))
+		app.deleteJobFile(bucket, payload.ReportType, dst.Key)
+		return nil
+	}
+
+	// The file not recorded to the job can be found neither by the retention nor by the erasure
+	if err != nil {
+		app.deleteJobFile(bucket, payload.ReportType, dst.Key)
+		return app.processFailed(
+			broker,
+			topic,
+			payload,
+			d,
+			payload.Id,
+			pkg.ReportFileStatusUploading,
+			reporterErrors.ErrorDatabaseQueryFailed,
+			err,
+		)
+	}
+
+	app.setJobStatus(payload.Id, pkg.ReportFileStatusUploaded, nil, nil)
+
 	if payload.SendNotification {
 		msg := map[string]string{"file_name": payload.Id + "." + payload.FileType}
 		ch := fmt.Sprintf(app.cfg.CentrifugoConfig.UserChannel, payload.MerchantId)
-		err = app.centrifugo.Publish(ch, msg)
+		err = app.centrifugo.Publish(ctx, ch, msg)
 
 		if err != nil {
 			zap.L().Error(
@@ -384,7 +823,16 @@ func (app *Application) ExecuteProcess(payload *reporterpb.ReportFile, d amqp.De
 				zap.Error(err),
 				zap.Any("payload", payload),
 			)
-			return app.getProcessResult(app.generateReportBroker, pkg.BrokerGenerateReportTopicName, payload, d)
+			return app.processFailed(
+				broker,
+				topic,
+				payload,
+				d,
+				payload.Id,
+				pkg.ReportFileStatusUploaded,
+				reporterErrors.ErrorCentrifugoNotificationFailed,
+				err,
+			)
 		}
 	}
 
@@ -396,14 +844,29 @@ func (app *Application) ExecuteProcess(payload *reporterpb.ReportFile, d amqp.De
 			zap.Error(err),
 			zap.Any("payload", payload),
 		)
-		return app.getProcessResult(app.generateReportBroker, pkg.BrokerGenerateReportTopicName, payload, d)
+		return app.processFailed(
+			broker,
+			topic,
+			payload,
+			d,
+			payload.Id,
+			pkg.ReportFileStatusUploaded,
+			reporterErrors.ErrorTemporaryFileFailed,
+			err,
+		)
 	}
 
+	checksum := sha256.Sum256(file)
 	postProcessData := &reporterpb.PostProcessRequest{
 		ReportFile:    payload,
-		FileName:      fileName,
+		FileName:      dst.Key,
 		RetentionTime: retentionTime,
-		File:          file,
+		File: &reporterpb.FileReference{
+			Bucket:   bucket,
+			Key:      dst.Key,
+			Checksum: hex.EncodeToString(checksum[:]),
+			Size:     int64(len(file)),
+		},
 	}
 	amqpHeaders := amqp.Table{
 		"x-retry-count": int32(0),
@@ -411,20 +874,40 @@ func (app *Application) ExecuteProcess(payload *reporterpb.ReportFile, d amqp.De
 	err = app.postProcessBroker.Publish(pkg.BrokerPostProcessTopicName, postProcessData, amqpHeaders)
 
 	if err != nil {
-		postProcessData.File = nil
 		zap.L().Error(
 			"Publish message to post process broker failed",
 			zap.Error(err),
 			zap.Any("data", postProcessData),
 		)
-		return app.getProcessResult(app.generateReportBroker, pkg.BrokerGenerateReportTopicName, payload, d)
+		return app.processFailed(
+			broker,
+			topic,
+			payload,
+			d,
+			payload.Id,
+			pkg.ReportFileStatusUploaded,
+			reporterErrors.ErrorMessageBrokerFailed,
+			err,
+		)
 	}
 
 	return nil
 }
 
 func (app *Application) ExecutePostProcess(payload *reporterpb.PostProcessRequest, d amqp.Delivery) error {
-	log.Println("2")
+	if !app.jobs.Start() {
+		return app.requeue(app.postProcessBroker, pkg.BrokerPostProcessTopicName, payload, d, payload.ReportFile.Id)
+	}
+
+	defer app.jobs.Done()
+
+	if app.isJobCancelled(payload.ReportFile.Id, pkg.ReportFileStatusPostProcessing) {
+		app.deleteJobFile(getFileReferenceBucket(payload.File), payload.ReportFile.ReportType, payload.FileName)
+		return nil
+	}
+
+	app.setJobStatus(payload.ReportFile.Id, pkg.ReportFileStatusPostProcessing, nil, nil)
+
 	h := builder.NewBuilder(
 		app.service,
 		payload.ReportFile,
@@ -433,68 +916,405 @@ func (app *Application) ExecutePostProcess(payload *reporterpb.PostProcessReques
 	handler, err := h.GetBuilder()
 
 	if err != nil {
-		payload.File = nil
 		zap.L().Error(
 			"Unable to get handler",
 			zap.Error(err),
 			zap.Any("payload", payload),
 		)
-		return app.getProcessResult(app.postProcessBroker, pkg.BrokerPostProcessTopicName, payload, d)
+		return app.processFailed(
+			app.postProcessBroker,
+			pkg.BrokerPostProcessTopicName,
+			payload,
+			d,
+			payload.ReportFile.Id,
+			pkg.ReportFileStatusPostProcessing,
+			reporterErrors.ErrorHandlerNotFound,
+			err,
+		)
 	}
 
-	ctx, _ := context.WithTimeout(context.Background(), time.Minute*2)
-	err = handler.PostProcess(ctx, payload.ReportFile.Id, payload.FileName, payload.RetentionTime, payload.File)
+	ctx, cancel := getStageContext(app.ctx, app.cfg.Job.PostProcessTimeout)
+	defer cancel()
+
+	err = handler.PostProcess(
+		ctx,
+		payload.ReportFile.Id,
+		payload.FileName,
+		payload.RetentionTime,
+		app.getFileLoader(payload),
+	)
 
 	if err != nil {
-		payload.File = nil
 		zap.L().Error(
 			"PostProcess execution error",
 			zap.Error(err),
 			zap.Any("payload", payload),
 		)
-		return app.getProcessResult(app.postProcessBroker, pkg.BrokerPostProcessTopicName, payload, d)
+		return app.processFailed(
+			app.postProcessBroker,
+			pkg.BrokerPostProcessTopicName,
+			payload,
+			d,
+			payload.ReportFile.Id,
+			pkg.ReportFileStatusPostProcessing,
+			reporterErrors.ErrorPostProcessFailed,
+			err,
+		)
 	}
 
+	app.setJobStatus(payload.ReportFile.Id, pkg.ReportFileStatusCompleted, nil, nil)
+
 	return nil
 }
 
+// getStageContext limits the context by the stage deadline in milliseconds, zero deadline means no limit.
+func getStageContext(ctx context.Context, timeout int64) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, time.Duration(timeout)*time.Millisecond)
+}
+
+// setJobStatus records the job state transition. Failures of the job store are logged
+// and never interrupt the report file generation.
+func (app *Application) setJobStatus(
+	id, status string,
+	errMsg *reporterpb.ResponseErrorMessage,
+	err error,
+) *proto.ReportFileJobError {
+	var jobErr *proto.ReportFileJobError
+
+	if errMsg != nil {
+		jobErr = &proto.ReportFileJobError{Code: errMsg.Code, Message: errMsg.Message}
+
+		if err != nil {
+			jobErr.Details = err.Error()
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := app.reportFileRepository.SetStatus(ctx, id, status, jobErr); err != nil {
+		zap.L().Error(
+			"Unable to update report file job status",
+			zap.Error(err),
+			zap.String("id", id),
+			zap.String("status", status),
+		)
+	}
+
+	return jobErr
+}
+
+// isJobCancelled reports whether the report file job has been cancelled and the processing must be stopped
+// before the given stage.
+func (app *Application) isJobCancelled(id, stage string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	job, err := app.reportFileRepository.GetById(ctx, id)
+
+	if err != nil || (job.Status != pkg.ReportFileStatusCancelled && job.Status != pkg.ReportFileStatusErased) {
+		return false
+	}
+
+	zap.L().Info("Report file job cancelled, processing stopped", zap.String("id", id), zap.String("stage", stage))
+
+	return true
+}
+
+// getFileLoader returns the loader of the rendered report file referenced by the post processing message.
+// The content is verified by the checksum calculated before the upload. The messages published by the previous
+// release carry the content instead of the reference, it is returned as is.
+func (app *Application) getFileLoader(payload *reporterpb.PostProcessRequest) builder.FileLoader {
+	reportType, ref := payload.ReportFile.ReportType, payload.File
+
+	return func(ctx context.Context) ([]byte, error) {
+		if ref == nil {
+			if len(payload.FileContent) > 0 {
+				return payload.FileContent, nil
+			}
+
+			return nil, errFileReferenceMissing
+		}
+
+		if ref.Bucket == "" {
+			return nil, errFileReferenceMissing
+		}
+
+		client, err := app.storages.GetStorage(ref.Bucket, reportType)
+
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := client.Get(ctx, ref.Key)
+
+		if err != nil {
+			return nil, err
+		}
+
+		checksum := sha256.Sum256(content)
+
+		if hex.EncodeToString(checksum[:]) != ref.Checksum {
+			return nil, errFileChecksumMismatch
+		}
+
+		return content, nil
+	}
+}
+
+// deleteJobFile removes the uploaded report file from the storage of the bucket, the empty bucket means the
+// storage routed by the report type.
+func (app *Application) deleteJobFile(bucket, reportType, fileName string) {
+	client, err := app.storages.GetStorage(bucket, reportType)
+
+	if err != nil {
+		zap.L().Error(
+			"Unable to delete report file from the storage",
+			zap.Error(err),
+			zap.String("bucket", bucket),
+			zap.String("file_name", fileName),
+		)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := client.Delete(ctx, fileName); err != nil {
+		zap.L().Error(
+			"Unable to delete report file from the storage",
+			zap.Error(err),
+			zap.String("bucket", bucket),
+			zap.String("file_name", fileName),
+			zap.String("report_type", reportType),
+		)
+	}
+}
+
+// setJobFile records the uploaded file to the job. Failures of the job store are logged, mongo.ErrNoDocuments
+// is returned when the job has been stopped.
+func (app *Application) setJobFile(id, bucket, fileName, contentType string, size int64, expiresAt *time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := app.reportFileRepository.SetFile(ctx, id, bucket, fileName, contentType, size, expiresAt)
+
+	if err != nil && err != mongo.ErrNoDocuments {
+		zap.L().Error(
+			"Unable to update report file job file info",
+			zap.Error(err),
+			zap.String("id", id),
+			zap.String("file_name", fileName),
+		)
+	}
+
+	return err
+}
+
+func getFileReferenceBucket(ref *reporterpb.FileReference) string {
+	if ref == nil {
+		return ""
+	}
+
+	return ref.Bucket
+}
+
+// processFailed marks the report file job as retrying on the given stage and schedules the message for the next
+// attempt, or marks the job as failed and moves the message to the dead letter queue when all attempts are exhausted.
+func (app *Application) processFailed(
+	broker rabbitmq.BrokerInterface,
+	topic string,
+	message protobufProto.Message,
+	d amqp.Delivery,
+	fileId, stage string,
+	errMsg *reporterpb.ResponseErrorMessage,
+	err error,
+) error {
+	if app.ctx.Err() != nil {
+		// The job is interrupted by the shutdown, so the attempt is not counted
+		app.setJobStatus(fileId, pkg.ReportFileStatusQueued, nil, nil)
+		return app.requeue(broker, topic, message, d, fileId)
+	}
+
+	// The job is failed only when all attempts are exhausted, the files of the failed jobs are purged by the retention
+	status := pkg.ReportFileStatusRetrying
+
+	if getDeliveryRetryCount(d) >= app.retryPolicies[topic].MaxCount {
+		status = pkg.ReportFileStatusFailed
+	}
+
+	jobErr := app.setJobStatus(fileId, status, errMsg, err)
+	return app.getProcessResult(broker, topic, message, d, fileId, stage, jobErr)
+}
+
+// requeue returns the message not processed because of the shutdown to the broker with the same retry count.
+func (app *Application) requeue(
+	broker rabbitmq.BrokerInterface,
+	topic string,
+	message protobufProto.Message,
+	d amqp.Delivery,
+	fileId string,
+) error {
+	amqpHeaders := amqp.Table{
+		"x-retry-count": getDeliveryRetryCount(d),
+	}
+
+	// The message is not acknowledged when the publish failed, so the job is not lost
+	if err := broker.Publish(topic, message, amqpHeaders); err != nil {
+		zap.L().Error(
+			"Requeue message of the interrupted job failed, message will be redelivered by the broker",
+			zap.Error(err),
+			zap.String("topic", topic),
+			zap.String("file_id", fileId),
+		)
+		return errMessageRedelivery
+	}
+
+	zap.L().Info("Message of the interrupted job requeued", zap.String("topic", topic), zap.String("file_id", fileId))
+
+	return nil
+}
+
+// getDeliveryRetryCount returns the count of the retries of the message, the header of the unexpected type
+// is ignored.
+func getDeliveryRetryCount(d amqp.Delivery) int32 {
+	if v, ok := d.Headers[rabbitmq.BrokerMessageRetryCountHeader].(int32); ok {
+		return v
+	}
+
+	return 0
+}
+
+// getReportTempFilePath returns the path of the temporary file of the job, the path is built from the job
+// identifier so the file can be found and removed by the erasure of the merchant report files.
+func getReportTempFilePath(id, fileType string) string {
+	return filepath.Join(os.TempDir(), fmt.Sprintf(pkg.ReportTempFileMask, id, fileType))
+}
+
+// removeTempFile removes the temporary report file if it has been left by the interrupted job.
+func removeTempFile(filePath string) {
+	if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+		zap.L().Error("Unable to delete temporary file", zap.Error(err), zap.String("path", filePath))
+	}
+}
+
 func (app *Application) getProcessResult(
 	broker rabbitmq.BrokerInterface,
 	topic string,
 	message protobufProto.Message,
 	d amqp.Delivery,
+	fileId, stage string,
+	jobErr *proto.ReportFileJobError,
 ) error {
-	retryCount := int32(0)
+	retryCount := getDeliveryRetryCount(d)
+	policy := app.retryPolicies[topic]
 
-	if v, ok := d.Headers[rabbitmq.BrokerMessageRetryCountHeader]; ok {
-		retryCount = v.(int32)
+	if retryCount >= policy.MaxCount {
+		// The message is not acknowledged when it has not reached the dead letter queue, so it is not lost
+		if err := app.sendToDeadLetter(topic, message, fileId, stage, retryCount, jobErr); err != nil {
+			return errMessageRedelivery
+		}
+
+		return nil
 	}
 
-	if retryCount >= pkg.BrokerMessageRetryMaxCount {
+	delay := policy.Delay(retryCount)
+	err := app.retryQueue.Publish(topic, message, retryCount+1, delay)
+
+	if err == nil {
 		return nil
 	}
 
+	zap.L().Error(
+		"Publish message to retry queue failed, message will be requeued without delay",
+		zap.Error(err),
+		zap.String("topic", topic),
+		zap.String("file_id", fileId),
+		zap.Duration("delay", delay),
+	)
+
 	amqpHeaders := amqp.Table{
 		"x-retry-count": retryCount + 1,
 	}
-	err := broker.Publish(topic, message, amqpHeaders)
+	err = broker.Publish(topic, message, amqpHeaders)
 
 	if err != nil {
 		zap.L().Error(
-			"ReQueue message to broker failed",
+			"ReQueue message to broker failed, message will be redelivered by the broker",
 			zap.Error(err),
 			zap.String("topic", topic),
 			zap.Any("message", message),
 			zap.Any("headers", amqpHeaders),
 		)
-		return nil
+		return errMessageRedelivery
+	}
+
+	return nil
+}
+
+func (app *Application) sendToDeadLetter(
+	topic string,
+	message protobufProto.Message,
+	fileId, stage string,
+	retryCount int32,
+	jobErr *proto.ReportFileJobError,
+) error {
+	payload, err := protobufProto.Marshal(message)
+
+	if err != nil {
+		zap.L().Error(
+			"Unable to marshal message for the dead letter queue",
+			zap.Error(err),
+			zap.String("topic", topic),
+			zap.String("file_id", fileId),
+		)
+		return err
 	}
 
+	msg := &reporterpb.DeadLetterMessage{
+		Topic:      topic,
+		Stage:      stage,
+		FileId:     fileId,
+		RetryCount: retryCount,
+		Payload:    payload,
+		CreatedAt:  ptypes.TimestampNow(),
+	}
+
+	if jobErr != nil {
+		msg.Error = &reporterpb.ResponseErrorMessage{Code: jobErr.Code, Message: jobErr.Message}
+		msg.ErrorDetails = jobErr.Details
+	}
+
+	if err = app.deadLetterQueue.Publish(msg); err != nil {
+		zap.L().Error(
+			"Publish message to the dead letter queue failed",
+			zap.Error(err),
+			zap.String("topic", topic),
+			zap.String("file_id", fileId),
+			zap.String("stage", stage),
+		)
+		return err
+	}
+
+	zap.L().Warn(
+		"Message moved to the dead letter queue",
+		zap.String("topic", topic),
+		zap.String("file_id", fileId),
+		zap.String("stage", stage),
+		zap.Int32("retry_count", retryCount),
+	)
+
 	return nil
 }
 
 func (c *appHealthCheck) Status() (interface{}, error) {
-	ctx, _ := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
 	info, err := c.centrifugo.Info(ctx)
 
 	if err != nil {
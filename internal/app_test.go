@@ -2,19 +2,25 @@ package internal
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
-	"github.com/aws/aws-sdk-go/service/s3/s3manager"
-	awsWrapper "github.com/paysuper/paysuper-aws-manager"
-	awsWrapperMocks "github.com/paysuper/paysuper-aws-manager/pkg/mocks"
-	reporterPkg "github.com/paysuper/paysuper-proto/go/reporterpb"
+	"errors"
 	"github.com/paysuper/paysuper-reporter/internal/config"
 	"github.com/paysuper/paysuper-reporter/internal/mocks"
+	"github.com/paysuper/paysuper-reporter/pkg"
+	reporterErrors "github.com/paysuper/paysuper-reporter/pkg/errors"
+	"github.com/paysuper/paysuper-reporter/pkg/proto"
+	reporterPkg "github.com/paysuper/paysuper-reporter/pkg/reporterpb"
 	"github.com/streadway/amqp"
 	"github.com/stretchr/testify/assert"
 	mock2 "github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/mongo"
+	rabbitmq "gopkg.in/ProtocolONE/rabbitmq.v1/pkg"
 	rabbitmqMock "gopkg.in/ProtocolONE/rabbitmq.v1/pkg/mocks"
 	"testing"
+	"time"
 )
 
 type ApplicationTestSuite struct {
@@ -27,14 +33,11 @@ func Test_Application(t *testing.T) {
 }
 
 func (suite *ApplicationTestSuite) SetupTest() {
-	awsManagerMock := &awsWrapperMocks.AwsManagerInterface{}
-	awsManagerMock.On("Upload", mock2.Anything, mock2.Anything, mock2.Anything).Return(&s3manager.UploadOutput{}, nil)
-
 	centrifugoMock := &mocks.CentrifugoInterface{}
-	centrifugoMock.On("Publish", mock2.Anything, mock2.Anything).Return(nil, nil)
+	centrifugoMock.On("Publish", mock2.Anything, mock2.Anything, mock2.Anything).Return(nil, nil)
 
 	documentGeneratorMock := &mocks.DocumentGeneratorInterface{}
-	documentGeneratorMock.On("Render", mock2.Anything).Return([]byte("agreement file content"), nil)
+	documentGeneratorMock.On("Render", mock2.Anything, mock2.Anything).Return([]byte("agreement file content"), nil)
 
 	brokerMock := &rabbitmqMock.BrokerInterface{}
 	brokerMock.On("Publish", mock2.Anything, mock2.Anything, mock2.Anything).Return(nil, nil)
@@ -42,13 +45,40 @@ func (suite *ApplicationTestSuite) SetupTest() {
 	brokerMock.On("SetExchangeName", mock2.Anything).Return(nil)
 	brokerMock.On("Subscribe", mock2.Anything).Return(nil, nil)
 
+	reportFileRepositoryMock := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepositoryMock.On("SetStatus", mock2.Anything, mock2.Anything, mock2.Anything, mock2.Anything).Return(nil)
+	reportFileRepositoryMock.On("GetById", mock2.Anything, mock2.Anything).
+		Return(&proto.ReportFileJob{Status: pkg.ReportFileStatusQueued}, nil)
+	reportFileRepositoryMock.On("SetFile", mock2.Anything, mock2.Anything, mock2.Anything, mock2.Anything, mock2.Anything, mock2.Anything, mock2.Anything).Return(nil)
+
+	retryQueueMock := &mocks.RetryQueueInterface{}
+	retryQueueMock.On("Publish", mock2.Anything, mock2.Anything, mock2.Anything, mock2.Anything).Return(nil)
+
+	storageMock := &mocks.StorageInterface{}
+	storageMock.On("Upload", mock2.Anything, mock2.Anything, mock2.Anything, mock2.Anything).Return(nil)
+	storageMock.On("Bucket").Return("reports")
+
+	agreementStorageMock := &mocks.StorageInterface{}
+	agreementStorageMock.On("Upload", mock2.Anything, mock2.Anything, mock2.Anything, mock2.Anything).Return(nil)
+	agreementStorageMock.On("Bucket").Return("agreements")
+
+	renderers := newRendererRegistry(pkg.RendererJsReport, nil)
+	renderers.Register(pkg.RendererJsReport, documentGeneratorMock)
+
 	suite.dummyApp = &Application{
-		s3:                   awsManagerMock,
-		s3Agreement:          awsManagerMock,
-		centrifugo:           centrifugoMock,
-		documentGenerator:    documentGeneratorMock,
-		generateReportBroker: brokerMock,
-		postProcessBroker:    brokerMock,
+		ctx:                       context.Background(),
+		storages:                  suite.getStorageRouter(storageMock, agreementStorageMock),
+		centrifugo:                centrifugoMock,
+		renderers:                 renderers,
+		generateReportBroker:      brokerMock,
+		generateInteractiveBroker: brokerMock,
+		postProcessBroker:         brokerMock,
+		reportFileRepository:      reportFileRepositoryMock,
+		retryQueue:                retryQueueMock,
+		jobs:                      newJobTracker(),
+		renderLimiter:             newRenderLimiter(1, 0),
+		interactiveRenderLimiter:  newRenderLimiter(1, 0),
+		retryPolicies:             newRetryPolicies(&config.RetryConfig{GenerateMaxCount: 10, PostProcessMaxCount: 10}),
 		cfg: &config.Config{
 			S3:               config.S3Config{},
 			DG:               config.DocumentGeneratorConfig{},
@@ -59,21 +89,29 @@ func (suite *ApplicationTestSuite) SetupTest() {
 
 func (suite *ApplicationTestSuite) TearDownTest() {}
 
+func (suite *ApplicationTestSuite) getStorageRouter(storage, agreementStorage StorageInterface) *StorageRouter {
+	storages := map[string]StorageInterface{
+		pkg.StorageTargetReports:    storage,
+		pkg.StorageTargetAgreements: agreementStorage,
+	}
+	routes := config.StorageRoutes{
+		{ReportType: reporterPkg.ReportTypeAgreement, Target: pkg.StorageTargetAgreements, Expiry: pkg.StorageExpiryNone},
+	}
+	router, err := newStorageRouter(storages, routes)
+	assert.NoError(suite.T(), err)
+
+	return router
+}
+
 func (suite *ApplicationTestSuite) TestApplication_ExecuteProcess_Agreement_Ok() {
 	fileName := ""
 
-	awsUploadMockFn := func(
-		ctx context.Context,
-		in *awsWrapper.UploadInput,
-		opts ...func(*s3manager.Uploader),
-	) *s3manager.UploadOutput {
-		fileName = in.FileName
-		return &s3manager.UploadOutput{}
-	}
-
-	awsManagerMock := &awsWrapperMocks.AwsManagerInterface{}
-	awsManagerMock.On("Upload", mock2.Anything, mock2.Anything, mock2.Anything).Return(awsUploadMockFn, nil)
-	suite.dummyApp.s3Agreement = awsManagerMock
+	agreementStorageMock := &mocks.StorageInterface{}
+	agreementStorageMock.On("Upload", mock2.Anything, mock2.Anything, mock2.Anything, &proto.StorageUploadOptions{Acl: pkg.StorageAclPrivate}).
+		Run(func(args mock2.Arguments) { fileName = args.String(1) }).
+		Return(nil)
+	agreementStorageMock.On("Bucket").Return("agreements")
+	suite.dummyApp.storages.storages[pkg.StorageTargetAgreements] = agreementStorageMock
 
 	params := map[string]interface{}{
 		reporterPkg.RequestParameterAgreementNumber:             "123456-AA-7890",
@@ -136,7 +174,539 @@ func (suite *ApplicationTestSuite) TestApplication_ExecuteProcess_Agreement_Ok()
 		Params:           b,
 		SendNotification: false,
 	}
+
+	postProcessBrokerMock := &rabbitmqMock.BrokerInterface{}
+	postProcessBrokerMock.On("Publish", mock2.Anything, mock2.Anything, mock2.Anything).Return(nil)
+	suite.dummyApp.postProcessBroker = postProcessBrokerMock
+
 	err = suite.dummyApp.ExecuteProcess(payload, amqp.Delivery{})
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), fileName, "License Agreement_Company Name_#123456-AA-7890.pdf")
+
+	checksum := sha256.Sum256([]byte("agreement file content"))
+	postProcessBrokerMock.AssertCalled(
+		suite.T(),
+		"Publish",
+		pkg.BrokerPostProcessTopicName,
+		&reporterPkg.PostProcessRequest{
+			ReportFile: payload,
+			FileName:   fileName,
+			File: &reporterPkg.FileReference{
+				Bucket:   "agreements",
+				Key:      fileName,
+				Checksum: hex.EncodeToString(checksum[:]),
+				Size:     int64(len("agreement file content")),
+			},
+		},
+		mock2.Anything,
+	)
+}
+
+func (suite *ApplicationTestSuite) TestApplication_ExecuteProcess_Error_Render_JobRetrying() {
+	documentGeneratorMock := &mocks.DocumentGeneratorInterface{}
+	documentGeneratorMock.On("Render", mock2.Anything, mock2.Anything).Return(nil, errors.New("render error"))
+	suite.dummyApp.renderers.Register(pkg.RendererJsReport, documentGeneratorMock)
+
+	reportFileRepositoryMock := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepositoryMock.On("SetStatus", mock2.Anything, mock2.Anything, mock2.Anything, mock2.Anything).Return(nil)
+	reportFileRepositoryMock.On("GetById", mock2.Anything, mock2.Anything).
+		Return(&proto.ReportFileJob{Status: pkg.ReportFileStatusQueued}, nil)
+	suite.dummyApp.reportFileRepository = reportFileRepositoryMock
+
+	params, err := json.Marshal(map[string]interface{}{reporterPkg.RequestParameterAgreementPSRate: []interface{}{}})
+	assert.NoError(suite.T(), err)
+
+	payload := &reporterPkg.ReportFile{
+		Id:         "ffffffffffffffffffffffff",
+		MerchantId: "ffffffffffffffffffffffff",
+		ReportType: reporterPkg.ReportTypeAgreement,
+		FileType:   reporterPkg.OutputExtensionPdf,
+		Params:     params,
+	}
+	err = suite.dummyApp.ExecuteProcess(payload, amqp.Delivery{})
+	assert.NoError(suite.T(), err)
+
+	reportFileRepositoryMock.AssertCalled(suite.T(), "SetStatus", mock2.Anything, payload.Id, pkg.ReportFileStatusBuilding, mock2.Anything)
+	reportFileRepositoryMock.AssertCalled(suite.T(), "SetStatus", mock2.Anything, payload.Id, pkg.ReportFileStatusRendering, mock2.Anything)
+	reportFileRepositoryMock.AssertCalled(
+		suite.T(),
+		"SetStatus",
+		mock2.Anything,
+		payload.Id,
+		pkg.ReportFileStatusRetrying,
+		mock2.MatchedBy(func(jobErr *proto.ReportFileJobError) bool {
+			return jobErr.Code == reporterErrors.ErrorDocumentGeneratorRender.Code && jobErr.Details == "render error"
+		}),
+	)
+	reportFileRepositoryMock.AssertNotCalled(suite.T(), "SetStatus", mock2.Anything, payload.Id, pkg.ReportFileStatusUploading, mock2.Anything)
+	suite.dummyApp.retryQueue.(*mocks.RetryQueueInterface).
+		AssertCalled(suite.T(), "Publish", pkg.BrokerGenerateReportInteractiveTopicName, payload, int32(1), mock2.Anything)
+}
+
+func (suite *ApplicationTestSuite) TestApplication_ExecuteProcess_Error_Render_JobFailed() {
+	documentGeneratorMock := &mocks.DocumentGeneratorInterface{}
+	documentGeneratorMock.On("Render", mock2.Anything, mock2.Anything).Return(nil, errors.New("render error"))
+	suite.dummyApp.renderers.Register(pkg.RendererJsReport, documentGeneratorMock)
+
+	reportFileRepositoryMock := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepositoryMock.On("SetStatus", mock2.Anything, mock2.Anything, mock2.Anything, mock2.Anything).Return(nil)
+	reportFileRepositoryMock.On("GetById", mock2.Anything, mock2.Anything).
+		Return(&proto.ReportFileJob{Status: pkg.ReportFileStatusQueued}, nil)
+	suite.dummyApp.reportFileRepository = reportFileRepositoryMock
+
+	deadLetterQueueMock := &mocks.DeadLetterQueueInterface{}
+	deadLetterQueueMock.On("Publish", mock2.Anything).Return(nil)
+	suite.dummyApp.deadLetterQueue = deadLetterQueueMock
+
+	params, err := json.Marshal(map[string]interface{}{reporterPkg.RequestParameterAgreementPSRate: []interface{}{}})
+	assert.NoError(suite.T(), err)
+
+	payload := &reporterPkg.ReportFile{
+		Id:         "ffffffffffffffffffffffff",
+		MerchantId: "ffffffffffffffffffffffff",
+		ReportType: reporterPkg.ReportTypeAgreement,
+		FileType:   reporterPkg.OutputExtensionPdf,
+		Params:     params,
+	}
+	d := amqp.Delivery{Headers: amqp.Table{rabbitmq.BrokerMessageRetryCountHeader: int32(10)}}
+	err = suite.dummyApp.ExecuteProcess(payload, d)
+	assert.NoError(suite.T(), err)
+
+	reportFileRepositoryMock.AssertCalled(suite.T(), "SetStatus", mock2.Anything, payload.Id, pkg.ReportFileStatusFailed, mock2.Anything)
+	reportFileRepositoryMock.AssertNotCalled(suite.T(), "SetStatus", mock2.Anything, payload.Id, pkg.ReportFileStatusRetrying, mock2.Anything)
+	deadLetterQueueMock.AssertCalled(suite.T(), "Publish", mock2.Anything)
+	suite.dummyApp.retryQueue.(*mocks.RetryQueueInterface).
+		AssertNotCalled(suite.T(), "Publish", mock2.Anything, mock2.Anything, mock2.Anything, mock2.Anything)
+}
+
+func (suite *ApplicationTestSuite) TestApplication_ExecuteProcess_Render_StageDeadline() {
+	suite.dummyApp.cfg.Job = config.JobConfig{Timeout: 60000, RenderTimeout: 1000}
+
+	documentGeneratorMock := &mocks.DocumentGeneratorInterface{}
+	documentGeneratorMock.On("Render", mock2.Anything, mock2.Anything).Return([]byte("file"), nil)
+	suite.dummyApp.renderers.Register(pkg.RendererJsReport, documentGeneratorMock)
+
+	params, err := json.Marshal(map[string]interface{}{reporterPkg.RequestParameterAgreementPSRate: []interface{}{}})
+	assert.NoError(suite.T(), err)
+
+	payload := &reporterPkg.ReportFile{
+		Id:         "ffffffffffffffffffffffff",
+		MerchantId: "ffffffffffffffffffffffff",
+		ReportType: reporterPkg.ReportTypeAgreement,
+		FileType:   reporterPkg.OutputExtensionPdf,
+		Params:     params,
+	}
+	err = suite.dummyApp.ExecuteProcess(payload, amqp.Delivery{})
+	assert.NoError(suite.T(), err)
+
+	documentGeneratorMock.AssertCalled(
+		suite.T(),
+		"Render",
+		mock2.MatchedBy(func(ctx context.Context) bool {
+			deadline, ok := ctx.Deadline()
+			return ok && time.Until(deadline) <= time.Second
+		}),
+		mock2.Anything,
+	)
+}
+
+func (suite *ApplicationTestSuite) TestApplication_ExecuteProcess_Notification_JobDeadline() {
+	suite.dummyApp.cfg.Job = config.JobConfig{Timeout: 60000}
+
+	documentGeneratorMock := &mocks.DocumentGeneratorInterface{}
+	documentGeneratorMock.On("Render", mock2.Anything, mock2.Anything).Return([]byte("file"), nil)
+	suite.dummyApp.renderers.Register(pkg.RendererJsReport, documentGeneratorMock)
+
+	params, err := json.Marshal(map[string]interface{}{reporterPkg.RequestParameterAgreementPSRate: []interface{}{}})
+	assert.NoError(suite.T(), err)
+
+	payload := &reporterPkg.ReportFile{
+		Id:               "ffffffffffffffffffffffff",
+		MerchantId:       "ffffffffffffffffffffffff",
+		ReportType:       reporterPkg.ReportTypeAgreement,
+		FileType:         reporterPkg.OutputExtensionPdf,
+		Params:           params,
+		SendNotification: true,
+	}
+	err = suite.dummyApp.ExecuteProcess(payload, amqp.Delivery{})
+	assert.NoError(suite.T(), err)
+
+	suite.dummyApp.centrifugo.(*mocks.CentrifugoInterface).AssertCalled(
+		suite.T(),
+		"Publish",
+		mock2.MatchedBy(func(ctx context.Context) bool {
+			deadline, ok := ctx.Deadline()
+			return ok && time.Until(deadline) <= time.Minute
+		}),
+		mock2.Anything,
+		mock2.Anything,
+	)
+}
+
+func (suite *ApplicationTestSuite) TestApplication_ExecuteProcess_Interactive_BulkSlotsTaken() {
+	suite.dummyApp.cfg.Job = config.JobConfig{Timeout: 60000, RenderTimeout: 100}
+	assert.NoError(suite.T(), suite.dummyApp.renderLimiter.Acquire(context.Background()))
+	defer suite.dummyApp.renderLimiter.Release()
+
+	documentGeneratorMock := &mocks.DocumentGeneratorInterface{}
+	documentGeneratorMock.On("Render", mock2.Anything, mock2.Anything).Return([]byte("file"), nil)
+	suite.dummyApp.renderers.Register(pkg.RendererJsReport, documentGeneratorMock)
+
+	params, err := json.Marshal(map[string]interface{}{reporterPkg.RequestParameterAgreementPSRate: []interface{}{}})
+	assert.NoError(suite.T(), err)
+
+	payload := &reporterPkg.ReportFile{
+		Id:         "ffffffffffffffffffffffff",
+		MerchantId: "ffffffffffffffffffffffff",
+		ReportType: reporterPkg.ReportTypeAgreement,
+		FileType:   reporterPkg.OutputExtensionPdf,
+		Params:     params,
+		Priority:   pkg.ReportFilePriorityInteractive,
+	}
+	err = suite.dummyApp.ExecuteProcess(payload, amqp.Delivery{})
+	assert.NoError(suite.T(), err)
+
+	documentGeneratorMock.AssertCalled(suite.T(), "Render", mock2.Anything, mock2.Anything)
+	suite.dummyApp.retryQueue.(*mocks.RetryQueueInterface).
+		AssertNotCalled(suite.T(), "Publish", mock2.Anything, mock2.Anything, mock2.Anything, mock2.Anything)
+}
+
+func (suite *ApplicationTestSuite) TestApplication_ExecuteProcess_Interrupted_Requeued() {
+	ctx, cancel := context.WithCancel(context.Background())
+	suite.dummyApp.ctx = ctx
+	cancel()
+
+	documentGeneratorMock := &mocks.DocumentGeneratorInterface{}
+	documentGeneratorMock.On("Render", mock2.Anything, mock2.Anything).Return(
+		nil,
+		func(ctx context.Context, _ *proto.GeneratorPayload) error {
+			return ctx.Err()
+		},
+	)
+	suite.dummyApp.renderers.Register(pkg.RendererJsReport, documentGeneratorMock)
+
+	reportFileRepositoryMock := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepositoryMock.On("SetStatus", mock2.Anything, mock2.Anything, mock2.Anything, mock2.Anything).Return(nil)
+	reportFileRepositoryMock.On("GetById", mock2.Anything, mock2.Anything).
+		Return(&proto.ReportFileJob{Status: pkg.ReportFileStatusQueued}, nil)
+	suite.dummyApp.reportFileRepository = reportFileRepositoryMock
+
+	params, err := json.Marshal(map[string]interface{}{reporterPkg.RequestParameterAgreementPSRate: []interface{}{}})
+	assert.NoError(suite.T(), err)
+
+	payload := &reporterPkg.ReportFile{
+		Id:         "ffffffffffffffffffffffff",
+		MerchantId: "ffffffffffffffffffffffff",
+		ReportType: reporterPkg.ReportTypeAgreement,
+		FileType:   reporterPkg.OutputExtensionPdf,
+		Params:     params,
+	}
+	d := amqp.Delivery{Headers: amqp.Table{rabbitmq.BrokerMessageRetryCountHeader: int32(2)}}
+	err = suite.dummyApp.ExecuteProcess(payload, d)
+	assert.NoError(suite.T(), err)
+
+	reportFileRepositoryMock.AssertCalled(suite.T(), "SetStatus", mock2.Anything, payload.Id, pkg.ReportFileStatusQueued, mock2.Anything)
+	reportFileRepositoryMock.AssertNotCalled(suite.T(), "SetStatus", mock2.Anything, payload.Id, pkg.ReportFileStatusFailed, mock2.Anything)
+	suite.dummyApp.generateInteractiveBroker.(*rabbitmqMock.BrokerInterface).AssertCalled(
+		suite.T(),
+		"Publish",
+		pkg.BrokerGenerateReportInteractiveTopicName,
+		payload,
+		amqp.Table{"x-retry-count": int32(2)},
+	)
+	suite.dummyApp.retryQueue.(*mocks.RetryQueueInterface).
+		AssertNotCalled(suite.T(), "Publish", mock2.Anything, mock2.Anything, mock2.Anything, mock2.Anything)
+	suite.dummyApp.storages.storages[pkg.StorageTargetAgreements].(*mocks.StorageInterface).
+		AssertNotCalled(suite.T(), "Upload", mock2.Anything, mock2.Anything, mock2.Anything, mock2.Anything)
+}
+
+func (suite *ApplicationTestSuite) TestApplication_ExecuteProcess_Stopped_Requeued() {
+	suite.dummyApp.jobs.Stop()
+
+	payload := &reporterPkg.ReportFile{
+		Id:         "ffffffffffffffffffffffff",
+		ReportType: reporterPkg.ReportTypeAgreement,
+		FileType:   reporterPkg.OutputExtensionPdf,
+	}
+	err := suite.dummyApp.ExecuteProcess(payload, amqp.Delivery{})
+	assert.NoError(suite.T(), err)
+
+	suite.dummyApp.generateInteractiveBroker.(*rabbitmqMock.BrokerInterface).AssertCalled(
+		suite.T(),
+		"Publish",
+		pkg.BrokerGenerateReportInteractiveTopicName,
+		payload,
+		amqp.Table{"x-retry-count": int32(0)},
+	)
+	suite.dummyApp.reportFileRepository.(*mocks.ReportFileRepositoryInterface).
+		AssertNotCalled(suite.T(), "SetStatus", mock2.Anything, mock2.Anything, mock2.Anything, mock2.Anything)
+	suite.dummyApp.renderers.Get(payload.ReportType, payload.FileType).(*mocks.DocumentGeneratorInterface).
+		AssertNotCalled(suite.T(), "Render", mock2.Anything, mock2.Anything)
+}
+
+func (suite *ApplicationTestSuite) TestApplication_ExecuteProcess_Stopped_RequeueFailed() {
+	suite.dummyApp.jobs.Stop()
+
+	brokerMock := &rabbitmqMock.BrokerInterface{}
+	brokerMock.On("Publish", mock2.Anything, mock2.Anything, mock2.Anything).Return(errors.New("error"))
+	suite.dummyApp.generateReportBroker = brokerMock
+
+	payload := &reporterPkg.ReportFile{
+		Id:         "ffffffffffffffffffffffff",
+		ReportType: reporterPkg.ReportTypeVat,
+		FileType:   reporterPkg.OutputExtensionPdf,
+	}
+	d := amqp.Delivery{Headers: amqp.Table{rabbitmq.BrokerMessageRetryCountHeader: "2"}}
+	err := suite.dummyApp.ExecuteProcess(payload, d)
+	assert.Equal(suite.T(), errMessageRedelivery, err)
+
+	brokerMock.AssertCalled(
+		suite.T(),
+		"Publish",
+		pkg.BrokerGenerateReportTopicName,
+		payload,
+		amqp.Table{"x-retry-count": int32(0)},
+	)
+}
+
+func (suite *ApplicationTestSuite) TestApplication_Shutdown_AbortJobs() {
+	ctx, cancel := context.WithCancel(context.Background())
+	suite.dummyApp.ctx = ctx
+	suite.dummyApp.cancel = cancel
+	suite.dummyApp.cfg.Job = config.JobConfig{DrainTimeout: 10, AbortTimeout: 1000}
+
+	rendering := make(chan bool)
+	documentGeneratorMock := &mocks.DocumentGeneratorInterface{}
+	documentGeneratorMock.On("Render", mock2.Anything, mock2.Anything).Return(
+		nil,
+		func(ctx context.Context, _ *proto.GeneratorPayload) error {
+			close(rendering)
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	)
+	suite.dummyApp.renderers.Register(pkg.RendererJsReport, documentGeneratorMock)
+
+	params, err := json.Marshal(map[string]interface{}{reporterPkg.RequestParameterAgreementPSRate: []interface{}{}})
+	assert.NoError(suite.T(), err)
+
+	payload := &reporterPkg.ReportFile{
+		Id:         "ffffffffffffffffffffffff",
+		ReportType: reporterPkg.ReportTypeAgreement,
+		FileType:   reporterPkg.OutputExtensionPdf,
+		Params:     params,
+	}
+
+	go func() {
+		_ = suite.dummyApp.ExecuteProcess(payload, amqp.Delivery{})
+	}()
+	<-rendering
+
+	suite.dummyApp.shutdown()
+
+	assert.Equal(suite.T(), 0, suite.dummyApp.jobs.Count())
+	assert.Error(suite.T(), ctx.Err())
+	suite.dummyApp.generateInteractiveBroker.(*rabbitmqMock.BrokerInterface).AssertCalled(
+		suite.T(),
+		"Publish",
+		pkg.BrokerGenerateReportInteractiveTopicName,
+		payload,
+		amqp.Table{"x-retry-count": int32(0)},
+	)
+}
+
+func (suite *ApplicationTestSuite) TestApplication_ExecuteProcess_Cancelled_BeforeBuild() {
+	reportFileRepositoryMock := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepositoryMock.On("SetStatus", mock2.Anything, mock2.Anything, mock2.Anything, mock2.Anything).Return(nil)
+	reportFileRepositoryMock.On("GetById", mock2.Anything, mock2.Anything).
+		Return(&proto.ReportFileJob{Status: pkg.ReportFileStatusCancelled}, nil)
+	suite.dummyApp.reportFileRepository = reportFileRepositoryMock
+
+	payload := &reporterPkg.ReportFile{
+		Id:         "ffffffffffffffffffffffff",
+		MerchantId: "ffffffffffffffffffffffff",
+		ReportType: reporterPkg.ReportTypeAgreement,
+		FileType:   reporterPkg.OutputExtensionPdf,
+	}
+	err := suite.dummyApp.ExecuteProcess(payload, amqp.Delivery{})
+	assert.NoError(suite.T(), err)
+
+	reportFileRepositoryMock.AssertNotCalled(suite.T(), "SetStatus", mock2.Anything, mock2.Anything, mock2.Anything, mock2.Anything)
+	suite.dummyApp.renderers.Get(payload.ReportType, payload.FileType).(*mocks.DocumentGeneratorInterface).
+		AssertNotCalled(suite.T(), "Render", mock2.Anything, mock2.Anything)
+}
+
+func (suite *ApplicationTestSuite) TestApplication_ExecuteProcess_Cancelled_AfterUpload() {
+	reportFileRepositoryMock := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepositoryMock.On("SetStatus", mock2.Anything, mock2.Anything, mock2.Anything, mock2.Anything).Return(nil)
+	reportFileRepositoryMock.On("GetById", mock2.Anything, mock2.Anything).
+		Return(&proto.ReportFileJob{Status: pkg.ReportFileStatusQueued}, nil)
+	reportFileRepositoryMock.
+		On("SetFile", mock2.Anything, mock2.Anything, mock2.Anything, mock2.Anything, mock2.Anything, mock2.Anything, mock2.Anything).
+		Return(mongo.ErrNoDocuments)
+	suite.dummyApp.reportFileRepository = reportFileRepositoryMock
+
+	storageMock := &mocks.StorageInterface{}
+	storageMock.On("Upload", mock2.Anything, mock2.Anything, mock2.Anything, mock2.Anything).Return(nil)
+	storageMock.On("Delete", mock2.Anything, mock2.Anything).Return(nil)
+	storageMock.On("Bucket").Return("agreements")
+	suite.dummyApp.storages.storages[pkg.StorageTargetAgreements] = storageMock
+
+	params, err := json.Marshal(map[string]interface{}{
+		reporterPkg.RequestParameterAgreementNumber:    "123456-AA-7890",
+		reporterPkg.RequestParameterAgreementLegalName: "Company Name",
+		reporterPkg.RequestParameterAgreementPSRate:    []interface{}{},
+	})
+	assert.NoError(suite.T(), err)
+
+	payload := &reporterPkg.ReportFile{
+		Id:               "ffffffffffffffffffffffff",
+		MerchantId:       "ffffffffffffffffffffffff",
+		ReportType:       reporterPkg.ReportTypeAgreement,
+		FileType:         reporterPkg.OutputExtensionPdf,
+		Params:           params,
+		SendNotification: true,
+	}
+	err = suite.dummyApp.ExecuteProcess(payload, amqp.Delivery{})
+	assert.NoError(suite.T(), err)
+
+	storageMock.AssertCalled(suite.T(), "Delete", mock2.Anything, "License Agreement_Company Name_#123456-AA-7890.pdf")
+	reportFileRepositoryMock.AssertNotCalled(suite.T(), "SetStatus", mock2.Anything, payload.Id, pkg.ReportFileStatusUploaded, mock2.Anything)
+	suite.dummyApp.centrifugo.(*mocks.CentrifugoInterface).AssertNotCalled(suite.T(), "Publish", mock2.Anything, mock2.Anything, mock2.Anything)
+	suite.dummyApp.postProcessBroker.(*rabbitmqMock.BrokerInterface).
+		AssertNotCalled(suite.T(), "Publish", pkg.BrokerPostProcessTopicName, mock2.Anything, mock2.Anything)
+}
+
+func (suite *ApplicationTestSuite) TestApplication_ExecuteProcess_Error_SetFile() {
+	reportFileRepositoryMock := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepositoryMock.On("SetStatus", mock2.Anything, mock2.Anything, mock2.Anything, mock2.Anything).Return(nil)
+	reportFileRepositoryMock.On("GetById", mock2.Anything, mock2.Anything).
+		Return(&proto.ReportFileJob{Status: pkg.ReportFileStatusQueued}, nil)
+	reportFileRepositoryMock.
+		On("SetFile", mock2.Anything, mock2.Anything, mock2.Anything, mock2.Anything, mock2.Anything, mock2.Anything, mock2.Anything).
+		Return(errors.New("error"))
+	suite.dummyApp.reportFileRepository = reportFileRepositoryMock
+
+	storageMock := &mocks.StorageInterface{}
+	storageMock.On("Upload", mock2.Anything, mock2.Anything, mock2.Anything, mock2.Anything).Return(nil)
+	storageMock.On("Delete", mock2.Anything, mock2.Anything).Return(nil)
+	storageMock.On("Bucket").Return("agreements")
+	suite.dummyApp.storages.storages[pkg.StorageTargetAgreements] = storageMock
+
+	params, err := json.Marshal(map[string]interface{}{
+		reporterPkg.RequestParameterAgreementNumber:    "123456-AA-7890",
+		reporterPkg.RequestParameterAgreementLegalName: "Company Name",
+		reporterPkg.RequestParameterAgreementPSRate:    []interface{}{},
+	})
+	assert.NoError(suite.T(), err)
+
+	payload := &reporterPkg.ReportFile{
+		Id:               "ffffffffffffffffffffffff",
+		MerchantId:       "ffffffffffffffffffffffff",
+		ReportType:       reporterPkg.ReportTypeAgreement,
+		FileType:         reporterPkg.OutputExtensionPdf,
+		Params:           params,
+		SendNotification: true,
+	}
+	err = suite.dummyApp.ExecuteProcess(payload, amqp.Delivery{})
+	assert.NoError(suite.T(), err)
+
+	storageMock.AssertCalled(suite.T(), "Delete", mock2.Anything, "License Agreement_Company Name_#123456-AA-7890.pdf")
+	reportFileRepositoryMock.AssertCalled(
+		suite.T(),
+		"SetStatus",
+		mock2.Anything,
+		payload.Id,
+		pkg.ReportFileStatusRetrying,
+		mock2.MatchedBy(func(jobErr *proto.ReportFileJobError) bool {
+			return jobErr.Code == reporterErrors.ErrorDatabaseQueryFailed.Code && jobErr.Details == "error"
+		}),
+	)
+	reportFileRepositoryMock.AssertNotCalled(suite.T(), "SetStatus", mock2.Anything, payload.Id, pkg.ReportFileStatusUploaded, mock2.Anything)
+	suite.dummyApp.centrifugo.(*mocks.CentrifugoInterface).AssertNotCalled(suite.T(), "Publish", mock2.Anything, mock2.Anything, mock2.Anything)
+	suite.dummyApp.retryQueue.(*mocks.RetryQueueInterface).
+		AssertCalled(suite.T(), "Publish", pkg.BrokerGenerateReportInteractiveTopicName, payload, int32(1), mock2.Anything)
+}
+
+func (suite *ApplicationTestSuite) TestApplication_ExecutePostProcess_Cancelled() {
+	reportFileRepositoryMock := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepositoryMock.On("GetById", mock2.Anything, mock2.Anything).
+		Return(&proto.ReportFileJob{Status: pkg.ReportFileStatusCancelled}, nil)
+	suite.dummyApp.reportFileRepository = reportFileRepositoryMock
+
+	storageMock := &mocks.StorageInterface{}
+	storageMock.On("Delete", mock2.Anything, mock2.Anything).Return(nil)
+	suite.dummyApp.storages.storages[pkg.StorageTargetReports] = storageMock
+
+	payload := &reporterPkg.PostProcessRequest{
+		ReportFile: &reporterPkg.ReportFile{
+			Id:         "ffffffffffffffffffffffff",
+			ReportType: reporterPkg.ReportTypeVat,
+		},
+		FileName: "report.pdf",
+	}
+	err := suite.dummyApp.ExecutePostProcess(payload, amqp.Delivery{})
+	assert.NoError(suite.T(), err)
+
+	storageMock.AssertCalled(suite.T(), "Delete", mock2.Anything, "report.pdf")
+	reportFileRepositoryMock.AssertNotCalled(suite.T(), "SetStatus", mock2.Anything, mock2.Anything, mock2.Anything, mock2.Anything)
+}
+
+func (suite *ApplicationTestSuite) TestApplication_getFileLoader_Ok() {
+	content := []byte("royalty report content")
+	checksum := sha256.Sum256(content)
+
+	storageMock := &mocks.StorageInterface{}
+	storageMock.On("Bucket").Return("reports")
+	storageMock.On("Get", mock2.Anything, "report.pdf").Return(content, nil)
+	suite.dummyApp.storages.storages[pkg.StorageTargetReports] = storageMock
+
+	ref := &reporterPkg.FileReference{Bucket: "reports", Key: "report.pdf", Checksum: hex.EncodeToString(checksum[:])}
+	res, err := suite.dummyApp.getFileLoader(&reporterPkg.PostProcessRequest{
+		ReportFile: &reporterPkg.ReportFile{ReportType: reporterPkg.ReportTypeRoyalty},
+		File:       ref,
+	})(context.TODO())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), content, res)
+}
+
+func (suite *ApplicationTestSuite) TestApplication_getFileLoader_Error_Checksum() {
+	storageMock := &mocks.StorageInterface{}
+	storageMock.On("Bucket").Return("reports")
+	storageMock.On("Get", mock2.Anything, "report.pdf").Return([]byte("corrupted content"), nil)
+	suite.dummyApp.storages.storages[pkg.StorageTargetReports] = storageMock
+
+	ref := &reporterPkg.FileReference{Bucket: "reports", Key: "report.pdf", Checksum: "checksum"}
+	_, err := suite.dummyApp.getFileLoader(&reporterPkg.PostProcessRequest{
+		ReportFile: &reporterPkg.ReportFile{ReportType: reporterPkg.ReportTypeRoyalty},
+		File:       ref,
+	})(context.TODO())
+	assert.Equal(suite.T(), errFileChecksumMismatch, err)
+}
+
+func (suite *ApplicationTestSuite) TestApplication_getFileLoader_Error_Bucket() {
+	ref := &reporterPkg.FileReference{Bucket: "unknown", Key: "report.pdf"}
+	_, err := suite.dummyApp.getFileLoader(&reporterPkg.PostProcessRequest{
+		ReportFile: &reporterPkg.ReportFile{ReportType: reporterPkg.ReportTypeAgreement},
+		File:       ref,
+	})(context.TODO())
+	assert.EqualError(suite.T(), err, "report file bucket unknown is not configured")
+}
+
+func (suite *ApplicationTestSuite) TestApplication_getFileLoader_Error_ReferenceMissing() {
+	_, err := suite.dummyApp.getFileLoader(&reporterPkg.PostProcessRequest{
+		ReportFile: &reporterPkg.ReportFile{ReportType: reporterPkg.ReportTypeRoyalty},
+		File:       nil,
+	})(context.TODO())
+	assert.Equal(suite.T(), errFileReferenceMissing, err)
+}
+
+func (suite *ApplicationTestSuite) TestApplication_getFileLoader_Ok_FileContent() {
+	content := []byte("royalty report content")
+	res, err := suite.dummyApp.getFileLoader(&reporterPkg.PostProcessRequest{
+		ReportFile:  &reporterPkg.ReportFile{ReportType: reporterPkg.ReportTypeRoyalty},
+		FileContent: content,
+	})(context.TODO())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), content, res)
 }
@@ -0,0 +1,160 @@
+package internal
+
+import (
+	"errors"
+	"github.com/streadway/amqp"
+	"time"
+)
+
+const (
+	brokerMinReconnectDelay = 100 * time.Millisecond
+	brokerMaxReconnectDelay = 30 * time.Second
+)
+
+var errBrokerReconnectDelayed = errors.New("broker connection is lost, reconnect is delayed")
+
+// BrokerChannel holds the connection and the channel of the publisher and dials them again when the broker
+// connection is lost. Reconnects after the failed dial are delayed by the exponential backoff, so the publishers
+// fail fast instead of dialing the unavailable broker on every message. The caller synchronizes the access.
+type BrokerChannel struct {
+	address string
+	declare func(ch *amqp.Channel) error
+
+	conn    *amqp.Connection
+	ch      *amqp.Channel
+	delay   time.Duration
+	retryAt time.Time
+	now     func() time.Time
+}
+
+// newBrokerChannel dials the broker, the declare function is called for every new channel.
+func newBrokerChannel(address string, declare func(ch *amqp.Channel) error) (*BrokerChannel, error) {
+	c := &BrokerChannel{address: address, declare: declare, delay: brokerMinReconnectDelay, now: time.Now}
+
+	if _, _, err := c.Channel(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Channel returns the open channel and reports whether it has been dialed by the call.
+func (c *BrokerChannel) Channel() (*amqp.Channel, bool, error) {
+	if c.ch != nil {
+		return c.ch, false, nil
+	}
+
+	if c.now().Before(c.retryAt) {
+		return nil, false, errBrokerReconnectDelayed
+	}
+
+	conn, ch, err := c.dial()
+
+	if err != nil {
+		c.retryAt = c.now().Add(c.delay)
+
+		if c.delay *= 2; c.delay > brokerMaxReconnectDelay {
+			c.delay = brokerMaxReconnectDelay
+		}
+
+		return nil, false, err
+	}
+
+	c.conn, c.ch = conn, ch
+	c.delay = brokerMinReconnectDelay
+	c.retryAt = time.Time{}
+
+	return ch, true, nil
+}
+
+// Publish calls the function with the open channel. The function is called again on the channel dialed anew when
+// the connection has been lost, the dialed flag tells the function to declare its topology again.
+func (c *BrokerChannel) Publish(fn func(ch *amqp.Channel, dialed bool) error) error {
+	ch, dialed, err := c.Channel()
+
+	if err != nil {
+		return err
+	}
+
+	err = fn(ch, dialed)
+
+	if !isBrokerConnectionLost(err) {
+		return err
+	}
+
+	c.Reset()
+
+	if dialed {
+		return err
+	}
+
+	if ch, dialed, err = c.Channel(); err != nil {
+		return err
+	}
+
+	if err = fn(ch, dialed); isBrokerConnectionLost(err) {
+		c.Reset()
+	}
+
+	return err
+}
+
+// Connection returns the connection of the open channel.
+func (c *BrokerChannel) Connection() (*amqp.Connection, error) {
+	if _, _, err := c.Channel(); err != nil {
+		return nil, err
+	}
+
+	return c.conn, nil
+}
+
+// Reset closes the lost connection, so the next call of Channel dials the broker again.
+func (c *BrokerChannel) Reset() {
+	if c.conn != nil {
+		_ = c.conn.Close()
+	}
+
+	c.conn, c.ch = nil, nil
+}
+
+func (c *BrokerChannel) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+
+	err := c.conn.Close()
+	c.conn, c.ch = nil, nil
+
+	return err
+}
+
+func (c *BrokerChannel) dial() (*amqp.Connection, *amqp.Channel, error) {
+	conn, err := amqp.Dial(c.address)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ch, err := conn.Channel()
+
+	if err != nil {
+		_ = conn.Close()
+		return nil, nil, err
+	}
+
+	if c.declare != nil {
+		if err = c.declare(ch); err != nil {
+			_ = conn.Close()
+			return nil, nil, err
+		}
+	}
+
+	return conn, ch, nil
+}
+
+// isBrokerConnectionLost reports whether the error has closed the channel, amqp.ErrClosed is returned for the channel
+// or the connection closed before, the other broker errors close the channel they are raised on.
+func isBrokerConnectionLost(err error) bool {
+	_, ok := err.(*amqp.Error)
+	return ok
+}
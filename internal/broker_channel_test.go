@@ -0,0 +1,66 @@
+package internal
+
+import (
+	"errors"
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type BrokerChannelTestSuite struct {
+	suite.Suite
+}
+
+func Test_BrokerChannel(t *testing.T) {
+	suite.Run(t, new(BrokerChannelTestSuite))
+}
+
+func (suite *BrokerChannelTestSuite) TestBrokerChannel_New_Error() {
+	_, err := newBrokerChannel("amqp://127.0.0.1:1/", nil)
+	assert.Error(suite.T(), err)
+}
+
+func (suite *BrokerChannelTestSuite) TestBrokerChannel_Channel_ReconnectDelayed() {
+	now := time.Now()
+	c := &BrokerChannel{address: "amqp://127.0.0.1:1/", delay: brokerMinReconnectDelay, now: func() time.Time { return now }}
+
+	_, _, err := c.Channel()
+	assert.Error(suite.T(), err)
+	assert.NotEqual(suite.T(), errBrokerReconnectDelayed, err)
+	assert.Equal(suite.T(), 2*brokerMinReconnectDelay, c.delay)
+
+	_, _, err = c.Channel()
+	assert.Equal(suite.T(), errBrokerReconnectDelayed, err)
+
+	now = now.Add(brokerMinReconnectDelay)
+	_, _, err = c.Channel()
+	assert.NotEqual(suite.T(), errBrokerReconnectDelayed, err)
+	assert.Equal(suite.T(), 4*brokerMinReconnectDelay, c.delay)
+
+	c.delay = brokerMaxReconnectDelay
+	now = now.Add(time.Hour)
+	_, _, _ = c.Channel()
+	assert.Equal(suite.T(), brokerMaxReconnectDelay, c.delay)
+}
+
+func (suite *BrokerChannelTestSuite) TestBrokerChannel_Publish_ReconnectDelayed() {
+	now := time.Now()
+	c := &BrokerChannel{retryAt: now.Add(time.Second), now: func() time.Time { return now }}
+	called := false
+
+	err := c.Publish(func(ch *amqp.Channel, dialed bool) error {
+		called = true
+		return nil
+	})
+	assert.Equal(suite.T(), errBrokerReconnectDelayed, err)
+	assert.False(suite.T(), called)
+}
+
+func (suite *BrokerChannelTestSuite) TestBrokerChannel_isBrokerConnectionLost() {
+	assert.True(suite.T(), isBrokerConnectionLost(amqp.ErrClosed))
+	assert.True(suite.T(), isBrokerConnectionLost(&amqp.Error{Code: amqp.PreconditionFailed}))
+	assert.False(suite.T(), isBrokerConnectionLost(errors.New("error")))
+	assert.False(suite.T(), isBrokerConnectionLost(nil))
+}
@@ -6,7 +6,7 @@ import (
 	"fmt"
 	"github.com/micro/go-micro/client"
 	"github.com/paysuper/paysuper-proto/go/billingpb"
-	"github.com/paysuper/paysuper-proto/go/reporterpb"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
 	"time"
 )
 
@@ -82,7 +82,7 @@ func (h *Agreement) Validate() error {
 	return nil
 }
 
-func (h *Agreement) Build() (interface{}, error) {
+func (h *Agreement) Build(_ context.Context) (interface{}, error) {
 	params, err := h.GetParams()
 
 	if err != nil {
@@ -117,7 +117,7 @@ func (h *Agreement) PostProcess(
 	_ string,
 	fileName string,
 	_ int64,
-	_ []byte,
+	_ FileLoader,
 ) error {
 	req := &billingpb.SetMerchantS3AgreementRequest{
 		MerchantId:      h.report.MerchantId,
@@ -8,7 +8,7 @@ import (
 	"github.com/micro/go-micro"
 	"github.com/paysuper/paysuper-proto/go/billingpb"
 	billingMocks "github.com/paysuper/paysuper-proto/go/billingpb/mocks"
-	"github.com/paysuper/paysuper-proto/go/reporterpb"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -182,7 +182,7 @@ func (suite *AgreementBuilderTestSuite) TestAgreementBuilder_Build_Ok() {
 
 	handler := &Handler{report: &reporterpb.ReportFile{Params: body}, service: micro.NewService()}
 	builder := newAgreementHandler(handler)
-	params, err := builder.Build()
+	params, err := builder.Build(context.Background())
 	assert.NoError(suite.T(), err)
 	assert.Contains(suite.T(), params, reporterpb.RequestParameterAgreementNumber)
 }
@@ -197,7 +197,7 @@ func (suite *AgreementBuilderTestSuite) TestAgreementBuilder_PostProcess_Ok() {
 		billing: bs,
 	}
 	builder := newAgreementHandler(handler)
-	err := builder.PostProcess(context.TODO(), "id", "fileName", 3600, []byte{})
+	err := builder.PostProcess(context.TODO(), "id", "fileName", 3600, nil)
 	assert.NoError(suite.T(), err)
 }
 
@@ -211,7 +211,7 @@ func (suite *AgreementBuilderTestSuite) TestAgreementBuilder_PostProcess_Billing
 		billing: bs,
 	}
 	builder := newAgreementHandler(handler)
-	err := builder.PostProcess(context.TODO(), "id", "fileName", 3600, []byte{})
+	err := builder.PostProcess(context.TODO(), "id", "fileName", 3600, nil)
 	assert.Error(suite.T(), err)
 	assert.Equal(suite.T(), "some error", err.Error())
 }
@@ -232,7 +232,7 @@ func (suite *AgreementBuilderTestSuite) TestAgreementBuilder_PostProcess_Billing
 		billing: bs,
 	}
 	builder := newAgreementHandler(handler)
-	err := builder.PostProcess(context.TODO(), "id", "fileName", 3600, []byte{})
+	err := builder.PostProcess(context.TODO(), "id", "fileName", 3600, nil)
 	assert.Error(suite.T(), err)
 	assert.Equal(suite.T(), "some business logic  error", err.Error())
 }
@@ -6,8 +6,8 @@ import (
 	errs "errors"
 	"github.com/micro/go-micro"
 	"github.com/paysuper/paysuper-proto/go/billingpb"
-	"github.com/paysuper/paysuper-proto/go/reporterpb"
 	"github.com/paysuper/paysuper-reporter/pkg/errors"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
 )
 
 var (
@@ -22,10 +22,14 @@ var (
 	}
 )
 
+// FileLoader reads the rendered report file from the storage. Post processing handlers call it only when
+// they need the content of the file.
+type FileLoader func(ctx context.Context) ([]byte, error)
+
 type BuildInterface interface {
 	Validate() error
-	Build() (interface{}, error)
-	PostProcess(context.Context, string, string, int64, []byte) error
+	Build(ctx context.Context) (interface{}, error)
+	PostProcess(context.Context, string, string, int64, FileLoader) error
 }
 
 type Handler struct {
@@ -2,8 +2,8 @@ package builder
 
 import (
 	billingMocks "github.com/paysuper/paysuper-proto/go/billingpb/mocks"
-	"github.com/paysuper/paysuper-proto/go/reporterpb"
 	"github.com/paysuper/paysuper-reporter/pkg/errors"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"testing"
@@ -6,8 +6,8 @@ import (
 	"fmt"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/paysuper/paysuper-proto/go/billingpb"
-	"github.com/paysuper/paysuper-proto/go/reporterpb"
 	errs "github.com/paysuper/paysuper-reporter/pkg/errors"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.uber.org/zap"
 	"math"
@@ -39,8 +39,7 @@ func (h *Payout) Validate() error {
 	return nil
 }
 
-func (h *Payout) Build() (interface{}, error) {
-	ctx := context.TODO()
+func (h *Payout) Build(ctx context.Context) (interface{}, error) {
 	params, _ := h.GetParams()
 	payoutId := fmt.Sprintf("%s", params[reporterpb.ParamsFieldId])
 
@@ -156,8 +155,14 @@ func (h *Payout) PostProcess(
 	id string,
 	fileName string,
 	retentionTime int64,
-	content []byte,
+	load FileLoader,
 ) error {
+	content, err := load(ctx)
+
+	if err != nil {
+		return err
+	}
+
 	params, _ := h.GetParams()
 
 	req := &billingpb.PayoutDocumentPdfUploadedRequest{
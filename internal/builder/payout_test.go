@@ -1,12 +1,13 @@
 package builder
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/paysuper/paysuper-proto/go/billingpb"
 	billingMocks "github.com/paysuper/paysuper-proto/go/billingpb/mocks"
-	"github.com/paysuper/paysuper-proto/go/reporterpb"
 	"github.com/paysuper/paysuper-reporter/pkg/errors"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
 	"github.com/stretchr/testify/assert"
 	mock2 "github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -69,7 +70,7 @@ func (suite *PayoutBuilderTestSuite) TestPayoutBuilder_Build_Ok() {
 		billing: billing,
 	})
 
-	r, err := h.Build()
+	r, err := h.Build(context.Background())
 	assert.NoError(suite.T(), err)
 	assert.NotEmpty(suite.T(), payoutResponse.Item.Id, r)
 }
@@ -102,7 +103,7 @@ func (suite *PayoutBuilderTestSuite) TestPayoutBuilder_Build_Error_GetPayoutDocu
 		billing: billing,
 	})
 
-	_, err := h.Build()
+	_, err := h.Build(context.Background())
 	assert.Error(suite.T(), err)
 }
 
@@ -134,7 +135,7 @@ func (suite *PayoutBuilderTestSuite) TestPayoutBuilder_Build_Error_GetMerchantBy
 		billing: billing,
 	})
 
-	_, err := h.Build()
+	_, err := h.Build(context.Background())
 	assert.Error(suite.T(), err)
 }
 
@@ -166,7 +167,7 @@ func (suite *PayoutBuilderTestSuite) TestPayoutBuilder_Build_Error_GetOperatingC
 		billing: billing,
 	})
 
-	_, err := h.Build()
+	_, err := h.Build(context.Background())
 	assert.Error(suite.T(), err)
 }
 
@@ -6,8 +6,8 @@ import (
 	"fmt"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/paysuper/paysuper-proto/go/billingpb"
-	"github.com/paysuper/paysuper-proto/go/reporterpb"
 	errs "github.com/paysuper/paysuper-reporter/pkg/errors"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.uber.org/zap"
 	"math"
@@ -47,8 +47,7 @@ func (h *Royalty) Validate() error {
 	return nil
 }
 
-func (h *Royalty) Build() (interface{}, error) {
-	ctx := context.TODO()
+func (h *Royalty) Build(ctx context.Context) (interface{}, error) {
 	params, _ := h.GetParams()
 	royaltyId := fmt.Sprintf("%s", params[reporterpb.ParamsFieldId])
 
@@ -233,8 +232,14 @@ func (h *Royalty) PostProcess(
 	id string,
 	fileName string,
 	retentionTime int64,
-	content []byte,
+	load FileLoader,
 ) error {
+	content, err := load(ctx)
+
+	if err != nil {
+		return err
+	}
+
 	params, _ := h.GetParams()
 
 	req := &billingpb.RoyaltyReportPdfUploadedRequest{
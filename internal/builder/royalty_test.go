@@ -1,12 +1,14 @@
 package builder
 
 import (
+	"context"
 	"encoding/json"
+	errs "errors"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/paysuper/paysuper-proto/go/billingpb"
 	billingMocks "github.com/paysuper/paysuper-proto/go/billingpb/mocks"
-	"github.com/paysuper/paysuper-proto/go/reporterpb"
 	"github.com/paysuper/paysuper-reporter/pkg/errors"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
 	"github.com/stretchr/testify/assert"
 	mock2 "github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -81,7 +83,7 @@ func (suite *RoyaltyBuilderTestSuite) TestRoyaltyBuilder_Build_Ok() {
 		billing: billing,
 	})
 
-	r, err := h.Build()
+	r, err := h.Build(context.Background())
 	assert.NoError(suite.T(), err)
 	assert.NotEmpty(suite.T(), royaltyResponse.Item.Id, r)
 }
@@ -114,7 +116,7 @@ func (suite *RoyaltyBuilderTestSuite) TestRoyaltyBuilder_Build_Error_GetRoyaltyR
 		billing: billing,
 	})
 
-	_, err := h.Build()
+	_, err := h.Build(context.Background())
 	assert.Error(suite.T(), err)
 }
 
@@ -146,7 +148,7 @@ func (suite *RoyaltyBuilderTestSuite) TestRoyaltyBuilder_Build_Error_GetMerchant
 		billing: billing,
 	})
 
-	_, err := h.Build()
+	_, err := h.Build(context.Background())
 	assert.Error(suite.T(), err)
 }
 
@@ -178,10 +180,49 @@ func (suite *RoyaltyBuilderTestSuite) TestRoyaltyBuilder_Build_Error_GetOperatin
 		billing: billing,
 	})
 
-	_, err := h.Build()
+	_, err := h.Build(context.Background())
 	assert.Error(suite.T(), err)
 }
 
+func (suite *RoyaltyBuilderTestSuite) TestRoyaltyBuilder_PostProcess_Ok() {
+	billing := &billingMocks.BillingService{}
+	billing.
+		On("RoyaltyReportPdfUploaded", mock2.Anything, mock2.MatchedBy(func(req *billingpb.RoyaltyReportPdfUploadedRequest) bool {
+			return req.Id == "id" && req.RoyaltyReportId == "1" && string(req.Content) == "content"
+		})).
+		Return(&billingpb.RoyaltyReportPdfUploadedResponse{}, nil)
+
+	params, _ := json.Marshal(map[string]interface{}{reporterpb.ParamsFieldId: "1"})
+	h := newRoyaltyHandler(&Handler{
+		report:  &reporterpb.ReportFile{MerchantId: "ffffffffffffffffffffffff", Params: params},
+		billing: billing,
+	})
+	load := func(_ context.Context) ([]byte, error) {
+		return []byte("content"), nil
+	}
+
+	err := h.PostProcess(context.TODO(), "id", "report.pdf", 3600, load)
+	assert.NoError(suite.T(), err)
+	billing.AssertExpectations(suite.T())
+}
+
+func (suite *RoyaltyBuilderTestSuite) TestRoyaltyBuilder_PostProcess_Error_Load() {
+	billing := &billingMocks.BillingService{}
+
+	params, _ := json.Marshal(map[string]interface{}{reporterpb.ParamsFieldId: "1"})
+	h := newRoyaltyHandler(&Handler{
+		report:  &reporterpb.ReportFile{MerchantId: "ffffffffffffffffffffffff", Params: params},
+		billing: billing,
+	})
+	load := func(_ context.Context) ([]byte, error) {
+		return nil, errs.New("storage error")
+	}
+
+	err := h.PostProcess(context.TODO(), "id", "report.pdf", 3600, load)
+	assert.EqualError(suite.T(), err, "storage error")
+	billing.AssertNotCalled(suite.T(), "RoyaltyReportPdfUploaded", mock2.Anything, mock2.Anything)
+}
+
 func (suite *RoyaltyBuilderTestSuite) getRoyaltyReportTemplate() *billingpb.RoyaltyReport {
 	datetime, _ := ptypes.TimestampProto(time.Now())
 
@@ -6,13 +6,15 @@ import (
 	"fmt"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/paysuper/paysuper-proto/go/billingpb"
-	"github.com/paysuper/paysuper-proto/go/reporterpb"
 	errs "github.com/paysuper/paysuper-reporter/pkg/errors"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.uber.org/zap"
 	"math"
 )
 
+const royaltyTransactionsPageLimit = int64(1000)
+
 type RoyaltyTransactions DefaultHandler
 
 func newRoyaltyTransactionsHandler(h *Handler) BuildInterface {
@@ -47,8 +49,7 @@ func (h *RoyaltyTransactions) Validate() error {
 	return nil
 }
 
-func (h *RoyaltyTransactions) Build() (interface{}, error) {
-	ctx := context.TODO()
+func (h *RoyaltyTransactions) Build(ctx context.Context) (interface{}, error) {
 	params, _ := h.GetParams()
 	royaltyId := fmt.Sprintf("%s", params[reporterpb.ParamsFieldId])
 
@@ -86,31 +87,59 @@ func (h *RoyaltyTransactions) Build() (interface{}, error) {
 		return nil, err
 	}
 
-	ordersRequest := &billingpb.ListOrdersRequest{Merchant: []string{h.report.MerchantId}}
-	orders, err := h.billing.FindAllOrdersPublic(ctx, ordersRequest)
+	periodFrom, err := ptypes.Timestamp(royalty.Item.PeriodFrom)
 
-	if err != nil || orders.Status != billingpb.ResponseStatusOk {
-		if err == nil {
-			err = errors.New(orders.Message.Message)
-		}
+	if err != nil {
+		zap.L().Error(
+			"Unable to cast timestamp to time",
+			zap.Error(err),
+			zap.String("period_from", royalty.Item.PeriodFrom.String()),
+		)
+		return nil, err
+	}
+
+	periodTo, err := ptypes.Timestamp(royalty.Item.PeriodTo)
 
+	if err != nil {
 		zap.L().Error(
-			"Unable to get orders",
+			"Unable to cast timestamp to time",
 			zap.Error(err),
-			zap.String("merchant_id", h.report.MerchantId),
+			zap.String("period_to", royalty.Item.PeriodTo.String()),
 		)
+		return nil, err
+	}
 
+	orders, err := h.getOrders(ctx, royalty.Item.OperatingCompanyId, periodFrom.Unix(), periodTo.Unix())
+
+	if err != nil {
 		return nil, err
 	}
 
 	var transactions []map[string]interface{}
 
-	for _, order := range orders.Item.Items {
-		netRevenue := float64(0)
-		if order.NetRevenue != nil {
-			netRevenue = order.NetRevenue.Amount
+	totalGrossAmount := float64(0)
+	totalVatAmount := float64(0)
+	totalFeeAmount := float64(0)
+	totalNetAmount := float64(0)
+
+	for _, order := range orders {
+		grossAmount := getOrderViewMoneyAmount(order.GrossRevenue)
+		vatAmount := getOrderViewMoneyAmount(order.TaxFee)
+		feeAmount := getOrderViewMoneyAmount(order.FeesTotal)
+		netAmount := getOrderViewMoneyAmount(order.NetRevenue)
+
+		if order.Type == billingpb.OrderTypeRefund {
+			grossAmount = -1 * getOrderViewMoneyAmount(order.RefundGrossRevenue)
+			vatAmount = -1 * getOrderViewMoneyAmount(order.RefundTaxFee)
+			feeAmount = -1 * getOrderViewMoneyAmount(order.RefundFeesTotal)
+			netAmount = -1 * getOrderViewMoneyAmount(order.RefundReverseRevenue)
 		}
 
+		grossAmount = math.Round(grossAmount*100) / 100
+		vatAmount = math.Round(vatAmount*100) / 100
+		feeAmount = math.Round(feeAmount*100) / 100
+		netAmount = math.Round(netAmount*100) / 100
+
 		datetime, err := ptypes.Timestamp(order.TransactionDate)
 
 		if err != nil {
@@ -123,14 +152,22 @@ func (h *RoyaltyTransactions) Build() (interface{}, error) {
 		}
 
 		transactions = append(transactions, map[string]interface{}{
-			"status":     order.Status,
-			"project":    order.Project.Name["en"],
-			"datetime":   datetime.Format("2006-01-02T15:04:05"),
-			"country":    order.CountryCode,
-			"method":     order.PaymentMethod.Name,
-			"id":         order.Id,
-			"net_amount": math.Round(netRevenue*100) / 100,
+			"status":       order.Status,
+			"project":      order.Project.Name["en"],
+			"datetime":     datetime.Format("2006-01-02T15:04:05"),
+			"country":      order.CountryCode,
+			"method":       order.PaymentMethod.Name,
+			"id":           order.Id,
+			"gross_amount": grossAmount,
+			"vat_amount":   vatAmount,
+			"fee_amount":   feeAmount,
+			"net_amount":   netAmount,
 		})
+
+		totalGrossAmount += grossAmount
+		totalVatAmount += vatAmount
+		totalFeeAmount += feeAmount
+		totalNetAmount += netAmount
 	}
 
 	ocRequest := &billingpb.GetOperatingCompanyRequest{Id: royalty.Item.OperatingCompanyId}
@@ -161,28 +198,6 @@ func (h *RoyaltyTransactions) Build() (interface{}, error) {
 		return nil, err
 	}
 
-	periodFrom, err := ptypes.Timestamp(royalty.Item.PeriodFrom)
-
-	if err != nil {
-		zap.L().Error(
-			"Unable to cast timestamp to time",
-			zap.Error(err),
-			zap.String("period_from", royalty.Item.PeriodFrom.String()),
-		)
-		return nil, err
-	}
-
-	periodTo, err := ptypes.Timestamp(royalty.Item.PeriodTo)
-
-	if err != nil {
-		zap.L().Error(
-			"Unable to cast timestamp to time",
-			zap.Error(err),
-			zap.String("period_to", royalty.Item.PeriodTo.String()),
-		)
-		return nil, err
-	}
-
 	result := map[string]interface{}{
 		"id":                       royalty.Item.Id,
 		"report_date":              date.Format("2006-01-02"),
@@ -194,11 +209,74 @@ func (h *RoyaltyTransactions) Build() (interface{}, error) {
 		"oc_name":                  operatingCompany.Company.Name,
 		"oc_address":               operatingCompany.Company.Address,
 		"transactions":             transactions,
+		"transactions_count":       len(transactions),
+		"total_gross_amount":       math.Round(totalGrossAmount*100) / 100,
+		"total_vat_amount":         math.Round(totalVatAmount*100) / 100,
+		"total_fee_amount":         math.Round(totalFeeAmount*100) / 100,
+		"total_net_amount":         math.Round(totalNetAmount*100) / 100,
 	}
 
 	return result, nil
 }
 
-func (h *RoyaltyTransactions) PostProcess(_ context.Context, _, _ string, _ int64, _ []byte) error {
+// getOrders requests the merchant orders of the operating company and the royalty report period page by page
+// until all of them are received.
+func (h *RoyaltyTransactions) getOrders(
+	ctx context.Context,
+	operatingCompanyId string,
+	dateFrom, dateTo int64,
+) ([]*billingpb.OrderViewPublic, error) {
+	var orders []*billingpb.OrderViewPublic
+
+	for offset := int64(0); ; offset += royaltyTransactionsPageLimit {
+		req := &billingpb.ListOrdersRequest{
+			Merchant:           []string{h.report.MerchantId},
+			OperatingCompanyId: operatingCompanyId,
+			PmDateFrom:         dateFrom,
+			PmDateTo:           dateTo,
+			Limit:              royaltyTransactionsPageLimit,
+			Offset:             offset,
+		}
+		res, err := h.billing.FindAllOrdersPublic(ctx, req)
+
+		if err != nil || res.Status != billingpb.ResponseStatusOk {
+			if err == nil {
+				err = errors.New(res.Message.Message)
+			}
+
+			zap.L().Error(
+				"Unable to get orders",
+				zap.Error(err),
+				zap.String("merchant_id", h.report.MerchantId),
+				zap.Int64("offset", offset),
+			)
+
+			return nil, err
+		}
+
+		if res.Item == nil {
+			break
+		}
+
+		orders = append(orders, res.Item.Items...)
+
+		if int64(len(res.Item.Items)) < royaltyTransactionsPageLimit ||
+			(res.Item.Count > 0 && int64(len(orders)) >= res.Item.Count) {
+			break
+		}
+	}
+
+	return orders, nil
+}
+
+func getOrderViewMoneyAmount(money *billingpb.OrderViewMoney) float64 {
+	if money == nil {
+		return 0
+	}
+
+	return money.Amount
+}
+
+func (h *RoyaltyTransactions) PostProcess(_ context.Context, _, _ string, _ int64, _ FileLoader) error {
 	return nil
 }
@@ -1,12 +1,13 @@
 package builder
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/paysuper/paysuper-proto/go/billingpb"
 	billingMocks "github.com/paysuper/paysuper-proto/go/billingpb/mocks"
-	"github.com/paysuper/paysuper-proto/go/reporterpb"
 	"github.com/paysuper/paysuper-reporter/pkg/errors"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
 	"github.com/stretchr/testify/assert"
 	mock2 "github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -89,10 +90,153 @@ func (suite *RoyaltyTransactionsBuilderTestSuite) TestRoyaltyTransactionsBuilder
 		billing: billing,
 	})
 
-	_, err := h.Build()
+	_, err := h.Build(context.Background())
 	assert.NoError(suite.T(), err)
 }
 
+func (suite *RoyaltyTransactionsBuilderTestSuite) TestRoyaltyTransactionsBuilder_Build_Ok_PeriodAndOperatingCompany() {
+	billing := &billingMocks.BillingService{}
+
+	royalty := suite.getRoyaltyReportTemplate()
+	royalty.OperatingCompanyId = "5dbc5fd0b6a4e8e4a3d1e2a1"
+	royaltyResponse := &billingpb.GetRoyaltyReportResponse{
+		Status: billingpb.ResponseStatusOk,
+		Item:   royalty,
+	}
+	billing.On("GetRoyaltyReport", mock2.Anything, mock2.Anything).Return(royaltyResponse, nil)
+
+	sale := suite.getOrdersTemplate()[0]
+	sale.OperatingCompanyId = royalty.OperatingCompanyId
+	sale.GrossRevenue = &billingpb.OrderViewMoney{Amount: 120}
+	sale.TaxFee = &billingpb.OrderViewMoney{Amount: 20}
+	sale.FeesTotal = &billingpb.OrderViewMoney{Amount: 10}
+	sale.NetRevenue = &billingpb.OrderViewMoney{Amount: 90}
+
+	refund := suite.getOrdersTemplate()[0]
+	refund.Type = billingpb.OrderTypeRefund
+	refund.OperatingCompanyId = royalty.OperatingCompanyId
+	refund.RefundGrossRevenue = &billingpb.OrderViewMoney{Amount: 60}
+	refund.RefundTaxFee = &billingpb.OrderViewMoney{Amount: 10}
+	refund.RefundFeesTotal = &billingpb.OrderViewMoney{Amount: 5}
+	refund.RefundReverseRevenue = &billingpb.OrderViewMoney{Amount: 45}
+
+	periodFrom, _ := ptypes.Timestamp(royalty.PeriodFrom)
+	periodTo, _ := ptypes.Timestamp(royalty.PeriodTo)
+
+	ordersResponse := &billingpb.ListOrdersPublicResponse{
+		Status: billingpb.ResponseStatusOk,
+		Item: &billingpb.ListOrdersPublicResponseItem{
+			Count: 2,
+			Items: []*billingpb.OrderViewPublic{sale, refund},
+		},
+	}
+	billing.
+		On("FindAllOrdersPublic", mock2.Anything, mock2.MatchedBy(func(req *billingpb.ListOrdersRequest) bool {
+			return req.OperatingCompanyId == royalty.OperatingCompanyId &&
+				req.PmDateFrom == periodFrom.Unix() &&
+				req.PmDateTo == periodTo.Unix() &&
+				req.Limit == royaltyTransactionsPageLimit &&
+				req.Offset == 0
+		})).
+		Return(ordersResponse, nil)
+
+	merchantResponse := &billingpb.GetMerchantResponse{
+		Status: billingpb.ResponseStatusOk,
+		Item:   suite.getMerchantTemplate(),
+	}
+	billing.On("GetMerchantBy", mock2.Anything, mock2.Anything).Return(merchantResponse, nil)
+
+	ocResponse := &billingpb.GetOperatingCompanyResponse{
+		Status:  billingpb.ResponseStatusOk,
+		Company: suite.getOperatingCompanyTemplate(),
+	}
+	billing.On("GetOperatingCompany", mock2.Anything, mock2.Anything).Return(ocResponse, nil)
+
+	params, _ := json.Marshal(map[string]interface{}{})
+	h := newRoyaltyTransactionsHandler(&Handler{
+		report:  &reporterpb.ReportFile{MerchantId: "ffffffffffffffffffffffff", Params: params},
+		billing: billing,
+	})
+
+	r, err := h.Build(context.Background())
+	assert.NoError(suite.T(), err)
+
+	result := r.(map[string]interface{})
+	transactions := result["transactions"].([]map[string]interface{})
+	assert.Len(suite.T(), transactions, 2)
+	assert.Equal(suite.T(), float64(120), transactions[0]["gross_amount"])
+	assert.Equal(suite.T(), float64(20), transactions[0]["vat_amount"])
+	assert.Equal(suite.T(), float64(10), transactions[0]["fee_amount"])
+	assert.Equal(suite.T(), float64(90), transactions[0]["net_amount"])
+	assert.Equal(suite.T(), float64(-60), transactions[1]["gross_amount"])
+	assert.Equal(suite.T(), float64(-10), transactions[1]["vat_amount"])
+	assert.Equal(suite.T(), float64(-5), transactions[1]["fee_amount"])
+	assert.Equal(suite.T(), float64(-45), transactions[1]["net_amount"])
+	assert.Equal(suite.T(), 2, result["transactions_count"])
+	assert.Equal(suite.T(), float64(60), result["total_gross_amount"])
+	assert.Equal(suite.T(), float64(10), result["total_vat_amount"])
+	assert.Equal(suite.T(), float64(5), result["total_fee_amount"])
+	assert.Equal(suite.T(), float64(45), result["total_net_amount"])
+}
+
+func (suite *RoyaltyTransactionsBuilderTestSuite) TestRoyaltyTransactionsBuilder_Build_Ok_Pagination() {
+	billing := &billingMocks.BillingService{}
+
+	royaltyResponse := &billingpb.GetRoyaltyReportResponse{
+		Status: billingpb.ResponseStatusOk,
+		Item:   suite.getRoyaltyReportTemplate(),
+	}
+	billing.On("GetRoyaltyReport", mock2.Anything, mock2.Anything).Return(royaltyResponse, nil)
+
+	var items []*billingpb.OrderViewPublic
+
+	for i := int64(0); i < royaltyTransactionsPageLimit; i++ {
+		items = append(items, suite.getOrdersTemplate()...)
+	}
+
+	billing.
+		On("FindAllOrdersPublic", mock2.Anything, mock2.MatchedBy(func(req *billingpb.ListOrdersRequest) bool {
+			return req.Offset == 0
+		})).
+		Return(&billingpb.ListOrdersPublicResponse{
+			Status: billingpb.ResponseStatusOk,
+			Item:   &billingpb.ListOrdersPublicResponseItem{Items: items},
+		}, nil)
+	billing.
+		On("FindAllOrdersPublic", mock2.Anything, mock2.MatchedBy(func(req *billingpb.ListOrdersRequest) bool {
+			return req.Offset == royaltyTransactionsPageLimit
+		})).
+		Return(&billingpb.ListOrdersPublicResponse{
+			Status: billingpb.ResponseStatusOk,
+			Item:   &billingpb.ListOrdersPublicResponseItem{Items: suite.getOrdersTemplate()},
+		}, nil)
+
+	merchantResponse := &billingpb.GetMerchantResponse{
+		Status: billingpb.ResponseStatusOk,
+		Item:   suite.getMerchantTemplate(),
+	}
+	billing.On("GetMerchantBy", mock2.Anything, mock2.Anything).Return(merchantResponse, nil)
+
+	ocResponse := &billingpb.GetOperatingCompanyResponse{
+		Status:  billingpb.ResponseStatusOk,
+		Company: suite.getOperatingCompanyTemplate(),
+	}
+	billing.On("GetOperatingCompany", mock2.Anything, mock2.Anything).Return(ocResponse, nil)
+
+	params, _ := json.Marshal(map[string]interface{}{})
+	h := newRoyaltyTransactionsHandler(&Handler{
+		report:  &reporterpb.ReportFile{MerchantId: "ffffffffffffffffffffffff", Params: params},
+		billing: billing,
+	})
+
+	r, err := h.Build(context.Background())
+	assert.NoError(suite.T(), err)
+	billing.AssertNumberOfCalls(suite.T(), "FindAllOrdersPublic", 2)
+
+	result := r.(map[string]interface{})
+	assert.Equal(suite.T(), int(royaltyTransactionsPageLimit)+1, result["transactions_count"])
+}
+
 func (suite *RoyaltyTransactionsBuilderTestSuite) TestRoyaltyTransactionsBuilder_Build_Error_GetRoyaltyReport() {
 	billing := &billingMocks.BillingService{}
 
@@ -129,7 +273,7 @@ func (suite *RoyaltyTransactionsBuilderTestSuite) TestRoyaltyTransactionsBuilder
 		billing: billing,
 	})
 
-	_, err := h.Build()
+	_, err := h.Build(context.Background())
 	assert.Error(suite.T(), err)
 }
 
@@ -169,7 +313,7 @@ func (suite *RoyaltyTransactionsBuilderTestSuite) TestRoyaltyTransactionsBuilder
 		billing: billing,
 	})
 
-	_, err := h.Build()
+	_, err := h.Build(context.Background())
 	assert.Error(suite.T(), err)
 }
 
@@ -209,7 +353,7 @@ func (suite *RoyaltyTransactionsBuilderTestSuite) TestRoyaltyTransactionsBuilder
 		billing: billing,
 	})
 
-	_, err := h.Build()
+	_, err := h.Build(context.Background())
 	assert.Error(suite.T(), err)
 }
 
@@ -249,7 +393,7 @@ func (suite *RoyaltyTransactionsBuilderTestSuite) TestRoyaltyTransactionsBuilder
 		billing: billing,
 	})
 
-	_, err := h.Build()
+	_, err := h.Build(context.Background())
 	assert.Error(suite.T(), err)
 }
 
@@ -6,8 +6,8 @@ import (
 	"fmt"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/paysuper/paysuper-proto/go/billingpb"
-	"github.com/paysuper/paysuper-proto/go/reporterpb"
 	errs "github.com/paysuper/paysuper-reporter/pkg/errors"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.uber.org/zap"
 	"math"
@@ -63,7 +63,7 @@ func (h *Transactions) Validate() error {
 	return nil
 }
 
-func (h *Transactions) Build() (interface{}, error) {
+func (h *Transactions) Build(ctx context.Context) (interface{}, error) {
 	var logs []map[string]interface{}
 	var status []string
 	var paymentMethods []string
@@ -71,7 +71,6 @@ func (h *Transactions) Build() (interface{}, error) {
 	dateFrom := int64(0)
 	dateTo := int64(0)
 
-	ctx := context.TODO()
 	params, _ := h.GetParams()
 
 	if st, ok := params[reporterpb.ParamsFieldStatus]; ok && st != nil {
@@ -159,6 +158,6 @@ func (h *Transactions) Build() (interface{}, error) {
 	return reports, nil
 }
 
-func (h *Transactions) PostProcess(_ context.Context, _, _ string, _ int64, _ []byte) error {
+func (h *Transactions) PostProcess(_ context.Context, _, _ string, _ int64, _ FileLoader) error {
 	return nil
 }
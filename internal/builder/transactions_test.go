@@ -1,12 +1,13 @@
 package builder
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/paysuper/paysuper-proto/go/billingpb"
 	billingMocks "github.com/paysuper/paysuper-proto/go/billingpb/mocks"
-	"github.com/paysuper/paysuper-proto/go/reporterpb"
 	"github.com/paysuper/paysuper-reporter/pkg/errors"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
 	"github.com/stretchr/testify/assert"
 	mock2 "github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -57,7 +58,7 @@ func (suite *TransactionsBuilderTestSuite) TestTransactionsBuilder_Build_Ok() {
 		billing: billing,
 	})
 
-	_, err := h.Build()
+	_, err := h.Build(context.Background())
 	assert.NoError(suite.T(), err)
 }
 
@@ -77,7 +78,7 @@ func (suite *TransactionsBuilderTestSuite) TestTransactionsBuilder_Build_Error_F
 		billing: billing,
 	})
 
-	_, err := h.Build()
+	_, err := h.Build(context.Background())
 	assert.Error(suite.T(), err)
 }
 
@@ -6,13 +6,16 @@ import (
 	"fmt"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/paysuper/paysuper-proto/go/billingpb"
-	"github.com/paysuper/paysuper-proto/go/reporterpb"
 	errs "github.com/paysuper/paysuper-reporter/pkg/errors"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
 	"go.uber.org/zap"
 	"math"
+	"reflect"
 	"time"
 )
 
+const vatReportsPageLimit = int64(1000)
+
 type Vat DefaultHandler
 
 func newVatHandler(h *Handler) BuildInterface {
@@ -34,35 +37,51 @@ func (h *Vat) Validate() error {
 		return errors.New(errs.ErrorParamCountryNotFound.Message)
 	}
 
+	if st, ok := params[reporterpb.ParamsFieldDateFrom]; ok {
+		if reflect.TypeOf(st).Kind() != reflect.Float64 {
+			return errors.New(errs.ErrorHandlerValidation.Message)
+		}
+	}
+
+	if st, ok := params[reporterpb.ParamsFieldDateTo]; ok {
+		if reflect.TypeOf(st).Kind() != reflect.Float64 {
+			return errors.New(errs.ErrorHandlerValidation.Message)
+		}
+	}
+
+	df, okFrom := params[reporterpb.ParamsFieldDateFrom]
+	dt, okTo := params[reporterpb.ParamsFieldDateTo]
+
+	if okFrom && okTo && df.(float64) > dt.(float64) {
+		return errors.New(errs.ErrorHandlerValidation.Message)
+	}
+
 	return nil
 }
 
-func (h *Vat) Build() (interface{}, error) {
+func (h *Vat) Build(ctx context.Context) (interface{}, error) {
 	var reports []map[string]interface{}
+	var included []*billingpb.VatReport
+	var startDate, endDate time.Time
 
-	ctx := context.TODO()
 	params, _ := h.GetParams()
 	country := fmt.Sprintf("%s", params[reporterpb.ParamsFieldCountry])
 
-	vatsRequest := &billingpb.VatReportsRequest{Country: country, Offset: 0, Limit: 1000}
-	vats, err := h.billing.GetVatReportsForCountry(ctx, vatsRequest)
-
-	if err != nil || vats.Status != billingpb.ResponseStatusOk {
-		if err == nil {
-			err = errors.New(vats.Message.Message)
-		}
+	dateFrom := time.Time{}
+	dateTo := time.Time{}
 
-		zap.L().Error(
-			"Unable to get vats for country",
-			zap.Error(err),
-			zap.String("country", country),
-		)
+	if df, ok := params[reporterpb.ParamsFieldDateFrom]; ok {
+		dateFrom = time.Unix(int64(df.(float64)), 0)
+	}
 
-		return nil, err
+	if dt, ok := params[reporterpb.ParamsFieldDateTo]; ok {
+		dateTo = time.Unix(int64(dt.(float64)), 0)
 	}
 
-	if len(vats.Data.Items) < 1 {
-		return reports, nil
+	vats, err := h.getReports(ctx, country)
+
+	if err != nil {
+		return nil, err
 	}
 
 	grossRevenue := float64(0)
@@ -72,15 +91,8 @@ func (h *Vat) Build() (interface{}, error) {
 	ratesAndFees := float64(0)
 	taxAmount := float64(0)
 
-	for _, vat := range vats.Data.Items {
-		grossRevenue += math.Round(vat.GrossRevenue*100) / 100
-		correction += math.Round(vat.CorrectionAmount*100) / 100
-		totalTransactionsCount += vat.TransactionsCount
-		deduction += math.Round(vat.DeductionAmount*100) / 100
-		ratesAndFees += math.Round(vat.FeesAmount*100) / 100
-		taxAmount += math.Round(vat.VatAmount*100) / 100
-
-		dateFrom, err := ptypes.Timestamp(vat.DateFrom)
+	for _, vat := range vats {
+		periodFrom, err := ptypes.Timestamp(vat.DateFrom)
 
 		if err != nil {
 			zap.L().Error(
@@ -91,7 +103,7 @@ func (h *Vat) Build() (interface{}, error) {
 			return nil, err
 		}
 
-		dateTo, err := ptypes.Timestamp(vat.DateTo)
+		periodTo, err := ptypes.Timestamp(vat.DateTo)
 
 		if err != nil {
 			zap.L().Error(
@@ -102,6 +114,11 @@ func (h *Vat) Build() (interface{}, error) {
 			return nil, err
 		}
 
+		// The vat period is included when it overlaps with the requested dates
+		if (!dateFrom.IsZero() && periodTo.Before(dateFrom)) || (!dateTo.IsZero() && periodFrom.After(dateTo)) {
+			continue
+		}
+
 		payUntilDate, err := ptypes.Timestamp(vat.PayUntilDate)
 
 		if err != nil {
@@ -113,9 +130,25 @@ func (h *Vat) Build() (interface{}, error) {
 			return nil, err
 		}
 
+		if startDate.IsZero() || periodFrom.Before(startDate) {
+			startDate = periodFrom
+		}
+
+		if endDate.IsZero() || periodTo.After(endDate) {
+			endDate = periodTo
+		}
+
+		grossRevenue += math.Round(vat.GrossRevenue*100) / 100
+		correction += math.Round(vat.CorrectionAmount*100) / 100
+		totalTransactionsCount += vat.TransactionsCount
+		deduction += math.Round(vat.DeductionAmount*100) / 100
+		ratesAndFees += math.Round(vat.FeesAmount*100) / 100
+		taxAmount += math.Round(vat.VatAmount*100) / 100
+
+		included = append(included, vat)
 		reports = append(reports, map[string]interface{}{
-			"period_from":             dateFrom.Format("2006-01-02"),
-			"period_to":               dateTo.Format("2006-01-02"),
+			"period_from":             periodFrom.Format("2006-01-02"),
+			"period_to":               periodTo.Format("2006-01-02"),
 			"vat_id":                  vat.Id,
 			"status":                  vat.Status,
 			"payment_date":            payUntilDate.Format("2006-01-02"),
@@ -129,9 +162,13 @@ func (h *Vat) Build() (interface{}, error) {
 		})
 	}
 
+	if len(included) < 1 {
+		return reports, nil
+	}
+
 	res, err := h.billing.GetOperatingCompany(
-		context.Background(),
-		&billingpb.GetOperatingCompanyRequest{Id: vats.Data.Items[0].OperatingCompanyId},
+		ctx,
+		&billingpb.GetOperatingCompanyRequest{Id: included[0].OperatingCompanyId},
 	)
 
 	if err != nil || res.Company == nil {
@@ -142,7 +179,7 @@ func (h *Vat) Build() (interface{}, error) {
 		zap.L().Error(
 			"unable to get operating company",
 			zap.Error(err),
-			zap.String("operating_company_id", vats.Data.Items[0].OperatingCompanyId),
+			zap.String("operating_company_id", included[0].OperatingCompanyId),
 		)
 
 		return nil, err
@@ -150,10 +187,10 @@ func (h *Vat) Build() (interface{}, error) {
 
 	result := map[string]interface{}{
 		"country":                  country,
-		"currency":                 vats.Data.Items[0].Currency,
-		"vat_rate":                 vats.Data.Items[0].VatRate,
-		"start_date":               "2019-10-01",
-		"end_date":                 time.Now().Format("2006-01-02"),
+		"currency":                 included[0].Currency,
+		"vat_rate":                 included[0].VatRate,
+		"start_date":               startDate.Format("2006-01-02"),
+		"end_date":                 endDate.Format("2006-01-02"),
 		"gross_revenue":            grossRevenue,
 		"correction":               correction,
 		"total_transactions_count": totalTransactionsCount,
@@ -169,6 +206,44 @@ func (h *Vat) Build() (interface{}, error) {
 	return result, nil
 }
 
-func (h *Vat) PostProcess(_ context.Context, _, _ string, _ int64, _ []byte) error {
+// getReports requests the vat reports of the country page by page until all of them are received.
+func (h *Vat) getReports(ctx context.Context, country string) ([]*billingpb.VatReport, error) {
+	var vats []*billingpb.VatReport
+
+	for offset := int64(0); ; offset += vatReportsPageLimit {
+		req := &billingpb.VatReportsRequest{Country: country, Offset: offset, Limit: vatReportsPageLimit}
+		res, err := h.billing.GetVatReportsForCountry(ctx, req)
+
+		if err != nil || res.Status != billingpb.ResponseStatusOk {
+			if err == nil {
+				err = errors.New(res.Message.Message)
+			}
+
+			zap.L().Error(
+				"Unable to get vats for country",
+				zap.Error(err),
+				zap.String("country", country),
+				zap.Int64("offset", offset),
+			)
+
+			return nil, err
+		}
+
+		if res.Data == nil {
+			break
+		}
+
+		vats = append(vats, res.Data.Items...)
+
+		if int64(len(res.Data.Items)) < vatReportsPageLimit ||
+			(res.Data.Count > 0 && int64(len(vats)) >= int64(res.Data.Count)) {
+			break
+		}
+	}
+
+	return vats, nil
+}
+
+func (h *Vat) PostProcess(_ context.Context, _, _ string, _ int64, _ FileLoader) error {
 	return nil
 }
@@ -1,12 +1,13 @@
 package builder
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/paysuper/paysuper-proto/go/billingpb"
 	billingMocks "github.com/paysuper/paysuper-proto/go/billingpb/mocks"
-	"github.com/paysuper/paysuper-proto/go/reporterpb"
 	"github.com/paysuper/paysuper-reporter/pkg/errors"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
 	"github.com/stretchr/testify/assert"
 	mock2 "github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -54,6 +55,31 @@ func (suite *VatBuilderTestSuite) TestVatBuilder_Validate_Ok() {
 	assert.NoError(suite.T(), h.Validate())
 }
 
+func (suite *VatBuilderTestSuite) TestVatBuilder_Validate_Error_DateFromInvalid() {
+	params, _ := json.Marshal(map[string]interface{}{
+		reporterpb.ParamsFieldCountry:  "RU",
+		reporterpb.ParamsFieldDateFrom: "2020-01-01",
+	})
+	h := newVatHandler(&Handler{
+		report: &reporterpb.ReportFile{Params: params},
+	})
+
+	assert.EqualError(suite.T(), h.Validate(), errors.ErrorHandlerValidation.Message)
+}
+
+func (suite *VatBuilderTestSuite) TestVatBuilder_Validate_Error_DateFromAfterDateTo() {
+	params, _ := json.Marshal(map[string]interface{}{
+		reporterpb.ParamsFieldCountry:  "RU",
+		reporterpb.ParamsFieldDateFrom: time.Now().Unix(),
+		reporterpb.ParamsFieldDateTo:   time.Now().Add(-time.Hour).Unix(),
+	})
+	h := newVatHandler(&Handler{
+		report: &reporterpb.ReportFile{Params: params},
+	})
+
+	assert.EqualError(suite.T(), h.Validate(), errors.ErrorHandlerValidation.Message)
+}
+
 func (suite *VatBuilderTestSuite) TestVatBuilder_Build_Ok() {
 	billing := &billingMocks.BillingService{}
 
@@ -89,12 +115,136 @@ func (suite *VatBuilderTestSuite) TestVatBuilder_Build_Ok() {
 		billing: billing,
 	})
 
-	r, err := h.Build()
+	r, err := h.Build(context.Background())
 	assert.NoError(suite.T(), err)
 	assert.Len(suite.T(), reportsResponse.Data.Items, 1)
 	assert.NotEmpty(suite.T(), reportsResponse.Data.Items[0].Id, r)
 }
 
+func (suite *VatBuilderTestSuite) TestVatBuilder_Build_Ok_Pagination() {
+	billing := &billingMocks.BillingService{}
+
+	var items []*billingpb.VatReport
+
+	for i := int64(0); i < vatReportsPageLimit; i++ {
+		items = append(items, suite.getReportsTemplate()...)
+	}
+
+	billing.
+		On("GetVatReportsForCountry", mock2.Anything, mock2.MatchedBy(func(req *billingpb.VatReportsRequest) bool {
+			return req.Offset == 0 && req.Limit == vatReportsPageLimit
+		})).
+		Return(&billingpb.VatReportsResponse{
+			Status: billingpb.ResponseStatusOk,
+			Data:   &billingpb.VatReportsPaginate{Items: items},
+		}, nil)
+	billing.
+		On("GetVatReportsForCountry", mock2.Anything, mock2.MatchedBy(func(req *billingpb.VatReportsRequest) bool {
+			return req.Offset == vatReportsPageLimit && req.Limit == vatReportsPageLimit
+		})).
+		Return(&billingpb.VatReportsResponse{
+			Status: billingpb.ResponseStatusOk,
+			Data:   &billingpb.VatReportsPaginate{Items: suite.getReportsTemplate()},
+		}, nil)
+
+	ocResponse := &billingpb.GetOperatingCompanyResponse{
+		Status:  billingpb.ResponseStatusOk,
+		Company: suite.getOperatingCompanyTemplate(),
+	}
+	billing.On("GetOperatingCompany", mock2.Anything, mock2.Anything).Return(ocResponse, nil)
+
+	params, _ := json.Marshal(map[string]interface{}{
+		reporterpb.ParamsFieldCountry: "RU",
+	})
+	h := newVatHandler(&Handler{
+		report:  &reporterpb.ReportFile{Params: params},
+		billing: billing,
+	})
+
+	r, err := h.Build(context.Background())
+	assert.NoError(suite.T(), err)
+	billing.AssertNumberOfCalls(suite.T(), "GetVatReportsForCountry", 2)
+
+	result := r.(map[string]interface{})
+	assert.Len(suite.T(), result["reports"], int(vatReportsPageLimit)+1)
+}
+
+func (suite *VatBuilderTestSuite) TestVatBuilder_Build_Ok_FilterByDates() {
+	billing := &billingMocks.BillingService{}
+
+	reportsResponse := &billingpb.VatReportsResponse{
+		Status: billingpb.ResponseStatusOk,
+		Data: &billingpb.VatReportsPaginate{
+			Items: []*billingpb.VatReport{
+				suite.getReportTemplate("1", "2020-01-01T00:00:00Z", "2020-01-31T23:59:59Z"),
+				suite.getReportTemplate("2", "2020-02-01T00:00:00Z", "2020-02-29T23:59:59Z"),
+				suite.getReportTemplate("3", "2020-03-01T00:00:00Z", "2020-03-31T23:59:59Z"),
+			},
+		},
+	}
+	billing.On("GetVatReportsForCountry", mock2.Anything, mock2.Anything).Return(reportsResponse, nil)
+
+	ocResponse := &billingpb.GetOperatingCompanyResponse{
+		Status:  billingpb.ResponseStatusOk,
+		Company: suite.getOperatingCompanyTemplate(),
+	}
+	billing.On("GetOperatingCompany", mock2.Anything, mock2.Anything).Return(ocResponse, nil)
+
+	dateFrom, _ := time.Parse(time.RFC3339, "2020-02-10T00:00:00Z")
+	dateTo, _ := time.Parse(time.RFC3339, "2020-03-10T00:00:00Z")
+
+	params, _ := json.Marshal(map[string]interface{}{
+		reporterpb.ParamsFieldCountry:  "RU",
+		reporterpb.ParamsFieldDateFrom: dateFrom.Unix(),
+		reporterpb.ParamsFieldDateTo:   dateTo.Unix(),
+	})
+	h := newVatHandler(&Handler{
+		report:  &reporterpb.ReportFile{Params: params},
+		billing: billing,
+	})
+
+	r, err := h.Build(context.Background())
+	assert.NoError(suite.T(), err)
+
+	result := r.(map[string]interface{})
+	reports := result["reports"].([]map[string]interface{})
+	assert.Len(suite.T(), reports, 2)
+	assert.Equal(suite.T(), "2", reports[0]["vat_id"])
+	assert.Equal(suite.T(), "3", reports[1]["vat_id"])
+	assert.Equal(suite.T(), "2020-02-01", result["start_date"])
+	assert.Equal(suite.T(), "2020-03-31", result["end_date"])
+}
+
+func (suite *VatBuilderTestSuite) TestVatBuilder_Build_Ok_FilterByDates_Empty() {
+	billing := &billingMocks.BillingService{}
+
+	reportsResponse := &billingpb.VatReportsResponse{
+		Status: billingpb.ResponseStatusOk,
+		Data: &billingpb.VatReportsPaginate{
+			Items: []*billingpb.VatReport{
+				suite.getReportTemplate("1", "2020-01-01T00:00:00Z", "2020-01-31T23:59:59Z"),
+			},
+		},
+	}
+	billing.On("GetVatReportsForCountry", mock2.Anything, mock2.Anything).Return(reportsResponse, nil)
+
+	dateFrom, _ := time.Parse(time.RFC3339, "2020-02-01T00:00:00Z")
+
+	params, _ := json.Marshal(map[string]interface{}{
+		reporterpb.ParamsFieldCountry:  "RU",
+		reporterpb.ParamsFieldDateFrom: dateFrom.Unix(),
+	})
+	h := newVatHandler(&Handler{
+		report:  &reporterpb.ReportFile{Params: params},
+		billing: billing,
+	})
+
+	r, err := h.Build(context.Background())
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), r)
+	billing.AssertNotCalled(suite.T(), "GetOperatingCompany", mock2.Anything, mock2.Anything)
+}
+
 func (suite *VatBuilderTestSuite) TestVatBuilder_Build_Error_GetVatReportsForCountry() {
 	billing := &billingMocks.BillingService{}
 
@@ -131,7 +281,7 @@ func (suite *VatBuilderTestSuite) TestVatBuilder_Build_Error_GetVatReportsForCou
 		billing: billing,
 	})
 
-	_, err := h.Build()
+	_, err := h.Build(context.Background())
 	assert.Error(suite.T(), err)
 }
 
@@ -171,7 +321,7 @@ func (suite *VatBuilderTestSuite) TestVatBuilder_Build_Error_GetOperatingCompany
 		billing: billing,
 	})
 
-	_, err := h.Build()
+	_, err := h.Build(context.Background())
 	assert.Error(suite.T(), err)
 }
 
@@ -188,6 +338,21 @@ func (suite *VatBuilderTestSuite) getReportsTemplate() []*billingpb.VatReport {
 	}
 }
 
+func (suite *VatBuilderTestSuite) getReportTemplate(id, dateFrom, dateTo string) *billingpb.VatReport {
+	from, _ := time.Parse(time.RFC3339, dateFrom)
+	to, _ := time.Parse(time.RFC3339, dateTo)
+
+	datetimeFrom, _ := ptypes.TimestampProto(from)
+	datetimeTo, _ := ptypes.TimestampProto(to)
+
+	return &billingpb.VatReport{
+		Id:           id,
+		DateFrom:     datetimeFrom,
+		DateTo:       datetimeTo,
+		PayUntilDate: datetimeTo,
+	}
+}
+
 func (suite *VatBuilderTestSuite) getOperatingCompanyTemplate() *billingpb.OperatingCompany {
 	return &billingpb.OperatingCompany{
 		Name:      "Name",
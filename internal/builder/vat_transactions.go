@@ -6,13 +6,15 @@ import (
 	"fmt"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/paysuper/paysuper-proto/go/billingpb"
-	"github.com/paysuper/paysuper-proto/go/reporterpb"
 	errs "github.com/paysuper/paysuper-reporter/pkg/errors"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.uber.org/zap"
 	"math"
 )
 
+const vatTransactionsPageLimit = int64(1000)
+
 type VatTransactions DefaultHandler
 
 func newVatTransactionsHandler(h *Handler) BuildInterface {
@@ -41,8 +43,7 @@ func (h *VatTransactions) Validate() error {
 	return nil
 }
 
-func (h *VatTransactions) Build() (interface{}, error) {
-	ctx := context.TODO()
+func (h *VatTransactions) Build(ctx context.Context) (interface{}, error) {
 	params, _ := h.GetParams()
 	vatId := fmt.Sprintf("%s", params[reporterpb.ParamsFieldId])
 
@@ -63,26 +64,15 @@ func (h *VatTransactions) Build() (interface{}, error) {
 		return nil, err
 	}
 
-	ordersRequest := &billingpb.VatTransactionsRequest{VatReportId: vatId, Offset: 0, Limit: 1000}
-	orders, err := h.billing.GetVatReportTransactions(ctx, ordersRequest)
-
-	if err != nil || orders.Status != billingpb.ResponseStatusOk {
-		if err == nil {
-			err = errors.New(orders.Message.Message)
-		}
-
-		zap.L().Error(
-			"Unable to get vat orders",
-			zap.Error(err),
-			zap.String("vat_id", vatId),
-		)
+	orders, err := h.getTransactions(ctx, vatId)
 
+	if err != nil {
 		return nil, err
 	}
 
 	var transactions []map[string]interface{}
 
-	for _, order := range orders.Data.Items {
+	for _, order := range orders {
 		amount := float64(0)
 		amountCurrency := ""
 
@@ -174,8 +164,19 @@ func (h *VatTransactions) Build() (interface{}, error) {
 		})
 	}
 
+	// The count of the vat report may differ from the exported transactions, e.g. when orders are refunded after
+	// the report calculation, both counts are exported
+	if int64(len(transactions)) != int64(vat.Vat.TransactionsCount) {
+		zap.L().Warn(
+			errs.ErrorVatTransactionsCountMismatch.Message,
+			zap.String("vat_id", vatId),
+			zap.Int("transactions_count", len(transactions)),
+			zap.Int64("vat_transactions_count", int64(vat.Vat.TransactionsCount)),
+		)
+	}
+
 	res, err := h.billing.GetOperatingCompany(
-		context.Background(),
+		ctx,
 		&billingpb.GetOperatingCompanyRequest{Id: vat.Vat.OperatingCompanyId},
 	)
 
@@ -249,11 +250,50 @@ func (h *VatTransactions) Build() (interface{}, error) {
 		"oc_name":                  res.Company.Name,
 		"oc_address":               res.Company.Address,
 		"transactions":             transactions,
+		"transactions_count":       len(transactions),
 	}
 
 	return result, nil
 }
 
-func (h *VatTransactions) PostProcess(_ context.Context, _, _ string, _ int64, _ []byte) error {
+// getTransactions requests the vat report transactions page by page until all of them are received.
+func (h *VatTransactions) getTransactions(ctx context.Context, vatId string) ([]*billingpb.OrderViewPrivate, error) {
+	var orders []*billingpb.OrderViewPrivate
+
+	for offset := int64(0); ; offset += vatTransactionsPageLimit {
+		req := &billingpb.VatTransactionsRequest{VatReportId: vatId, Offset: offset, Limit: vatTransactionsPageLimit}
+		res, err := h.billing.GetVatReportTransactions(ctx, req)
+
+		if err != nil || res.Status != billingpb.ResponseStatusOk {
+			if err == nil {
+				err = errors.New(res.Message.Message)
+			}
+
+			zap.L().Error(
+				"Unable to get vat orders",
+				zap.Error(err),
+				zap.String("vat_id", vatId),
+				zap.Int64("offset", offset),
+			)
+
+			return nil, err
+		}
+
+		if res.Data == nil {
+			break
+		}
+
+		orders = append(orders, res.Data.Items...)
+
+		if int64(len(res.Data.Items)) < vatTransactionsPageLimit ||
+			(res.Data.Count > 0 && int64(len(orders)) >= int64(res.Data.Count)) {
+			break
+		}
+	}
+
+	return orders, nil
+}
+
+func (h *VatTransactions) PostProcess(_ context.Context, _, _ string, _ int64, _ FileLoader) error {
 	return nil
 }
@@ -1,12 +1,14 @@
 package builder
 
 import (
+	"context"
 	"encoding/json"
+	errs "errors"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/paysuper/paysuper-proto/go/billingpb"
 	billingMocks "github.com/paysuper/paysuper-proto/go/billingpb/mocks"
-	"github.com/paysuper/paysuper-proto/go/reporterpb"
 	"github.com/paysuper/paysuper-reporter/pkg/errors"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
 	"github.com/stretchr/testify/assert"
 	mock2 "github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -72,10 +74,105 @@ func (suite *VatTransactionsBuilderTestSuite) TestVatTransactionsBuilder_Build_O
 		billing: billing,
 	})
 
-	_, err := h.Build()
+	_, err := h.Build(context.Background())
 	assert.NoError(suite.T(), err)
 }
 
+func (suite *VatTransactionsBuilderTestSuite) TestVatTransactionsBuilder_Build_Ok_Pagination() {
+	billing := &billingMocks.BillingService{}
+
+	vat := suite.getVatTemplate()
+	vat.TransactionsCount = int32(vatTransactionsPageLimit) + 1
+	vatResponse := &billingpb.VatReportResponse{
+		Status: billingpb.ResponseStatusOk,
+		Vat:    vat,
+	}
+	billing.On("GetVatReport", mock2.Anything, mock2.Anything).Return(vatResponse, nil)
+
+	var items []*billingpb.OrderViewPrivate
+
+	for i := int64(0); i < vatTransactionsPageLimit; i++ {
+		items = append(items, suite.getOrdersTemplate()...)
+	}
+
+	billing.
+		On("GetVatReportTransactions", mock2.Anything, mock2.MatchedBy(func(req *billingpb.VatTransactionsRequest) bool {
+			return req.Offset == 0 && req.Limit == vatTransactionsPageLimit
+		})).
+		Return(&billingpb.PrivateTransactionsResponse{
+			Status: billingpb.ResponseStatusOk,
+			Data:   &billingpb.PrivateTransactionsPaginate{Count: vat.TransactionsCount, Items: items},
+		}, nil)
+	billing.
+		On("GetVatReportTransactions", mock2.Anything, mock2.MatchedBy(func(req *billingpb.VatTransactionsRequest) bool {
+			return req.Offset == vatTransactionsPageLimit && req.Limit == vatTransactionsPageLimit
+		})).
+		Return(&billingpb.PrivateTransactionsResponse{
+			Status: billingpb.ResponseStatusOk,
+			Data:   &billingpb.PrivateTransactionsPaginate{Count: vat.TransactionsCount, Items: suite.getOrdersTemplate()},
+		}, nil)
+
+	ocResponse := &billingpb.GetOperatingCompanyResponse{
+		Status:  billingpb.ResponseStatusOk,
+		Company: suite.getOperatingCompanyTemplate(),
+	}
+	billing.On("GetOperatingCompany", mock2.Anything, mock2.Anything).Return(ocResponse, nil)
+
+	params, _ := json.Marshal(map[string]interface{}{})
+	h := newVatTransactionsHandler(&Handler{
+		report:  &reporterpb.ReportFile{Params: params},
+		billing: billing,
+	})
+
+	r, err := h.Build(context.Background())
+	assert.NoError(suite.T(), err)
+	billing.AssertNumberOfCalls(suite.T(), "GetVatReportTransactions", 2)
+
+	result := r.(map[string]interface{})
+	assert.Len(suite.T(), result["transactions"], int(vat.TransactionsCount))
+	assert.Equal(suite.T(), int(vat.TransactionsCount), result["transactions_count"])
+}
+
+func (suite *VatTransactionsBuilderTestSuite) TestVatTransactionsBuilder_Build_Error_GetVatReportTransactions_NextPage() {
+	billing := &billingMocks.BillingService{}
+
+	vatResponse := &billingpb.VatReportResponse{
+		Status: billingpb.ResponseStatusOk,
+		Vat:    suite.getVatTemplate(),
+	}
+	billing.On("GetVatReport", mock2.Anything, mock2.Anything).Return(vatResponse, nil)
+
+	var items []*billingpb.OrderViewPrivate
+
+	for i := int64(0); i < vatTransactionsPageLimit; i++ {
+		items = append(items, suite.getOrdersTemplate()...)
+	}
+
+	billing.
+		On("GetVatReportTransactions", mock2.Anything, mock2.MatchedBy(func(req *billingpb.VatTransactionsRequest) bool {
+			return req.Offset == 0
+		})).
+		Return(&billingpb.PrivateTransactionsResponse{
+			Status: billingpb.ResponseStatusOk,
+			Data:   &billingpb.PrivateTransactionsPaginate{Items: items},
+		}, nil)
+	billing.
+		On("GetVatReportTransactions", mock2.Anything, mock2.MatchedBy(func(req *billingpb.VatTransactionsRequest) bool {
+			return req.Offset == vatTransactionsPageLimit
+		})).
+		Return(nil, errs.New("error"))
+
+	params, _ := json.Marshal(map[string]interface{}{})
+	h := newVatTransactionsHandler(&Handler{
+		report:  &reporterpb.ReportFile{Params: params},
+		billing: billing,
+	})
+
+	_, err := h.Build(context.Background())
+	assert.Error(suite.T(), err)
+	billing.AssertNotCalled(suite.T(), "GetOperatingCompany", mock2.Anything, mock2.Anything)
+}
+
 func (suite *VatTransactionsBuilderTestSuite) TestVatTransactionsBuilder_Build_Error_GetVatReport() {
 	billing := &billingMocks.BillingService{}
 
@@ -106,7 +203,7 @@ func (suite *VatTransactionsBuilderTestSuite) TestVatTransactionsBuilder_Build_E
 		billing: billing,
 	})
 
-	_, err := h.Build()
+	_, err := h.Build(context.Background())
 	assert.Error(suite.T(), err)
 }
 
@@ -140,7 +237,7 @@ func (suite *VatTransactionsBuilderTestSuite) TestVatTransactionsBuilder_Build_E
 		billing: billing,
 	})
 
-	_, err := h.Build()
+	_, err := h.Build(context.Background())
 	assert.Error(suite.T(), err)
 }
 
@@ -174,18 +271,56 @@ func (suite *VatTransactionsBuilderTestSuite) TestVatTransactionsBuilder_Build_E
 		billing: billing,
 	})
 
-	_, err := h.Build()
+	_, err := h.Build(context.Background())
 	assert.Error(suite.T(), err)
 }
 
+func (suite *VatTransactionsBuilderTestSuite) TestVatTransactionsBuilder_Build_TransactionsCountMismatch() {
+	billing := &billingMocks.BillingService{}
+
+	vat := suite.getVatTemplate()
+	vat.TransactionsCount = 2
+	vatResponse := &billingpb.VatReportResponse{
+		Status: billingpb.ResponseStatusOk,
+		Vat:    vat,
+	}
+	billing.On("GetVatReport", mock2.Anything, mock2.Anything).Return(vatResponse, nil)
+
+	ordersResponse := &billingpb.PrivateTransactionsResponse{
+		Status: billingpb.ResponseStatusOk,
+		Data: &billingpb.PrivateTransactionsPaginate{
+			Items: suite.getOrdersTemplate(),
+		},
+	}
+	billing.On("GetVatReportTransactions", mock2.Anything, mock2.Anything).Return(ordersResponse, nil)
+
+	ocResponse := &billingpb.GetOperatingCompanyResponse{
+		Status:  billingpb.ResponseStatusOk,
+		Company: suite.getOperatingCompanyTemplate(),
+	}
+	billing.On("GetOperatingCompany", mock2.Anything, mock2.Anything).Return(ocResponse, nil)
+
+	params, _ := json.Marshal(map[string]interface{}{})
+	h := newVatTransactionsHandler(&Handler{
+		report:  &reporterpb.ReportFile{Params: params},
+		billing: billing,
+	})
+
+	r, err := h.Build(context.Background())
+	assert.NoError(suite.T(), err)
+	assert.EqualValues(suite.T(), 2, r.(map[string]interface{})["total_transactions_count"])
+	assert.Equal(suite.T(), len(suite.getOrdersTemplate()), r.(map[string]interface{})["transactions_count"])
+}
+
 func (suite *VatTransactionsBuilderTestSuite) getVatTemplate() *billingpb.VatReport {
 	datetime, _ := ptypes.TimestampProto(time.Now())
 
 	return &billingpb.VatReport{
-		Id:        "ffffffffffffffffffffffff",
-		CreatedAt: datetime,
-		DateFrom:  datetime,
-		DateTo:    datetime,
+		Id:                "ffffffffffffffffffffffff",
+		CreatedAt:         datetime,
+		DateFrom:          datetime,
+		DateTo:            datetime,
+		TransactionsCount: 1,
 	}
 }
 
@@ -13,7 +13,7 @@ import (
 )
 
 type CentrifugoInterface interface {
-	Publish(string, interface{}) error
+	Publish(context.Context, string, interface{}) error
 	Info(ctx context.Context) (gocent.InfoResult, error)
 }
 
@@ -40,14 +40,14 @@ func newCentrifugoClient(cfg *config.CentrifugoConfig) CentrifugoInterface {
 		)}
 }
 
-func (c Centrifugo) Publish(channel string, msg interface{}) error {
+func (c Centrifugo) Publish(ctx context.Context, channel string, msg interface{}) error {
 	b, err := json.Marshal(msg)
 
 	if err != nil {
 		return err
 	}
 
-	return c.centrifugoClient.Publish(context.Background(), channel, b)
+	return c.centrifugoClient.Publish(ctx, channel, b)
 }
 
 func (c *Centrifugo) Info(ctx context.Context) (gocent.InfoResult, error) {
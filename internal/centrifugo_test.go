@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"context"
 	"github.com/paysuper/paysuper-reporter/internal/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
@@ -22,10 +23,10 @@ func (suite *CentrifugoTestSuite) TestCentrifugo_newCentrifugoClient_Ok() {
 
 func (suite *CentrifugoTestSuite) TestCentrifugo_Publish_Error_Marshal() {
 	centrifugo := newCentrifugoClient(&config.CentrifugoConfig{})
-	assert.Error(suite.T(), centrifugo.Publish("string", make(chan int)))
+	assert.Error(suite.T(), centrifugo.Publish(context.Background(), "string", make(chan int)))
 }
 
 func (suite *CentrifugoTestSuite) TestCentrifugo_Publish_Error_Client() {
 	centrifugo := newCentrifugoClient(&config.CentrifugoConfig{})
-	assert.Error(suite.T(), centrifugo.Publish("string", "test"))
+	assert.Error(suite.T(), centrifugo.Publish(context.Background(), "string", "test"))
 }
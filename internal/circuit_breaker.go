@@ -0,0 +1,81 @@
+package internal
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var errCircuitBreakerOpen = errors.New("circuit breaker is open, the service is unavailable")
+
+// CircuitBreaker stops the calls to the failing service after the threshold of consecutive failures.
+// When the cooldown passes a single trial call is allowed: on success the breaker is closed,
+// on failure it is opened again for the next cooldown.
+type CircuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openedAt  time.Time
+	trial     bool
+	now       func() time.Time
+	mx        sync.Mutex
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// Allow returns error when the call must not be made.
+func (b *CircuitBreaker) Allow() error {
+	if b.threshold <= 0 {
+		return nil
+	}
+
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	if b.failures < b.threshold {
+		return nil
+	}
+
+	if b.trial || b.now().Sub(b.openedAt) < b.cooldown {
+		return errCircuitBreakerOpen
+	}
+
+	b.trial = true
+
+	return nil
+}
+
+func (b *CircuitBreaker) Success() {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	b.failures = 0
+	b.trial = false
+}
+
+// Release ends the trial call without changing the state of the breaker, when the call result says nothing
+// about the service health.
+func (b *CircuitBreaker) Release() {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	b.trial = false
+}
+
+func (b *CircuitBreaker) Failure() {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	b.failures++
+	b.trial = false
+
+	if b.failures >= b.threshold {
+		b.openedAt = b.now()
+	}
+}
@@ -0,0 +1,73 @@
+package internal
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type CircuitBreakerTestSuite struct {
+	suite.Suite
+	now     time.Time
+	breaker *CircuitBreaker
+}
+
+func Test_CircuitBreaker(t *testing.T) {
+	suite.Run(t, new(CircuitBreakerTestSuite))
+}
+
+func (suite *CircuitBreakerTestSuite) SetupTest() {
+	suite.now = time.Now()
+	suite.breaker = newCircuitBreaker(2, time.Minute)
+	suite.breaker.now = func() time.Time { return suite.now }
+}
+
+func (suite *CircuitBreakerTestSuite) TestCircuitBreaker_Open() {
+	suite.breaker.Failure()
+	assert.NoError(suite.T(), suite.breaker.Allow())
+
+	suite.breaker.Failure()
+	assert.Equal(suite.T(), errCircuitBreakerOpen, suite.breaker.Allow())
+}
+
+func (suite *CircuitBreakerTestSuite) TestCircuitBreaker_Success_ResetFailures() {
+	suite.breaker.Failure()
+	suite.breaker.Success()
+	suite.breaker.Failure()
+	assert.NoError(suite.T(), suite.breaker.Allow())
+}
+
+func (suite *CircuitBreakerTestSuite) TestCircuitBreaker_Trial_Success() {
+	suite.breaker.Failure()
+	suite.breaker.Failure()
+
+	suite.now = suite.now.Add(time.Minute)
+	assert.NoError(suite.T(), suite.breaker.Allow())
+	assert.Equal(suite.T(), errCircuitBreakerOpen, suite.breaker.Allow())
+
+	suite.breaker.Success()
+	assert.NoError(suite.T(), suite.breaker.Allow())
+	assert.NoError(suite.T(), suite.breaker.Allow())
+}
+
+func (suite *CircuitBreakerTestSuite) TestCircuitBreaker_Trial_Failure() {
+	suite.breaker.Failure()
+	suite.breaker.Failure()
+
+	suite.now = suite.now.Add(time.Minute)
+	assert.NoError(suite.T(), suite.breaker.Allow())
+
+	suite.breaker.Failure()
+	assert.Equal(suite.T(), errCircuitBreakerOpen, suite.breaker.Allow())
+
+	suite.now = suite.now.Add(time.Minute)
+	assert.NoError(suite.T(), suite.breaker.Allow())
+}
+
+func (suite *CircuitBreakerTestSuite) TestCircuitBreaker_Disabled() {
+	breaker := newCircuitBreaker(0, time.Minute)
+	breaker.Failure()
+	breaker.Failure()
+	assert.NoError(suite.T(), breaker.Allow())
+}
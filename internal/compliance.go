@@ -0,0 +1,514 @@
+package internal
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/paysuper/paysuper-reporter/pkg"
+	reporterErrors "github.com/paysuper/paysuper-reporter/pkg/errors"
+	"github.com/paysuper/paysuper-reporter/pkg/proto"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	"io"
+	"os"
+	"time"
+)
+
+const (
+	CommandCompliance = "compliance"
+
+	complianceCommandHold    = "hold"
+	complianceCommandRelease = "release"
+	complianceCommandErase   = "erase"
+)
+
+var errComplianceUnknownCommand = errors.New("unknown compliance command, expected one of: hold, release, erase")
+
+// SetLegalHold stops the retention sweeper from purging the report files of the merchant, or the single report
+// file when the file identifier is set, until the hold is released.
+func (app *Application) SetLegalHold(
+	ctx context.Context,
+	req *reporterpb.SetLegalHoldRequest,
+	res *reporterpb.LegalHoldResponse,
+) error {
+	if _, err := primitive.ObjectIDFromHex(req.MerchantId); err != nil {
+		res.Status = pkg.ResponseStatusBadData
+		res.Message = reporterErrors.ErrorParamMerchantIdNotFound
+
+		return nil
+	}
+
+	if req.Reason == "" {
+		res.Status = pkg.ResponseStatusBadData
+		res.Message = reporterErrors.ErrorLegalHoldReasonEmpty
+
+		return nil
+	}
+
+	if req.FileId != "" {
+		if _, status, msg := app.getReportFileJob(ctx, req.FileId, req.MerchantId); msg != nil {
+			res.Status = status
+			res.Message = msg
+
+			return nil
+		}
+	}
+
+	hold := &proto.LegalHold{
+		Id:         primitive.NewObjectID(),
+		MerchantId: req.MerchantId,
+		FileId:     req.FileId,
+		Reason:     req.Reason,
+		UserId:     req.UserId,
+		CreatedAt:  time.Now(),
+	}
+
+	if err := app.legalHoldRepository.Insert(ctx, hold); err != nil {
+		res.Status = pkg.ResponseStatusSystemError
+		res.Message = reporterErrors.ErrorDatabaseQueryFailed
+
+		return nil
+	}
+
+	zap.L().Info(
+		"Legal hold set",
+		zap.String("id", hold.Id.Hex()),
+		zap.String("merchant_id", hold.MerchantId),
+		zap.String("file_id", hold.FileId),
+		zap.String("user_id", hold.UserId),
+	)
+
+	res.Status = pkg.ResponseStatusOk
+	res.Item = newLegalHold(hold)
+
+	return nil
+}
+
+// ReleaseLegalHold releases the active hold, the released hold is kept for the audit.
+func (app *Application) ReleaseLegalHold(
+	ctx context.Context,
+	req *reporterpb.ReleaseLegalHoldRequest,
+	res *reporterpb.LegalHoldResponse,
+) error {
+	if _, err := primitive.ObjectIDFromHex(req.HoldId); err != nil {
+		res.Status = pkg.ResponseStatusNotFound
+		res.Message = reporterErrors.ErrorLegalHoldNotFound
+
+		return nil
+	}
+
+	hold, err := app.legalHoldRepository.Release(ctx, req.HoldId, req.MerchantId, req.UserId)
+
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			res.Status = pkg.ResponseStatusNotFound
+			res.Message = reporterErrors.ErrorLegalHoldNotFound
+
+			return nil
+		}
+
+		res.Status = pkg.ResponseStatusSystemError
+		res.Message = reporterErrors.ErrorDatabaseQueryFailed
+
+		return nil
+	}
+
+	zap.L().Info(
+		"Legal hold released",
+		zap.String("id", req.HoldId),
+		zap.String("merchant_id", req.MerchantId),
+		zap.String("user_id", req.UserId),
+	)
+
+	res.Status = pkg.ResponseStatusOk
+	res.Item = newLegalHold(hold)
+
+	return nil
+}
+
+// EraseMerchantFiles removes the stored report files and the temporary files of the merchant and scrubs the params
+// of their jobs. Files under the legal hold are retained. The result of every file is recorded to the receipt.
+func (app *Application) EraseMerchantFiles(
+	ctx context.Context,
+	req *reporterpb.EraseMerchantFilesRequest,
+	res *reporterpb.EraseMerchantFilesResponse,
+) error {
+	if _, err := primitive.ObjectIDFromHex(req.MerchantId); err != nil {
+		res.Status = pkg.ResponseStatusBadData
+		res.Message = reporterErrors.ErrorParamMerchantIdNotFound
+
+		return nil
+	}
+
+	if req.Reason == "" {
+		res.Status = pkg.ResponseStatusBadData
+		res.Message = reporterErrors.ErrorErasureReasonEmpty
+
+		return nil
+	}
+
+	receipt, err := app.eraseMerchantFiles(ctx, req)
+
+	if err != nil {
+		zap.L().Error(
+			reporterErrors.ErrorMerchantErasureFailed.Message,
+			zap.Error(err),
+			zap.String("merchant_id", req.MerchantId),
+		)
+		res.Status = pkg.ResponseStatusSystemError
+		res.Message = reporterErrors.ErrorMerchantErasureFailed
+
+		return nil
+	}
+
+	res.Status = pkg.ResponseStatusOk
+	res.Item = newErasureReceipt(receipt)
+
+	return nil
+}
+
+func (app *Application) eraseMerchantFiles(
+	ctx context.Context,
+	req *reporterpb.EraseMerchantFilesRequest,
+) (*proto.ErasureReceipt, error) {
+	holds, err := app.legalHoldRepository.FindActive(ctx, req.MerchantId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	merchantHold := false
+	heldFiles := make(map[string]bool)
+
+	for _, hold := range holds {
+		if hold.FileId == "" {
+			merchantHold = true
+			continue
+		}
+
+		heldFiles[hold.FileId] = true
+	}
+
+	receipt := &proto.ErasureReceipt{
+		Id:         primitive.NewObjectID(),
+		MerchantId: req.MerchantId,
+		UserId:     req.UserId,
+		Reason:     req.Reason,
+		Items:      []*proto.ErasureReceiptItem{},
+		CreatedAt:  time.Now(),
+	}
+	seen := make(map[string]bool)
+	purged := make(map[string]bool)
+
+	// Erased jobs are kept, so the offset is not shifted by the erasure. Jobs created during the erasure shift
+	// the pages, the jobs found twice are skipped.
+	for offset := int64(0); ; offset += pkg.ListFilesMaxLimit {
+		jobs, err := app.reportFileRepository.Find(ctx, req.MerchantId, "", "", offset, pkg.ListFilesMaxLimit)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, job := range jobs {
+			id := job.Id.Hex()
+
+			if seen[id] {
+				continue
+			}
+
+			seen[id] = true
+
+			// The dead letter messages of the job erased before are purged again, as the purge may have failed.
+			if job.Status == pkg.ReportFileStatusErased {
+				purged[id] = true
+				continue
+			}
+
+			item := app.eraseJob(ctx, job, merchantHold || heldFiles[id])
+			receipt.Items = append(receipt.Items, item)
+
+			if item.Result != pkg.ErasureResultRetained {
+				purged[id] = true
+			}
+
+			switch item.Result {
+			case pkg.ErasureResultErased:
+				receipt.Erased++
+			case pkg.ErasureResultRetained:
+				receipt.Retained++
+			default:
+				receipt.Failed++
+			}
+		}
+
+		if int64(len(jobs)) < pkg.ListFilesMaxLimit {
+			break
+		}
+	}
+
+	receipt.Status = pkg.ErasureStatusCompleted
+
+	if receipt.Failed > 0 {
+		receipt.Status = pkg.ErasureStatusPartial
+	}
+
+	// The dead letter messages carry the params of the report files, they are removed, so the requeue can't
+	// replay the erased report files.
+	if len(purged) > 0 {
+		count, err := app.deadLetterQueue.Purge(func(msg *reporterpb.DeadLetterMessage) bool {
+			return purged[msg.FileId]
+		})
+		receipt.DeadLetterPurged = int32(count)
+
+		if err != nil {
+			zap.L().Error(
+				"Unable to purge dead letter messages of the erased report files",
+				zap.Error(err),
+				zap.String("merchant_id", req.MerchantId),
+			)
+			receipt.Status = pkg.ErasureStatusPartial
+		}
+	}
+
+	receipt.CompletedAt = time.Now()
+
+	if err = app.erasureReceiptRepository.Insert(ctx, receipt); err != nil {
+		return nil, err
+	}
+
+	zap.L().Info(
+		"Merchant report files erased",
+		zap.String("receipt_id", receipt.Id.Hex()),
+		zap.String("merchant_id", receipt.MerchantId),
+		zap.String("user_id", receipt.UserId),
+		zap.Int32("erased", receipt.Erased),
+		zap.Int32("retained", receipt.Retained),
+		zap.Int32("failed", receipt.Failed),
+		zap.Int32("dead_letter_purged", receipt.DeadLetterPurged),
+	)
+
+	return receipt, nil
+}
+
+// eraseJob erases the files of the job. The stored file is deleted before the job is scrubbed, so the failed
+// erasure keeps the file name and is repeated by the next erasure request.
+func (app *Application) eraseJob(ctx context.Context, job *proto.ReportFileJob, held bool) *proto.ErasureReceiptItem {
+	id := job.Id.Hex()
+	item := newErasureReceiptItem(job)
+
+	if held {
+		item.Result = pkg.ErasureResultRetained
+		return item
+	}
+
+	failed := func(err error) *proto.ErasureReceiptItem {
+		zap.L().Error("Unable to erase report file", zap.Error(err), zap.String("id", id))
+		item.Result = pkg.ErasureResultFailed
+		item.Error = err.Error()
+
+		return item
+	}
+
+	// Jobs in progress are cancelled. The report pipeline records the uploaded file only to the active job and
+	// deletes the upload of the stopped one, so the file recorded before the cancellation is found by the job
+	// read after it.
+	if isStatusIn(job.Status, inFlightStatuses) {
+		if err := app.reportFileRepository.Cancel(ctx, id); err != nil && err != mongo.ErrNoDocuments {
+			return failed(err)
+		}
+
+		current, err := app.reportFileRepository.GetById(ctx, id)
+
+		if err != nil {
+			return failed(err)
+		}
+
+		job = current
+		item = newErasureReceiptItem(job)
+	}
+
+	// Temporary files exist only on the instance rendering the job, the other instances find nothing to remove
+	filePath := getReportTempFilePath(id, job.FileType)
+
+	if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+		return failed(err)
+	}
+
+	if job.FileName != "" {
+		storage, err := app.storages.GetStorage(job.Bucket, job.ReportType)
+
+		if err != nil {
+			return failed(err)
+		}
+
+		if err = storage.Delete(ctx, job.FileName); err != nil {
+			return failed(err)
+		}
+	}
+
+	if err := app.reportFileRepository.Erase(ctx, id); err != nil && err != mongo.ErrNoDocuments {
+		return failed(err)
+	}
+
+	return item
+}
+
+// RunComplianceCommand executes the operator command to set or release the legal hold or to erase the report
+// files of the merchant. The error is returned to the caller, so it can stop the application before the exit.
+func (app *Application) RunComplianceCommand(args []string) error {
+	err := app.runComplianceCommand(args, os.Stdout)
+
+	if err != nil {
+		zap.L().Error("Compliance command failed", zap.Error(err))
+	}
+
+	return err
+}
+
+func (app *Application) runComplianceCommand(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errComplianceUnknownCommand
+	}
+
+	fs := flag.NewFlagSet(CommandCompliance+" "+args[0], flag.ContinueOnError)
+	merchantId := fs.String("merchant_id", "", "merchant identifier")
+	fileId := fs.String("file_id", "", "hold only the report file of the merchant")
+	holdId := fs.String("hold_id", "", "identifier of the legal hold to release")
+	reason := fs.String("reason", "", "reason of the legal hold or the erasure")
+	userId := fs.String("user_id", "", "identifier of the operator")
+
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	var (
+		status  int32
+		message *reporterpb.ResponseErrorMessage
+		item    interface{}
+	)
+
+	switch args[0] {
+	case complianceCommandHold:
+		res := &reporterpb.LegalHoldResponse{}
+		req := &reporterpb.SetLegalHoldRequest{MerchantId: *merchantId, FileId: *fileId, Reason: *reason, UserId: *userId}
+		_ = app.SetLegalHold(ctx, req, res)
+		status, message, item = res.Status, res.Message, res.Item
+	case complianceCommandRelease:
+		res := &reporterpb.LegalHoldResponse{}
+		req := &reporterpb.ReleaseLegalHoldRequest{HoldId: *holdId, MerchantId: *merchantId, UserId: *userId}
+		_ = app.ReleaseLegalHold(ctx, req, res)
+		status, message, item = res.Status, res.Message, res.Item
+	case complianceCommandErase:
+		res := &reporterpb.EraseMerchantFilesResponse{}
+		req := &reporterpb.EraseMerchantFilesRequest{MerchantId: *merchantId, Reason: *reason, UserId: *userId}
+		_ = app.EraseMerchantFiles(ctx, req, res)
+		status, message, item = res.Status, res.Message, res.Item
+	default:
+		return errComplianceUnknownCommand
+	}
+
+	if status != pkg.ResponseStatusOk {
+		return fmt.Errorf("%s: %s", message.Code, message.Message)
+	}
+
+	b, err := json.Marshal(item)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(out, string(b))
+	return err
+}
+
+// getErasureKeyHash returns the hash of the storage key recorded to the erasure receipt instead of the key,
+// which may contain the merchant data.
+func getErasureKeyHash(key string) string {
+	if key == "" {
+		return ""
+	}
+
+	hash := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(hash[:])
+}
+
+func isStatusIn(status string, statuses []string) bool {
+	for _, v := range statuses {
+		if v == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newErasureReceiptItem(job *proto.ReportFileJob) *proto.ErasureReceiptItem {
+	return &proto.ErasureReceiptItem{
+		FileId:     job.Id.Hex(),
+		ReportType: job.ReportType,
+		Bucket:     job.Bucket,
+		KeyHash:    getErasureKeyHash(job.FileName),
+		Size:       job.Size,
+		Result:     pkg.ErasureResultErased,
+	}
+}
+
+func newLegalHold(hold *proto.LegalHold) *reporterpb.LegalHold {
+	item := &reporterpb.LegalHold{
+		Id:         hold.Id.Hex(),
+		MerchantId: hold.MerchantId,
+		FileId:     hold.FileId,
+		Reason:     hold.Reason,
+		UserId:     hold.UserId,
+		ReleasedBy: hold.ReleasedBy,
+	}
+
+	item.CreatedAt, _ = ptypes.TimestampProto(hold.CreatedAt)
+
+	if hold.ReleasedAt != nil {
+		item.ReleasedAt, _ = ptypes.TimestampProto(*hold.ReleasedAt)
+	}
+
+	return item
+}
+
+func newErasureReceipt(receipt *proto.ErasureReceipt) *reporterpb.ErasureReceipt {
+	item := &reporterpb.ErasureReceipt{
+		Id:               receipt.Id.Hex(),
+		MerchantId:       receipt.MerchantId,
+		UserId:           receipt.UserId,
+		Reason:           receipt.Reason,
+		Status:           receipt.Status,
+		Erased:           receipt.Erased,
+		Retained:         receipt.Retained,
+		Failed:           receipt.Failed,
+		DeadLetterPurged: receipt.DeadLetterPurged,
+	}
+
+	for _, v := range receipt.Items {
+		item.Items = append(item.Items, &reporterpb.ErasureReceiptItem{
+			FileId:     v.FileId,
+			ReportType: v.ReportType,
+			Bucket:     v.Bucket,
+			KeyHash:    v.KeyHash,
+			Size:       v.Size,
+			Result:     v.Result,
+			Error:      v.Error,
+		})
+	}
+
+	item.CreatedAt, _ = ptypes.TimestampProto(receipt.CreatedAt)
+	item.CompletedAt, _ = ptypes.TimestampProto(receipt.CompletedAt)
+
+	return item
+}
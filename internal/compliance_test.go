@@ -0,0 +1,328 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	errs "errors"
+	"github.com/paysuper/paysuper-reporter/internal/config"
+	"github.com/paysuper/paysuper-reporter/internal/mocks"
+	"github.com/paysuper/paysuper-reporter/pkg"
+	"github.com/paysuper/paysuper-reporter/pkg/errors"
+	"github.com/paysuper/paysuper-reporter/pkg/proto"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+const complianceMerchantId = "ffffffffffffffffffffffff"
+
+type ComplianceTestSuite struct {
+	suite.Suite
+	app                      *Application
+	reportFileRepository     *mocks.ReportFileRepositoryInterface
+	legalHoldRepository      *mocks.LegalHoldRepositoryInterface
+	erasureReceiptRepository *mocks.ErasureReceiptRepositoryInterface
+	deadLetterQueue          *mocks.DeadLetterQueueInterface
+	storage                  *mocks.StorageInterface
+}
+
+func Test_Compliance(t *testing.T) {
+	suite.Run(t, new(ComplianceTestSuite))
+}
+
+func (suite *ComplianceTestSuite) SetupTest() {
+	suite.reportFileRepository = &mocks.ReportFileRepositoryInterface{}
+	suite.legalHoldRepository = &mocks.LegalHoldRepositoryInterface{}
+	suite.erasureReceiptRepository = &mocks.ErasureReceiptRepositoryInterface{}
+	suite.deadLetterQueue = &mocks.DeadLetterQueueInterface{}
+	suite.storage = &mocks.StorageInterface{}
+	suite.storage.On("Bucket").Return("reports")
+
+	storages, err := newStorageRouter(map[string]StorageInterface{pkg.StorageTargetReports: suite.storage}, nil)
+	assert.NoError(suite.T(), err)
+
+	suite.app = &Application{
+		cfg:                      &config.Config{},
+		reportFileRepository:     suite.reportFileRepository,
+		legalHoldRepository:      suite.legalHoldRepository,
+		erasureReceiptRepository: suite.erasureReceiptRepository,
+		deadLetterQueue:          suite.deadLetterQueue,
+		storages:                 storages,
+	}
+}
+
+func (suite *ComplianceTestSuite) TestCompliance_SetLegalHold_Ok() {
+	job := suite.getJob(pkg.ReportFileStatusCompleted, "report.pdf")
+	suite.reportFileRepository.On("GetById", mock.Anything, job.Id.Hex()).Return(job, nil)
+	suite.legalHoldRepository.On("Insert", mock.Anything, mock.Anything).Return(nil)
+
+	req := &reporterpb.SetLegalHoldRequest{
+		MerchantId: complianceMerchantId,
+		FileId:     job.Id.Hex(),
+		Reason:     "litigation",
+		UserId:     "fffffffffffffffffffffff1",
+	}
+	res := &reporterpb.LegalHoldResponse{}
+	assert.NoError(suite.T(), suite.app.SetLegalHold(context.TODO(), req, res))
+	assert.Equal(suite.T(), pkg.ResponseStatusOk, res.Status)
+	assert.NotEmpty(suite.T(), res.Item.Id)
+	assert.Equal(suite.T(), job.Id.Hex(), res.Item.FileId)
+	assert.Equal(suite.T(), "litigation", res.Item.Reason)
+	assert.NotNil(suite.T(), res.Item.CreatedAt)
+	assert.Nil(suite.T(), res.Item.ReleasedAt)
+}
+
+func (suite *ComplianceTestSuite) TestCompliance_SetLegalHold_Error_Validation() {
+	res := &reporterpb.LegalHoldResponse{}
+	req := &reporterpb.SetLegalHoldRequest{MerchantId: "merchant", Reason: "litigation"}
+	assert.NoError(suite.T(), suite.app.SetLegalHold(context.TODO(), req, res))
+	assert.Equal(suite.T(), pkg.ResponseStatusBadData, res.Status)
+	assert.Equal(suite.T(), errors.ErrorParamMerchantIdNotFound, res.Message)
+
+	res = &reporterpb.LegalHoldResponse{}
+	req = &reporterpb.SetLegalHoldRequest{MerchantId: complianceMerchantId}
+	assert.NoError(suite.T(), suite.app.SetLegalHold(context.TODO(), req, res))
+	assert.Equal(suite.T(), pkg.ResponseStatusBadData, res.Status)
+	assert.Equal(suite.T(), errors.ErrorLegalHoldReasonEmpty, res.Message)
+
+	job := suite.getJob(pkg.ReportFileStatusCompleted, "report.pdf")
+	job.MerchantId = "fffffffffffffffffffffff1"
+	suite.reportFileRepository.On("GetById", mock.Anything, job.Id.Hex()).Return(job, nil)
+
+	res = &reporterpb.LegalHoldResponse{}
+	req = &reporterpb.SetLegalHoldRequest{MerchantId: complianceMerchantId, FileId: job.Id.Hex(), Reason: "litigation"}
+	assert.NoError(suite.T(), suite.app.SetLegalHold(context.TODO(), req, res))
+	assert.Equal(suite.T(), pkg.ResponseStatusNotFound, res.Status)
+	assert.Equal(suite.T(), errors.ErrorReportFileNotFound, res.Message)
+	suite.legalHoldRepository.AssertNotCalled(suite.T(), "Insert", mock.Anything, mock.Anything)
+}
+
+func (suite *ComplianceTestSuite) TestCompliance_SetLegalHold_Error_Insert() {
+	suite.legalHoldRepository.On("Insert", mock.Anything, mock.Anything).Return(errs.New("error"))
+
+	res := &reporterpb.LegalHoldResponse{}
+	req := &reporterpb.SetLegalHoldRequest{MerchantId: complianceMerchantId, Reason: "litigation"}
+	assert.NoError(suite.T(), suite.app.SetLegalHold(context.TODO(), req, res))
+	assert.Equal(suite.T(), pkg.ResponseStatusSystemError, res.Status)
+	assert.Equal(suite.T(), errors.ErrorDatabaseQueryFailed, res.Message)
+}
+
+func (suite *ComplianceTestSuite) TestCompliance_ReleaseLegalHold_Ok() {
+	releasedAt := time.Now()
+	hold := &proto.LegalHold{
+		Id:         primitive.NewObjectID(),
+		MerchantId: complianceMerchantId,
+		Reason:     "litigation",
+		CreatedAt:  releasedAt.Add(-time.Hour),
+		ReleasedAt: &releasedAt,
+		ReleasedBy: "fffffffffffffffffffffff1",
+	}
+	suite.legalHoldRepository.
+		On("Release", mock.Anything, hold.Id.Hex(), complianceMerchantId, "fffffffffffffffffffffff1").
+		Return(hold, nil)
+
+	res := &reporterpb.LegalHoldResponse{}
+	req := &reporterpb.ReleaseLegalHoldRequest{
+		HoldId:     hold.Id.Hex(),
+		MerchantId: complianceMerchantId,
+		UserId:     "fffffffffffffffffffffff1",
+	}
+	assert.NoError(suite.T(), suite.app.ReleaseLegalHold(context.TODO(), req, res))
+	assert.Equal(suite.T(), pkg.ResponseStatusOk, res.Status)
+	assert.Equal(suite.T(), hold.Id.Hex(), res.Item.Id)
+	assert.NotNil(suite.T(), res.Item.ReleasedAt)
+	assert.Equal(suite.T(), "fffffffffffffffffffffff1", res.Item.ReleasedBy)
+}
+
+func (suite *ComplianceTestSuite) TestCompliance_ReleaseLegalHold_Error() {
+	res := &reporterpb.LegalHoldResponse{}
+	assert.NoError(suite.T(), suite.app.ReleaseLegalHold(context.TODO(), &reporterpb.ReleaseLegalHoldRequest{HoldId: "id"}, res))
+	assert.Equal(suite.T(), pkg.ResponseStatusNotFound, res.Status)
+	assert.Equal(suite.T(), errors.ErrorLegalHoldNotFound, res.Message)
+
+	notFoundId := primitive.NewObjectID().Hex()
+	failedId := primitive.NewObjectID().Hex()
+	suite.legalHoldRepository.On("Release", mock.Anything, notFoundId, mock.Anything, mock.Anything).Return(nil, mongo.ErrNoDocuments)
+	suite.legalHoldRepository.On("Release", mock.Anything, failedId, mock.Anything, mock.Anything).Return(nil, errs.New("error"))
+
+	res = &reporterpb.LegalHoldResponse{}
+	assert.NoError(suite.T(), suite.app.ReleaseLegalHold(context.TODO(), &reporterpb.ReleaseLegalHoldRequest{HoldId: notFoundId}, res))
+	assert.Equal(suite.T(), pkg.ResponseStatusNotFound, res.Status)
+	assert.Equal(suite.T(), errors.ErrorLegalHoldNotFound, res.Message)
+
+	res = &reporterpb.LegalHoldResponse{}
+	assert.NoError(suite.T(), suite.app.ReleaseLegalHold(context.TODO(), &reporterpb.ReleaseLegalHoldRequest{HoldId: failedId}, res))
+	assert.Equal(suite.T(), pkg.ResponseStatusSystemError, res.Status)
+	assert.Equal(suite.T(), errors.ErrorDatabaseQueryFailed, res.Message)
+}
+
+func (suite *ComplianceTestSuite) TestCompliance_EraseMerchantFiles_Ok() {
+	completed := suite.getJob(pkg.ReportFileStatusCompleted, "report1.pdf")
+	held := suite.getJob(pkg.ReportFileStatusCompleted, "report2.pdf")
+	rendering := suite.getJob(pkg.ReportFileStatusRendering, "")
+	erased := suite.getJob(pkg.ReportFileStatusErased, "")
+	failed := suite.getJob(pkg.ReportFileStatusFailed, "report3.pdf")
+	jobs := []*proto.ReportFileJob{completed, held, rendering, erased, failed}
+
+	holds := []*proto.LegalHold{{MerchantId: complianceMerchantId, FileId: held.Id.Hex()}}
+	suite.legalHoldRepository.On("FindActive", mock.Anything, complianceMerchantId).Return(holds, nil)
+	suite.reportFileRepository.
+		On("Find", mock.Anything, complianceMerchantId, "", "", int64(0), pkg.ListFilesMaxLimit).
+		Return(jobs, nil)
+	suite.reportFileRepository.On("Cancel", mock.Anything, rendering.Id.Hex()).Return(nil)
+	uploaded := *rendering
+	uploaded.Status = pkg.ReportFileStatusCancelled
+	uploaded.Bucket = "reports"
+	uploaded.FileName = "report4.pdf"
+	suite.reportFileRepository.On("GetById", mock.Anything, rendering.Id.Hex()).Return(&uploaded, nil)
+	suite.storage.On("Delete", mock.Anything, "report4.pdf").Return(nil)
+	suite.reportFileRepository.On("Erase", mock.Anything, mock.Anything).Return(nil)
+	suite.storage.On("Delete", mock.Anything, "report1.pdf").Return(nil)
+	suite.storage.On("Delete", mock.Anything, "report3.pdf").Return(errs.New("error"))
+	suite.erasureReceiptRepository.On("Insert", mock.Anything, mock.Anything).Return(nil)
+
+	messages := []*reporterpb.DeadLetterMessage{
+		{FileId: completed.Id.Hex()},
+		{FileId: held.Id.Hex()},
+		{FileId: erased.Id.Hex()},
+		{FileId: failed.Id.Hex()},
+		{FileId: primitive.NewObjectID().Hex()},
+	}
+	purged := make([]string, 0)
+	suite.deadLetterQueue.
+		On("Purge", mock.Anything).
+		Return(func(fn func(*reporterpb.DeadLetterMessage) bool) int {
+			for _, msg := range messages {
+				if fn(msg) {
+					purged = append(purged, msg.FileId)
+				}
+			}
+
+			return len(purged)
+		}, nil)
+
+	filePath := getReportTempFilePath(rendering.Id.Hex(), rendering.FileType)
+	assert.NoError(suite.T(), ioutil.WriteFile(filePath, []byte("report"), 0644))
+
+	res := &reporterpb.EraseMerchantFilesResponse{}
+	req := &reporterpb.EraseMerchantFilesRequest{MerchantId: complianceMerchantId, Reason: "gdpr", UserId: "fffffffffffffffffffffff1"}
+	assert.NoError(suite.T(), suite.app.EraseMerchantFiles(context.TODO(), req, res))
+	assert.Equal(suite.T(), pkg.ResponseStatusOk, res.Status)
+	assert.Equal(suite.T(), pkg.ErasureStatusPartial, res.Item.Status)
+	assert.Equal(suite.T(), int32(2), res.Item.Erased)
+	assert.Equal(suite.T(), int32(1), res.Item.Retained)
+	assert.Equal(suite.T(), int32(1), res.Item.Failed)
+	assert.Equal(suite.T(), int32(3), res.Item.DeadLetterPurged)
+	assert.Len(suite.T(), res.Item.Items, 4)
+	assert.Equal(suite.T(), []string{completed.Id.Hex(), erased.Id.Hex(), failed.Id.Hex()}, purged)
+
+	assert.Equal(suite.T(), pkg.ErasureResultErased, res.Item.Items[0].Result)
+	assert.Equal(suite.T(), getErasureKeyHash("report1.pdf"), res.Item.Items[0].KeyHash)
+	assert.NotContains(suite.T(), res.Item.Items[0].KeyHash, "report1")
+	assert.Equal(suite.T(), pkg.ErasureResultRetained, res.Item.Items[1].Result)
+	assert.Equal(suite.T(), pkg.ErasureResultErased, res.Item.Items[2].Result)
+	assert.Equal(suite.T(), getErasureKeyHash("report4.pdf"), res.Item.Items[2].KeyHash)
+	assert.Equal(suite.T(), pkg.ErasureResultFailed, res.Item.Items[3].Result)
+	assert.Equal(suite.T(), "error", res.Item.Items[3].Error)
+
+	_, err := os.Stat(filePath)
+	assert.True(suite.T(), os.IsNotExist(err))
+
+	suite.reportFileRepository.AssertCalled(suite.T(), "Erase", mock.Anything, completed.Id.Hex())
+	suite.reportFileRepository.AssertCalled(suite.T(), "Erase", mock.Anything, rendering.Id.Hex())
+	suite.reportFileRepository.AssertNotCalled(suite.T(), "Erase", mock.Anything, held.Id.Hex())
+	suite.reportFileRepository.AssertNotCalled(suite.T(), "Erase", mock.Anything, failed.Id.Hex())
+	suite.reportFileRepository.AssertNumberOfCalls(suite.T(), "Cancel", 1)
+	suite.storage.AssertCalled(suite.T(), "Delete", mock.Anything, "report4.pdf")
+	suite.erasureReceiptRepository.AssertNumberOfCalls(suite.T(), "Insert", 1)
+}
+
+func (suite *ComplianceTestSuite) TestCompliance_EraseMerchantFiles_MerchantHold() {
+	holds := []*proto.LegalHold{{MerchantId: complianceMerchantId}}
+	jobs := []*proto.ReportFileJob{suite.getJob(pkg.ReportFileStatusCompleted, "report.pdf")}
+	suite.legalHoldRepository.On("FindActive", mock.Anything, complianceMerchantId).Return(holds, nil)
+	suite.reportFileRepository.On("Find", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(jobs, nil)
+	suite.erasureReceiptRepository.On("Insert", mock.Anything, mock.Anything).Return(nil)
+
+	res := &reporterpb.EraseMerchantFilesResponse{}
+	req := &reporterpb.EraseMerchantFilesRequest{MerchantId: complianceMerchantId, Reason: "gdpr"}
+	assert.NoError(suite.T(), suite.app.EraseMerchantFiles(context.TODO(), req, res))
+	assert.Equal(suite.T(), pkg.ResponseStatusOk, res.Status)
+	assert.Equal(suite.T(), pkg.ErasureStatusCompleted, res.Item.Status)
+	assert.Equal(suite.T(), int32(1), res.Item.Retained)
+	suite.storage.AssertNotCalled(suite.T(), "Delete", mock.Anything, mock.Anything)
+	suite.reportFileRepository.AssertNotCalled(suite.T(), "Erase", mock.Anything, mock.Anything)
+	suite.deadLetterQueue.AssertNotCalled(suite.T(), "Purge", mock.Anything)
+}
+
+func (suite *ComplianceTestSuite) TestCompliance_EraseMerchantFiles_DeadLetterPurgeError() {
+	jobs := []*proto.ReportFileJob{suite.getJob(pkg.ReportFileStatusErased, "")}
+	suite.legalHoldRepository.On("FindActive", mock.Anything, complianceMerchantId).Return(nil, nil)
+	suite.reportFileRepository.On("Find", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(jobs, nil)
+	suite.deadLetterQueue.On("Purge", mock.Anything).Return(0, errs.New("error"))
+	suite.erasureReceiptRepository.On("Insert", mock.Anything, mock.Anything).Return(nil)
+
+	res := &reporterpb.EraseMerchantFilesResponse{}
+	req := &reporterpb.EraseMerchantFilesRequest{MerchantId: complianceMerchantId, Reason: "gdpr"}
+	assert.NoError(suite.T(), suite.app.EraseMerchantFiles(context.TODO(), req, res))
+	assert.Equal(suite.T(), pkg.ResponseStatusOk, res.Status)
+	assert.Equal(suite.T(), pkg.ErasureStatusPartial, res.Item.Status)
+	assert.Empty(suite.T(), res.Item.Items)
+}
+
+func (suite *ComplianceTestSuite) TestCompliance_EraseMerchantFiles_Error() {
+	res := &reporterpb.EraseMerchantFilesResponse{}
+	req := &reporterpb.EraseMerchantFilesRequest{MerchantId: complianceMerchantId}
+	assert.NoError(suite.T(), suite.app.EraseMerchantFiles(context.TODO(), req, res))
+	assert.Equal(suite.T(), pkg.ResponseStatusBadData, res.Status)
+	assert.Equal(suite.T(), errors.ErrorErasureReasonEmpty, res.Message)
+
+	suite.legalHoldRepository.On("FindActive", mock.Anything, complianceMerchantId).Return(nil, nil)
+	suite.reportFileRepository.On("Find", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	suite.erasureReceiptRepository.On("Insert", mock.Anything, mock.Anything).Return(errs.New("error"))
+
+	res = &reporterpb.EraseMerchantFilesResponse{}
+	req.Reason = "gdpr"
+	assert.NoError(suite.T(), suite.app.EraseMerchantFiles(context.TODO(), req, res))
+	assert.Equal(suite.T(), pkg.ResponseStatusSystemError, res.Status)
+	assert.Equal(suite.T(), errors.ErrorMerchantErasureFailed, res.Message)
+}
+
+func (suite *ComplianceTestSuite) TestCompliance_runComplianceCommand_Ok() {
+	suite.legalHoldRepository.On("Insert", mock.Anything, mock.Anything).Return(nil)
+
+	out := &bytes.Buffer{}
+	args := []string{complianceCommandHold, "-merchant_id", complianceMerchantId, "-reason", "litigation"}
+	assert.NoError(suite.T(), suite.app.runComplianceCommand(args, out))
+	assert.Contains(suite.T(), out.String(), `"reason":"litigation"`)
+}
+
+func (suite *ComplianceTestSuite) TestCompliance_runComplianceCommand_Error() {
+	err := suite.app.runComplianceCommand([]string{complianceCommandRelease, "-hold_id", "id"}, &bytes.Buffer{})
+	assert.EqualError(suite.T(), err, errors.ErrorLegalHoldNotFound.Code+": "+errors.ErrorLegalHoldNotFound.Message)
+
+	err = suite.app.runComplianceCommand([]string{"unknown"}, &bytes.Buffer{})
+	assert.Equal(suite.T(), errComplianceUnknownCommand, err)
+
+	err = suite.app.runComplianceCommand(nil, &bytes.Buffer{})
+	assert.Equal(suite.T(), errComplianceUnknownCommand, err)
+}
+
+func (suite *ComplianceTestSuite) getJob(status, fileName string) *proto.ReportFileJob {
+	return &proto.ReportFileJob{
+		Id:         primitive.NewObjectID(),
+		MerchantId: complianceMerchantId,
+		ReportType: reporterpb.ReportTypeVat,
+		FileType:   reporterpb.OutputExtensionPdf,
+		Status:     status,
+		FileName:   fileName,
+		Size:       100,
+	}
+}
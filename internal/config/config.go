@@ -1,20 +1,54 @@
 package config
 
 import (
+	"encoding/json"
 	"github.com/kelseyhightower/envconfig"
 )
 
-// AWS defines the parameters for connecting to the NATS streaming server.
+// AWS defines the buckets of the report files and the credentials to access them. Credentials and regions are
+// required by the S3 storage drivers only.
 type S3Config struct {
-	AccessKeyId string `envconfig:"AWS_ACCESS_KEY_ID" required:"true"`
-	SecretKey   string `envconfig:"AWS_SECRET_ACCESS_KEY" required:"true"`
-	Region      string `envconfig:"AWS_REGION" required:"true"`
+	AccessKeyId string `envconfig:"AWS_ACCESS_KEY_ID" default:""`
+	SecretKey   string `envconfig:"AWS_SECRET_ACCESS_KEY" default:""`
+	Region      string `envconfig:"AWS_REGION" default:""`
 	BucketName  string `envconfig:"AWS_BUCKET" required:"true"`
 
-	AwsAccessKeyIdAgreement     string `envconfig:"AWS_ACCESS_KEY_ID_AGREEMENT" required:"true"`
-	AwsSecretAccessKeyAgreement string `envconfig:"AWS_SECRET_ACCESS_KEY_AGREEMENT" required:"true"`
+	AwsAccessKeyIdAgreement     string `envconfig:"AWS_ACCESS_KEY_ID_AGREEMENT" default:""`
+	AwsSecretAccessKeyAgreement string `envconfig:"AWS_SECRET_ACCESS_KEY_AGREEMENT" default:""`
 	AwsRegionAgreement          string `envconfig:"AWS_REGION_AGREEMENT" default:"eu-west-1"`
 	AwsBucketAgreement          string `envconfig:"AWS_BUCKET_AGREEMENT" required:"true"`
+
+	DownloadUrlTtl int64 `envconfig:"AWS_DOWNLOAD_URL_TTL" default:"3600"`
+}
+
+// StorageConfig defines the storage driver of the report files: "s3" for AWS S3, "s3_compatible" for the S3
+// compatible storage at the endpoint, like MinIO, and "local" for the directory in the local filesystem. Local
+// download URLs are built from the base URL serving the directory, without it the file URLs are returned.
+// Routes are set as a JSON list of the storage routes of the report types.
+type StorageConfig struct {
+	Driver     string        `envconfig:"STORAGE_DRIVER" default:"s3"`
+	Endpoint   string        `envconfig:"STORAGE_ENDPOINT" default:""`
+	DisableSsl bool          `envconfig:"STORAGE_DISABLE_SSL" default:"false"`
+	LocalDir   string        `envconfig:"STORAGE_LOCAL_DIR" default:"./storage"`
+	LocalUrl   string        `envconfig:"STORAGE_LOCAL_URL" default:""`
+	Routes     StorageRoutes `envconfig:"STORAGE_ROUTES" default:"[{\"report_type\":\"agreement\",\"target\":\"agreements\",\"expiry\":\"none\"}]"`
+}
+
+// StorageRoute defines the storage target of the report type ("reports" or "agreements"), the template
+// of the file key prefix, the expiry policy ("retention" or "none") and the ACL of the uploaded files.
+// Report types without the route are stored in the reports target until the retention time expires.
+type StorageRoute struct {
+	ReportType string `json:"report_type"`
+	Target     string `json:"target"`
+	KeyPrefix  string `json:"key_prefix"`
+	Expiry     string `json:"expiry"`
+	Acl        string `json:"acl"`
+}
+
+type StorageRoutes []*StorageRoute
+
+func (r *StorageRoutes) Decode(value string) error {
+	return json.Unmarshal([]byte(value), r)
 }
 
 // Centrifugo defines the parameters for connecting to the Centrifugo server.
@@ -37,12 +71,134 @@ type DocumentGeneratorConfig struct {
 	TransactionsTemplate        string `envconfig:"DOCGEN_TRANSACTIONS_TEMPLATE" required:"true"`
 	PayoutTemplate              string `envconfig:"DOCGEN_PAYOUT_TEMPLATE" required:"true"`
 	AgreementTemplate           string `envconfig:"DOCGEN_AGREEMENT_TEMPLATE" required:"true"`
+
+	RetryMaxCount    int32   `envconfig:"DOCGEN_RETRY_MAX_COUNT" default:"2"`
+	RetryBaseDelay   int64   `envconfig:"DOCGEN_RETRY_BASE_DELAY" default:"500"`
+	RetryMaxDelay    int64   `envconfig:"DOCGEN_RETRY_MAX_DELAY" default:"5000"`
+	RetryJitter      float64 `envconfig:"DOCGEN_RETRY_JITTER" default:"0.2"`
+	BreakerThreshold int     `envconfig:"DOCGEN_BREAKER_THRESHOLD" default:"5"`
+	BreakerCooldown  int64   `envconfig:"DOCGEN_BREAKER_COOLDOWN" default:"30000"`
+}
+
+// RendererConfig defines the routing of the report files to the renderers. Routes are set as a list of
+// "<report type>.<file type>:<renderer>" pairs, where either type may be replaced by the "*" wildcard. Files
+// without route are rendered by the default renderer, "*.csv:native,*.xlsx:native" moves CSV and XLSX files
+// to the native renderers.
+type RendererConfig struct {
+	Default       string            `envconfig:"RENDERER_DEFAULT" default:"jsreport"`
+	Routes        map[string]string `envconfig:"RENDERER_ROUTES" default:""`
+	HtmlPdfApiUrl string            `envconfig:"RENDERER_HTML_PDF_API_URL" default:""`
+}
+
+// CsvConfig defines the parameters of the native CSV renderer.
+type CsvConfig struct {
+	Delimiter string `envconfig:"CSV_DELIMITER" default:","`
+	Bom       bool   `envconfig:"CSV_BOM" default:"false"`
+}
+
+// RetryConfig defines the delayed retry policies for the report generation and post processing stages.
+// Delays are set in milliseconds, the jitter is a fraction of the delay in range [0, 1].
+type RetryConfig struct {
+	GenerateMaxCount  int32   `envconfig:"RETRY_GENERATE_MAX_COUNT" default:"10"`
+	GenerateBaseDelay int64   `envconfig:"RETRY_GENERATE_BASE_DELAY" default:"5000"`
+	GenerateMaxDelay  int64   `envconfig:"RETRY_GENERATE_MAX_DELAY" default:"600000"`
+	GenerateJitter    float64 `envconfig:"RETRY_GENERATE_JITTER" default:"0.2"`
+
+	PostProcessMaxCount  int32   `envconfig:"RETRY_POST_PROCESS_MAX_COUNT" default:"10"`
+	PostProcessBaseDelay int64   `envconfig:"RETRY_POST_PROCESS_BASE_DELAY" default:"2000"`
+	PostProcessMaxDelay  int64   `envconfig:"RETRY_POST_PROCESS_MAX_DELAY" default:"300000"`
+	PostProcessJitter    float64 `envconfig:"RETRY_POST_PROCESS_JITTER" default:"0.2"`
+}
+
+// JobConfig defines the deadlines of the report file job and its stages in milliseconds. The stage deadlines
+// are limited by the deadline of the whole job, zero value disables the deadline. On shutdown the jobs in progress
+// are waited for the drain timeout, then aborted and requeued in the abort timeout.
+type JobConfig struct {
+	Timeout            int64 `envconfig:"JOB_TIMEOUT" default:"900000"`
+	BuildTimeout       int64 `envconfig:"JOB_BUILD_TIMEOUT" default:"300000"`
+	RenderTimeout      int64 `envconfig:"JOB_RENDER_TIMEOUT" default:"300000"`
+	UploadTimeout      int64 `envconfig:"JOB_UPLOAD_TIMEOUT" default:"60000"`
+	PostProcessTimeout int64 `envconfig:"JOB_POST_PROCESS_TIMEOUT" default:"120000"`
+	DrainTimeout       int64 `envconfig:"JOB_DRAIN_TIMEOUT" default:"30000"`
+	AbortTimeout       int64 `envconfig:"JOB_ABORT_TIMEOUT" default:"5000"`
+}
+
+// WorkerConfig defines the count of workers and the prefetch of the not acknowledged messages for the generate,
+// interactive generate and post process queues. The prefetch less than the count of workers is increased to it.
+// Count of the rendered files held in memory is limited separately for the bulk and the interactive lanes, so
+// the interactive jobs never wait for the bulk exports. When the memory limit in bytes is set, the new files are
+// not rendered until the heap in use falls below it.
+type WorkerConfig struct {
+	GenerateCount            int    `envconfig:"WORKER_GENERATE_COUNT" default:"4"`
+	GeneratePrefetch         int    `envconfig:"WORKER_GENERATE_PREFETCH" default:"4"`
+	InteractiveCount         int    `envconfig:"WORKER_INTERACTIVE_COUNT" default:"2"`
+	InteractivePrefetch      int    `envconfig:"WORKER_INTERACTIVE_PREFETCH" default:"2"`
+	PostProcessCount         int    `envconfig:"WORKER_POST_PROCESS_COUNT" default:"4"`
+	PostProcessPrefetch      int    `envconfig:"WORKER_POST_PROCESS_PREFETCH" default:"8"`
+	MaxFilesInMemory         int    `envconfig:"WORKER_MAX_FILES_IN_MEMORY" default:"4"`
+	InteractiveFilesInMemory int    `envconfig:"WORKER_INTERACTIVE_FILES_IN_MEMORY" default:"2"`
+	MemoryLimit              uint64 `envconfig:"WORKER_MEMORY_LIMIT" default:"0"`
+}
+
+// LimitConfig defines the limits of the report file requests per merchant and per user. Requests are counted
+// in the window set in seconds, the in-flight jobs are the jobs not completed, failed or cancelled yet. Daily quotas
+// are set per report type as a list of "<report type>:<count>" pairs and counted per merchant since the start
+// of the UTC day. Zero value disables the limit.
+type LimitConfig struct {
+	RequestWindow    int64            `envconfig:"LIMIT_REQUEST_WINDOW" default:"60"`
+	MerchantRequests int64            `envconfig:"LIMIT_MERCHANT_REQUESTS" default:"60"`
+	UserRequests     int64            `envconfig:"LIMIT_USER_REQUESTS" default:"20"`
+	MerchantInFlight int64            `envconfig:"LIMIT_MERCHANT_IN_FLIGHT" default:"10"`
+	UserInFlight     int64            `envconfig:"LIMIT_USER_IN_FLIGHT" default:"5"`
+	DailyQuotas      map[string]int64 `envconfig:"LIMIT_DAILY_QUOTAS" default:"transactions:100,vat_transactions:50,royalty_transactions:50"`
+}
+
+// IdempotencyConfig defines the time in seconds the report file with the same idempotency key or, without a key,
+// with the same merchant, report type, file type, template and params is deduplicated to the existing one.
+// Zero value disables the deduplication.
+type IdempotencyConfig struct {
+	Window int64 `envconfig:"IDEMPOTENCY_WINDOW" default:"600"`
+	KeyTtl int64 `envconfig:"IDEMPOTENCY_KEY_TTL" default:"86400"`
+}
+
+// RetentionConfig defines the sweeper deleting the report files whose retention time has expired. The sweeper runs
+// every interval set in seconds and purges the expired files in batches, zero interval disables it. In the dry run
+// mode the expired files are only logged and counted in the metrics.
+type RetentionConfig struct {
+	SweepInterval int64 `envconfig:"RETENTION_SWEEP_INTERVAL" default:"3600"`
+	BatchSize     int64 `envconfig:"RETENTION_BATCH_SIZE" default:"100"`
+	DryRun        bool  `envconfig:"RETENTION_DRY_RUN" default:"false"`
+}
+
+// EncryptionConfig defines the envelope encryption of the stored report files. Every file is encrypted by its own
+// data key, which is encrypted by the active key of the key provider, "local" reads the keys from the JSON keyfile
+// of the key identifiers and the base64 encoded 256-bit keys. Retired keys are kept in the keyfile to decrypt the
+// files uploaded before the rotation. Encrypted files are downloaded through the download server at the address,
+// the download URLs are built from the public URL of the server and signed by the download secret.
+type EncryptionConfig struct {
+	Enabled        bool   `envconfig:"ENCRYPTION_ENABLED" default:"false"`
+	KeyProvider    string `envconfig:"ENCRYPTION_KEY_PROVIDER" default:"local"`
+	KeyFile        string `envconfig:"ENCRYPTION_KEY_FILE" default:""`
+	ActiveKeyId    string `envconfig:"ENCRYPTION_ACTIVE_KEY_ID" default:""`
+	DownloadAddr   string `envconfig:"ENCRYPTION_DOWNLOAD_ADDR" default:":8087"`
+	DownloadUrl    string `envconfig:"ENCRYPTION_DOWNLOAD_URL" default:"http://127.0.0.1:8087"`
+	DownloadSecret string `envconfig:"ENCRYPTION_DOWNLOAD_SECRET" default:""`
 }
 
 type Config struct {
 	S3               S3Config
+	Storage          StorageConfig
 	DG               DocumentGeneratorConfig
 	CentrifugoConfig CentrifugoConfig
+	Retry            RetryConfig
+	Csv              CsvConfig
+	Renderer         RendererConfig
+	Job              JobConfig
+	Worker           WorkerConfig
+	Limit            LimitConfig
+	Idempotency      IdempotencyConfig
+	Retention        RetentionConfig
+	Encryption       EncryptionConfig
 
 	MetricsPort           string `envconfig:"METRICS_PORT" required:"false" default:"8086"`
 	MicroSelector         string `envconfig:"MICRO_SELECTOR" required:"false" default:""`
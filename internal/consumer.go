@@ -0,0 +1,231 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"github.com/paysuper/paysuper-reporter/pkg"
+	"github.com/streadway/amqp"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+var (
+	errConsumerStopped = errors.New("broker consumer is stopped")
+	// errMessageRedelivery is returned by the handler when the message can be processed neither now nor later
+	// by itself, the message is returned to the queue and redelivered by the broker.
+	errMessageRedelivery = errors.New("message must be redelivered by the broker")
+)
+
+const (
+	consumerMinReconnectDelay = 100 * time.Millisecond
+	consumerMaxReconnectDelay = 30 * time.Second
+)
+
+type ConsumerInterface interface {
+	Start()
+	Cancel()
+	Close() error
+}
+
+// Consumer processes the messages of the broker topic by the fixed count of workers. The prefetch limits the count
+// of the messages delivered to the consumer and not acknowledged yet. The exchange and the queue are declared
+// the same way as the rabbitmq broker, so the messages published by the broker are consumed as before.
+type Consumer struct {
+	address  string
+	topic    string
+	workers  int
+	prefetch int
+	handler  func(d amqp.Delivery) error
+
+	conn    *amqp.Connection
+	ch      *amqp.Channel
+	tag     string
+	stopped bool
+	stop    chan struct{}
+	mx      sync.Mutex
+}
+
+func newConsumer(address, topic string, workers, prefetch int, handler func(d amqp.Delivery) error) ConsumerInterface {
+	if workers < 1 {
+		workers = 1
+	}
+
+	if prefetch < workers {
+		prefetch = workers
+	}
+
+	return &Consumer{
+		address:  address,
+		topic:    topic,
+		workers:  workers,
+		prefetch: prefetch,
+		handler:  handler,
+		stop:     make(chan struct{}),
+	}
+}
+
+// Start consumes the messages in background and reconnects to the broker when the connection is lost.
+func (c *Consumer) Start() {
+	go c.run()
+}
+
+// Cancel stops the delivery of the new messages, the messages in progress are processed as usual.
+func (c *Consumer) Cancel() {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if c.stopped {
+		return
+	}
+
+	c.stopped = true
+	close(c.stop)
+
+	if c.ch == nil {
+		return
+	}
+
+	if err := c.ch.Cancel(c.tag, false); err != nil {
+		zap.L().Error("Cancel of the broker consumer failed", zap.Error(err), zap.String("topic", c.topic))
+	}
+}
+
+// Close cancels the consumer and closes the broker connection, the not acknowledged messages are returned to the queue.
+func (c *Consumer) Close() error {
+	c.Cancel()
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if c.conn == nil {
+		return nil
+	}
+
+	return c.conn.Close()
+}
+
+func (c *Consumer) run() {
+	delay := consumerMinReconnectDelay
+
+	for {
+		deliveries, err := c.consume()
+
+		if err == nil {
+			delay = consumerMinReconnectDelay
+			c.dispatch(deliveries)
+		} else {
+			zap.L().Error("Consume of the broker topic failed", zap.Error(err), zap.String("topic", c.topic))
+		}
+
+		select {
+		case <-c.stop:
+			return
+		case <-time.After(delay):
+		}
+
+		if delay *= 2; delay > consumerMaxReconnectDelay {
+			delay = consumerMaxReconnectDelay
+		}
+
+		zap.L().Warn("Reconnecting the broker consumer", zap.String("topic", c.topic))
+	}
+}
+
+func (c *Consumer) consume() (<-chan amqp.Delivery, error) {
+	conn, err := amqp.Dial(c.address)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ch, err := conn.Channel()
+
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	queue := fmt.Sprintf(pkg.BrokerQueueNameMask, c.topic)
+	tag := fmt.Sprintf(pkg.BrokerConsumerTagMask, queue, time.Now().UnixNano())
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if c.stopped {
+		_ = conn.Close()
+		return nil, errConsumerStopped
+	}
+
+	err = ch.Qos(c.prefetch, 0, false)
+
+	if err == nil {
+		err = ch.ExchangeDeclare(c.topic, pkg.BrokerExchangeKind, true, false, false, false, nil)
+	}
+
+	if err == nil {
+		_, err = ch.QueueDeclare(queue, true, false, false, false, nil)
+	}
+
+	if err == nil {
+		err = ch.QueueBind(queue, pkg.BrokerQueueBindKey, c.topic, false, nil)
+	}
+
+	var deliveries <-chan amqp.Delivery
+
+	if err == nil {
+		deliveries, err = ch.Consume(queue, tag, false, false, false, false, nil)
+	}
+
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	c.conn = conn
+	c.ch = ch
+	c.tag = tag
+
+	return deliveries, nil
+}
+
+// dispatch processes the deliveries by the workers until the deliveries channel is closed.
+func (c *Consumer) dispatch(deliveries <-chan amqp.Delivery) {
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < c.workers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for d := range deliveries {
+				c.process(d)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func (c *Consumer) process(d amqp.Delivery) {
+	if d.ContentType != pkg.BrokerContentType {
+		zap.L().Error("Unknown content type of the message, message skipped", zap.String("topic", c.topic))
+		_ = d.Nack(false, false)
+		return
+	}
+
+	if err := c.handler(d); err != nil {
+		if err == errMessageRedelivery {
+			zap.L().Warn("Message returned to the broker for redelivery", zap.String("topic", c.topic))
+			_ = d.Nack(false, true)
+			return
+		}
+
+		zap.L().Error("Message processing failed, message skipped", zap.Error(err), zap.String("topic", c.topic))
+		_ = d.Nack(false, false)
+		return
+	}
+
+	_ = d.Ack(false)
+}
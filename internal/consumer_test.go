@@ -0,0 +1,140 @@
+package internal
+
+import (
+	"errors"
+	"github.com/paysuper/paysuper-reporter/pkg"
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type ConsumerTestSuite struct {
+	suite.Suite
+}
+
+type consumerTestAcknowledger struct {
+	acks    int32
+	nacks   int32
+	requeue bool
+}
+
+func (a *consumerTestAcknowledger) Ack(_ uint64, _ bool) error {
+	atomic.AddInt32(&a.acks, 1)
+	return nil
+}
+
+func (a *consumerTestAcknowledger) Nack(_ uint64, _ bool, requeue bool) error {
+	atomic.AddInt32(&a.nacks, 1)
+	a.requeue = requeue
+	return nil
+}
+
+func (a *consumerTestAcknowledger) Reject(_ uint64, requeue bool) error {
+	return a.Nack(0, false, requeue)
+}
+
+func Test_Consumer(t *testing.T) {
+	suite.Run(t, new(ConsumerTestSuite))
+}
+
+func (suite *ConsumerTestSuite) TestConsumer_NewConsumer_Limits() {
+	c := newConsumer("", pkg.BrokerGenerateReportTopicName, 0, 0, nil).(*Consumer)
+	assert.Equal(suite.T(), 1, c.workers)
+	assert.Equal(suite.T(), 1, c.prefetch)
+
+	c = newConsumer("", pkg.BrokerGenerateReportTopicName, 4, 2, nil).(*Consumer)
+	assert.Equal(suite.T(), 4, c.workers)
+	assert.Equal(suite.T(), 4, c.prefetch)
+}
+
+func (suite *ConsumerTestSuite) TestConsumer_Process_Ack() {
+	ack := &consumerTestAcknowledger{}
+	c := newConsumer("", pkg.BrokerGenerateReportTopicName, 1, 1, func(d amqp.Delivery) error {
+		return nil
+	}).(*Consumer)
+
+	c.process(amqp.Delivery{Acknowledger: ack, ContentType: pkg.BrokerContentType})
+	assert.EqualValues(suite.T(), 1, ack.acks)
+	assert.EqualValues(suite.T(), 0, ack.nacks)
+}
+
+func (suite *ConsumerTestSuite) TestConsumer_Process_Nack_HandlerError() {
+	ack := &consumerTestAcknowledger{}
+	c := newConsumer("", pkg.BrokerGenerateReportTopicName, 1, 1, func(d amqp.Delivery) error {
+		return errors.New("error")
+	}).(*Consumer)
+
+	c.process(amqp.Delivery{Acknowledger: ack, ContentType: pkg.BrokerContentType})
+	assert.EqualValues(suite.T(), 0, ack.acks)
+	assert.EqualValues(suite.T(), 1, ack.nacks)
+	assert.False(suite.T(), ack.requeue)
+}
+
+func (suite *ConsumerTestSuite) TestConsumer_Process_Nack_Redelivery() {
+	ack := &consumerTestAcknowledger{}
+	c := newConsumer("", pkg.BrokerGenerateReportTopicName, 1, 1, func(d amqp.Delivery) error {
+		return errMessageRedelivery
+	}).(*Consumer)
+
+	c.process(amqp.Delivery{Acknowledger: ack, ContentType: pkg.BrokerContentType})
+	assert.EqualValues(suite.T(), 0, ack.acks)
+	assert.EqualValues(suite.T(), 1, ack.nacks)
+	assert.True(suite.T(), ack.requeue)
+}
+
+func (suite *ConsumerTestSuite) TestConsumer_Process_Nack_ContentType() {
+	ack := &consumerTestAcknowledger{}
+	called := false
+	c := newConsumer("", pkg.BrokerGenerateReportTopicName, 1, 1, func(d amqp.Delivery) error {
+		called = true
+		return nil
+	}).(*Consumer)
+
+	c.process(amqp.Delivery{Acknowledger: ack, ContentType: "application/json"})
+	assert.False(suite.T(), called)
+	assert.EqualValues(suite.T(), 1, ack.nacks)
+}
+
+func (suite *ConsumerTestSuite) TestConsumer_Dispatch_Workers() {
+	var running, maxRunning int32
+	mx := sync.Mutex{}
+
+	c := newConsumer("", pkg.BrokerGenerateReportTopicName, 2, 2, func(d amqp.Delivery) error {
+		n := atomic.AddInt32(&running, 1)
+
+		mx.Lock()
+		if n > maxRunning {
+			maxRunning = n
+		}
+		mx.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+
+		return nil
+	}).(*Consumer)
+
+	ack := &consumerTestAcknowledger{}
+	deliveries := make(chan amqp.Delivery, 6)
+
+	for i := 0; i < 6; i++ {
+		deliveries <- amqp.Delivery{Acknowledger: ack, ContentType: pkg.BrokerContentType}
+	}
+
+	close(deliveries)
+	c.dispatch(deliveries)
+
+	assert.EqualValues(suite.T(), 6, ack.acks)
+	assert.EqualValues(suite.T(), 2, maxRunning)
+}
+
+func (suite *ConsumerTestSuite) TestConsumer_Cancel_NotStarted() {
+	c := newConsumer("", pkg.BrokerGenerateReportTopicName, 1, 1, nil)
+	c.Cancel()
+	c.Cancel()
+	assert.NoError(suite.T(), c.Close())
+}
@@ -0,0 +1,119 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/paysuper/paysuper-reporter/internal/config"
+	"github.com/paysuper/paysuper-reporter/pkg/proto"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const csvBom = "\xEF\xBB\xBF"
+
+var (
+	errCsvDelimiterInvalid  = errors.New("csv delimiter must be a single character")
+	errCsvReportUnsupported = errors.New("report type has no csv columns definition")
+)
+
+// CsvRenderer renders the report files in the CSV format without the document generator service.
+type CsvRenderer struct {
+	delimiter rune
+	bom       bool
+}
+
+func newCsvRenderer(cfg *config.CsvConfig) (DocumentGeneratorInterface, error) {
+	delimiter, size := utf8.DecodeRuneInString(cfg.Delimiter)
+
+	if size == 0 || size != len(cfg.Delimiter) || delimiter == utf8.RuneError {
+		return nil, errCsvDelimiterInvalid
+	}
+
+	// Check the delimiter once on start instead of failing on every render
+	w := csv.NewWriter(&bytes.Buffer{})
+	w.Comma = delimiter
+
+	if err := w.Write(nil); err != nil {
+		return nil, errCsvDelimiterInvalid
+	}
+
+	return &CsvRenderer{delimiter: delimiter, bom: cfg.Bom}, nil
+}
+
+func (r *CsvRenderer) Render(_ context.Context, payload *proto.GeneratorPayload) ([]byte, error) {
+	sections, ok := reportFileSections[payload.ReportType]
+
+	if !ok || len(sections) < 1 {
+		return nil, errCsvReportUnsupported
+	}
+
+	section := sections[0]
+	rows, err := getSectionRows(payload.Data, section)
+
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+
+	if r.bom {
+		buf.WriteString(csvBom)
+	}
+
+	w := csv.NewWriter(buf)
+	w.Comma = r.delimiter
+
+	record := make([]string, len(section.Columns))
+
+	for i, column := range section.Columns {
+		record[i] = column.Title
+	}
+
+	if err = w.Write(record); err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		for i, column := range section.Columns {
+			record[i] = formatCsvValue(row[column.Field])
+		}
+
+		if err = w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+
+	if err = w.Error(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func formatCsvValue(val interface{}) string {
+	switch v := val.(type) {
+	case nil:
+		return ""
+	case string:
+		// Text that starts with a formula sign is executed by the spreadsheet applications
+		if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+			return "'" + v
+		}
+
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+
+	return fmt.Sprintf("%v", val)
+}
@@ -0,0 +1,119 @@
+package internal
+
+import (
+	"context"
+	"encoding/csv"
+	"github.com/paysuper/paysuper-reporter/internal/config"
+	"github.com/paysuper/paysuper-reporter/pkg/proto"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"strings"
+	"testing"
+)
+
+type CsvRendererTestSuite struct {
+	suite.Suite
+}
+
+func Test_CsvRenderer(t *testing.T) {
+	suite.Run(t, new(CsvRendererTestSuite))
+}
+
+func (suite *CsvRendererTestSuite) TestCsvRenderer_newCsvRenderer_Ok() {
+	r, err := newCsvRenderer(&config.CsvConfig{Delimiter: ";"})
+	assert.NoError(suite.T(), err)
+	assert.IsType(suite.T(), &CsvRenderer{}, r)
+	assert.Equal(suite.T(), ';', r.(*CsvRenderer).delimiter)
+}
+
+func (suite *CsvRendererTestSuite) TestCsvRenderer_newCsvRenderer_Error_Delimiter() {
+	for _, delimiter := range []string{"", ";;", "\"", "\n"} {
+		_, err := newCsvRenderer(&config.CsvConfig{Delimiter: delimiter})
+		assert.Equal(suite.T(), errCsvDelimiterInvalid, err, delimiter)
+	}
+}
+
+func (suite *CsvRendererTestSuite) TestCsvRenderer_Render_Ok() {
+	r, err := newCsvRenderer(&config.CsvConfig{Delimiter: ","})
+	assert.NoError(suite.T(), err)
+
+	b, err := r.Render(context.Background(), &proto.GeneratorPayload{
+		ReportType: reporterpb.ReportTypeTransactions,
+		Data: map[string]interface{}{
+			"transactions": []map[string]interface{}{
+				{
+					"project_name":   "Project, \"Special\" edition",
+					"product_name":   "=HYPERLINK(\"http://example.com\")",
+					"transaction_id": "1",
+					"net_amount":     12.5,
+					"status":         "processed",
+				},
+				{
+					"project_name": "Multi\nline",
+					"net_amount":   float64(-3),
+				},
+			},
+		},
+	})
+	assert.NoError(suite.T(), err)
+
+	records, err := csv.NewReader(strings.NewReader(string(b))).ReadAll()
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), records, 3)
+	assert.Equal(suite.T(), "Project", records[0][0])
+	assert.Equal(suite.T(), "Project, \"Special\" edition", records[1][0])
+	assert.Equal(suite.T(), "'=HYPERLINK(\"http://example.com\")", records[1][1])
+	assert.Equal(suite.T(), "12.5", records[1][6])
+	assert.Equal(suite.T(), "", records[1][7])
+	assert.Equal(suite.T(), "Multi\nline", records[2][0])
+	assert.Equal(suite.T(), "-3", records[2][6])
+}
+
+func (suite *CsvRendererTestSuite) TestCsvRenderer_Render_Ok_DelimiterAndBom() {
+	r, err := newCsvRenderer(&config.CsvConfig{Delimiter: ";", Bom: true})
+	assert.NoError(suite.T(), err)
+
+	b, err := r.Render(context.Background(), &proto.GeneratorPayload{
+		ReportType: reporterpb.ReportTypeRoyalty,
+		Data: map[string]interface{}{
+			"products": []interface{}{
+				map[string]interface{}{"product": "Game", "region": "EU", "total_end_user_sales": int32(2)},
+			},
+		},
+	})
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), strings.HasPrefix(string(b), csvBom))
+
+	lines := strings.Split(strings.TrimPrefix(string(b), csvBom), "\n")
+	assert.True(suite.T(), strings.HasPrefix(lines[0], "Product;Region;"))
+	assert.True(suite.T(), strings.HasPrefix(lines[1], "Game;EU;2;"))
+}
+
+func (suite *CsvRendererTestSuite) TestCsvRenderer_Render_Ok_Empty() {
+	r, err := newCsvRenderer(&config.CsvConfig{Delimiter: ","})
+	assert.NoError(suite.T(), err)
+
+	b, err := r.Render(context.Background(), &proto.GeneratorPayload{ReportType: reporterpb.ReportTypeVat, Data: []map[string]interface{}(nil)})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, strings.Count(string(b), "\n"))
+}
+
+func (suite *CsvRendererTestSuite) TestCsvRenderer_Render_Error_ReportTypeUnsupported() {
+	r, err := newCsvRenderer(&config.CsvConfig{Delimiter: ","})
+	assert.NoError(suite.T(), err)
+
+	_, err = r.Render(context.Background(), &proto.GeneratorPayload{ReportType: reporterpb.ReportTypeAgreement})
+	assert.Equal(suite.T(), errCsvReportUnsupported, err)
+}
+
+func (suite *CsvRendererTestSuite) TestCsvRenderer_Render_Error_RowsInvalid() {
+	r, err := newCsvRenderer(&config.CsvConfig{Delimiter: ","})
+	assert.NoError(suite.T(), err)
+
+	_, err = r.Render(context.Background(), &proto.GeneratorPayload{
+		ReportType: reporterpb.ReportTypeTransactions,
+		Data:       map[string]interface{}{"transactions": "invalid"},
+	})
+	assert.Equal(suite.T(), errReportSectionRowsInvalid, err)
+}
@@ -0,0 +1,332 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	protobufProto "github.com/golang/protobuf/proto"
+	"github.com/paysuper/paysuper-reporter/pkg"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
+	"github.com/streadway/amqp"
+	"go.uber.org/zap"
+	"io"
+	"os"
+	"sync"
+)
+
+const (
+	CommandDeadLetter = "dlq"
+
+	deadLetterCommandList    = "list"
+	deadLetterCommandRequeue = "requeue"
+	deadLetterDefaultLimit   = 100
+)
+
+var (
+	errDeadLetterUnknownTopic   = errors.New("dead letter message has unknown topic")
+	errDeadLetterUnknownCommand = errors.New("unknown dead letter command, expected one of: list, requeue")
+)
+
+type DeadLetterQueueInterface interface {
+	Publish(msg *reporterpb.DeadLetterMessage) error
+	Inspect(limit int) ([]*reporterpb.DeadLetterMessage, error)
+	Requeue(limit int, fn func(msg *reporterpb.DeadLetterMessage) (bool, error)) (int, error)
+	Purge(fn func(msg *reporterpb.DeadLetterMessage) bool) (int, error)
+	Close() error
+}
+
+type DeadLetterQueue struct {
+	channel *BrokerChannel
+	mx      sync.Mutex
+}
+
+func newDeadLetterQueue(address string) (DeadLetterQueueInterface, error) {
+	q := &DeadLetterQueue{}
+	channel, err := newBrokerChannel(address, q.declare)
+
+	if err != nil {
+		return nil, err
+	}
+
+	q.channel = channel
+
+	return q, nil
+}
+
+func (q *DeadLetterQueue) declare(ch *amqp.Channel) error {
+	err := ch.ExchangeDeclare(pkg.BrokerDeadLetterTopicName, amqp.ExchangeDirect, true, false, false, false, nil)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = ch.QueueDeclare(pkg.BrokerDeadLetterTopicName, true, false, false, false, nil)
+
+	if err != nil {
+		return err
+	}
+
+	return ch.QueueBind(
+		pkg.BrokerDeadLetterTopicName,
+		pkg.BrokerDeadLetterTopicName,
+		pkg.BrokerDeadLetterTopicName,
+		false,
+		nil,
+	)
+}
+
+func (q *DeadLetterQueue) Publish(msg *reporterpb.DeadLetterMessage) error {
+	body, err := protobufProto.Marshal(msg)
+
+	if err != nil {
+		return err
+	}
+
+	q.mx.Lock()
+	defer q.mx.Unlock()
+
+	return q.channel.Publish(func(ch *amqp.Channel, _ bool) error {
+		return ch.Publish(
+			pkg.BrokerDeadLetterTopicName,
+			pkg.BrokerDeadLetterTopicName,
+			false,
+			false,
+			amqp.Publishing{
+				ContentType:  "application/protobuf",
+				DeliveryMode: amqp.Persistent,
+				Body:         body,
+				Headers: amqp.Table{
+					"x-original-topic": msg.Topic,
+					"x-stage":          msg.Stage,
+				},
+			},
+		)
+	})
+}
+
+// Inspect returns up to limit messages from the dead letter queue without removing them.
+func (q *DeadLetterQueue) Inspect(limit int) ([]*reporterpb.DeadLetterMessage, error) {
+	var messages []*reporterpb.DeadLetterMessage
+
+	_, err := q.walk(limit, func(msg *reporterpb.DeadLetterMessage) (bool, error) {
+		messages = append(messages, msg)
+		return false, nil
+	})
+
+	return messages, err
+}
+
+// Requeue passes up to limit messages from the dead letter queue to the handler and removes the ones
+// the handler has accepted. Messages rejected by the handler stay in the queue.
+// It returns the count of removed messages.
+func (q *DeadLetterQueue) Requeue(limit int, fn func(msg *reporterpb.DeadLetterMessage) (bool, error)) (int, error) {
+	return q.walk(limit, fn)
+}
+
+// Purge removes the messages matched by the filter from the whole dead letter queue.
+// It returns the count of removed messages.
+func (q *DeadLetterQueue) Purge(fn func(msg *reporterpb.DeadLetterMessage) bool) (int, error) {
+	return q.walk(0, func(msg *reporterpb.DeadLetterMessage) (bool, error) {
+		return fn(msg), nil
+	})
+}
+
+func (q *DeadLetterQueue) Close() error {
+	q.mx.Lock()
+	defer q.mx.Unlock()
+
+	return q.channel.Close()
+}
+
+// walk reads messages on a dedicated channel. Messages that were not acknowledged are returned to the queue
+// in their original order when the channel is closed.
+func (q *DeadLetterQueue) walk(limit int, fn func(msg *reporterpb.DeadLetterMessage) (bool, error)) (int, error) {
+	ch, err := q.openChannel()
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		if err := ch.Close(); err != nil {
+			zap.L().Error("Unable to close dead letter queue channel", zap.Error(err))
+		}
+	}()
+
+	state, err := ch.QueueInspect(pkg.BrokerDeadLetterTopicName)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if limit <= 0 || limit > state.Messages {
+		limit = state.Messages
+	}
+
+	processed := 0
+
+	for i := 0; i < limit; i++ {
+		d, ok, err := ch.Get(pkg.BrokerDeadLetterTopicName, false)
+
+		if err != nil {
+			return processed, err
+		}
+
+		if !ok {
+			break
+		}
+
+		msg := &reporterpb.DeadLetterMessage{}
+
+		if err = protobufProto.Unmarshal(d.Body, msg); err != nil {
+			zap.L().Error("Unable to unmarshal dead letter message", zap.Error(err), zap.Uint64("tag", d.DeliveryTag))
+			continue
+		}
+
+		handled, err := fn(msg)
+
+		if err != nil {
+			return processed, err
+		}
+
+		if !handled {
+			continue
+		}
+
+		if err = d.Ack(false); err != nil {
+			return processed, err
+		}
+
+		processed++
+	}
+
+	return processed, nil
+}
+
+func (q *DeadLetterQueue) openChannel() (*amqp.Channel, error) {
+	q.mx.Lock()
+	defer q.mx.Unlock()
+
+	conn, err := q.channel.Connection()
+
+	if err != nil {
+		return nil, err
+	}
+
+	ch, err := conn.Channel()
+
+	if isBrokerConnectionLost(err) {
+		q.channel.Reset()
+	}
+
+	return ch, err
+}
+
+// RunDeadLetterCommand executes the operator command to inspect or requeue dead letter messages. The error is
+// returned to the caller, so it can stop the application before the exit.
+func (app *Application) RunDeadLetterCommand(args []string) error {
+	err := app.runDeadLetterCommand(args, os.Stdout)
+
+	if err != nil {
+		zap.L().Error("Dead letter command failed", zap.Error(err))
+	}
+
+	return err
+}
+
+func (app *Application) runDeadLetterCommand(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errDeadLetterUnknownCommand
+	}
+
+	fs := flag.NewFlagSet(CommandDeadLetter+" "+args[0], flag.ContinueOnError)
+	limit := fs.Int("limit", deadLetterDefaultLimit, "max count of messages to process, 0 to process all")
+	fileId := fs.String("file_id", "", "requeue only the messages of the report file")
+
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case deadLetterCommandList:
+		messages, err := app.deadLetterQueue.Inspect(*limit)
+
+		if err != nil {
+			return err
+		}
+
+		for _, msg := range messages {
+			b, err := json.Marshal(msg)
+
+			if err != nil {
+				return err
+			}
+
+			if _, err = fmt.Fprintln(out, string(b)); err != nil {
+				return err
+			}
+		}
+
+		_, err = fmt.Fprintf(out, "%d message(s) in the dead letter queue listed\n", len(messages))
+		return err
+	case deadLetterCommandRequeue:
+		count, err := app.deadLetterQueue.Requeue(*limit, func(msg *reporterpb.DeadLetterMessage) (bool, error) {
+			if *fileId != "" && msg.FileId != *fileId {
+				return false, nil
+			}
+
+			return true, app.requeueDeadLetter(msg)
+		})
+
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintf(out, "%d message(s) requeued\n", count)
+		return err
+	}
+
+	return errDeadLetterUnknownCommand
+}
+
+func (app *Application) requeueDeadLetter(msg *reporterpb.DeadLetterMessage) error {
+	var (
+		broker  = app.generateReportBroker
+		message protobufProto.Message
+	)
+
+	switch msg.Topic {
+	case pkg.BrokerGenerateReportTopicName:
+		message = &reporterpb.ReportFile{}
+	case pkg.BrokerGenerateReportInteractiveTopicName:
+		broker = app.generateInteractiveBroker
+		message = &reporterpb.ReportFile{}
+	case pkg.BrokerPostProcessTopicName:
+		broker = app.postProcessBroker
+		message = &reporterpb.PostProcessRequest{}
+	default:
+		return errDeadLetterUnknownTopic
+	}
+
+	if err := protobufProto.Unmarshal(msg.Payload, message); err != nil {
+		return err
+	}
+
+	// The message of the cancelled or erased report file is removed from the queue without the requeue.
+	if app.isJobCancelled(msg.FileId, msg.Stage) {
+		return nil
+	}
+
+	amqpHeaders := amqp.Table{
+		"x-retry-count": int32(0),
+	}
+
+	if err := broker.Publish(msg.Topic, message, amqpHeaders); err != nil {
+		return err
+	}
+
+	app.setJobStatus(msg.FileId, pkg.ReportFileStatusQueued, nil, nil)
+
+	return nil
+}
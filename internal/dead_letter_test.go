@@ -0,0 +1,294 @@
+package internal
+
+import (
+	"bytes"
+	errs "errors"
+	protobufProto "github.com/golang/protobuf/proto"
+	"github.com/paysuper/paysuper-reporter/internal/config"
+	"github.com/paysuper/paysuper-reporter/internal/mocks"
+	"github.com/paysuper/paysuper-reporter/pkg"
+	"github.com/paysuper/paysuper-reporter/pkg/proto"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	rabbitmqMock "gopkg.in/ProtocolONE/rabbitmq.v1/pkg/mocks"
+	"testing"
+)
+
+type DeadLetterTestSuite struct {
+	suite.Suite
+	app                  *Application
+	deadLetterQueue      *mocks.DeadLetterQueueInterface
+	generateReportBroker *rabbitmqMock.BrokerInterface
+	interactiveBroker    *rabbitmqMock.BrokerInterface
+	postProcessBroker    *rabbitmqMock.BrokerInterface
+	reportFileRepository *mocks.ReportFileRepositoryInterface
+	retryQueue           *mocks.RetryQueueInterface
+}
+
+func Test_DeadLetter(t *testing.T) {
+	suite.Run(t, new(DeadLetterTestSuite))
+}
+
+func (suite *DeadLetterTestSuite) SetupTest() {
+	suite.deadLetterQueue = &mocks.DeadLetterQueueInterface{}
+	suite.generateReportBroker = &rabbitmqMock.BrokerInterface{}
+	suite.interactiveBroker = &rabbitmqMock.BrokerInterface{}
+	suite.postProcessBroker = &rabbitmqMock.BrokerInterface{}
+	suite.reportFileRepository = &mocks.ReportFileRepositoryInterface{}
+	suite.reportFileRepository.On("SetStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	suite.reportFileRepository.
+		On("GetById", mock.Anything, "1").
+		Return(&proto.ReportFileJob{Status: pkg.ReportFileStatusFailed}, nil)
+	suite.reportFileRepository.
+		On("GetById", mock.Anything, "2").
+		Return(&proto.ReportFileJob{Status: pkg.ReportFileStatusErased}, nil)
+
+	suite.retryQueue = &mocks.RetryQueueInterface{}
+
+	suite.app = &Application{
+		retryQueue:                suite.retryQueue,
+		retryPolicies:             newRetryPolicies(&config.RetryConfig{GenerateMaxCount: 3, PostProcessMaxCount: 3}),
+		deadLetterQueue:           suite.deadLetterQueue,
+		generateReportBroker:      suite.generateReportBroker,
+		generateInteractiveBroker: suite.interactiveBroker,
+		postProcessBroker:         suite.postProcessBroker,
+		reportFileRepository:      suite.reportFileRepository,
+	}
+}
+
+func (suite *DeadLetterTestSuite) TestDeadLetter_runDeadLetterCommand_List_Ok() {
+	messages := []*reporterpb.DeadLetterMessage{
+		{Topic: pkg.BrokerGenerateReportTopicName, FileId: "1", Stage: pkg.ReportFileStatusRendering},
+		{Topic: pkg.BrokerPostProcessTopicName, FileId: "2", Stage: pkg.ReportFileStatusPostProcessing},
+	}
+	suite.deadLetterQueue.On("Inspect", 10).Return(messages, nil)
+
+	out := &bytes.Buffer{}
+	err := suite.app.runDeadLetterCommand([]string{deadLetterCommandList, "-limit", "10"}, out)
+
+	assert.NoError(suite.T(), err)
+	assert.Contains(suite.T(), out.String(), "2 message(s)")
+}
+
+func (suite *DeadLetterTestSuite) TestDeadLetter_runDeadLetterCommand_List_Error() {
+	suite.deadLetterQueue.On("Inspect", deadLetterDefaultLimit).Return(nil, errs.New("error"))
+
+	err := suite.app.runDeadLetterCommand([]string{deadLetterCommandList}, &bytes.Buffer{})
+	assert.Error(suite.T(), err)
+}
+
+func (suite *DeadLetterTestSuite) TestDeadLetter_runDeadLetterCommand_Error_UnknownCommand() {
+	err := suite.app.runDeadLetterCommand([]string{"unknown"}, &bytes.Buffer{})
+	assert.Equal(suite.T(), errDeadLetterUnknownCommand, err)
+
+	err = suite.app.runDeadLetterCommand([]string{}, &bytes.Buffer{})
+	assert.Equal(suite.T(), errDeadLetterUnknownCommand, err)
+}
+
+func (suite *DeadLetterTestSuite) TestDeadLetter_runDeadLetterCommand_Requeue_FilterByFileId() {
+	payload, err := protobufProto.Marshal(&reporterpb.ReportFile{Id: "1"})
+	assert.NoError(suite.T(), err)
+
+	messages := []*reporterpb.DeadLetterMessage{
+		{Topic: pkg.BrokerGenerateReportTopicName, FileId: "1", Payload: payload},
+		{Topic: pkg.BrokerGenerateReportTopicName, FileId: "2", Payload: payload},
+	}
+	handled := make([]bool, 0)
+
+	suite.deadLetterQueue.
+		On("Requeue", deadLetterDefaultLimit, mock.Anything).
+		Return(func(limit int, fn func(*reporterpb.DeadLetterMessage) (bool, error)) int {
+			count := 0
+
+			for _, msg := range messages {
+				ok, err := fn(msg)
+				assert.NoError(suite.T(), err)
+				handled = append(handled, ok)
+
+				if ok {
+					count++
+				}
+			}
+
+			return count
+		}, nil)
+	suite.generateReportBroker.On("Publish", pkg.BrokerGenerateReportTopicName, mock.Anything, mock.Anything).Return(nil)
+
+	out := &bytes.Buffer{}
+	err = suite.app.runDeadLetterCommand([]string{deadLetterCommandRequeue, "-file_id", "1"}, out)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []bool{true, false}, handled)
+	assert.Contains(suite.T(), out.String(), "1 message(s) requeued")
+	suite.generateReportBroker.AssertNumberOfCalls(suite.T(), "Publish", 1)
+	suite.reportFileRepository.AssertCalled(suite.T(), "SetStatus", mock.Anything, "1", pkg.ReportFileStatusQueued, mock.Anything)
+}
+
+func (suite *DeadLetterTestSuite) TestDeadLetter_requeueDeadLetter_PostProcess_Ok() {
+	payload, err := protobufProto.Marshal(&reporterpb.PostProcessRequest{FileName: "report.pdf"})
+	assert.NoError(suite.T(), err)
+
+	suite.postProcessBroker.On("Publish", pkg.BrokerPostProcessTopicName, mock.Anything, mock.Anything).Return(nil)
+
+	msg := &reporterpb.DeadLetterMessage{Topic: pkg.BrokerPostProcessTopicName, FileId: "1", Payload: payload}
+	assert.NoError(suite.T(), suite.app.requeueDeadLetter(msg))
+	suite.postProcessBroker.AssertNumberOfCalls(suite.T(), "Publish", 1)
+	suite.generateReportBroker.AssertNotCalled(suite.T(), "Publish", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *DeadLetterTestSuite) TestDeadLetter_requeueDeadLetter_Interactive_Ok() {
+	payload, err := protobufProto.Marshal(&reporterpb.ReportFile{Id: "1", ReportType: reporterpb.ReportTypeAgreement})
+	assert.NoError(suite.T(), err)
+
+	suite.interactiveBroker.On("Publish", pkg.BrokerGenerateReportInteractiveTopicName, mock.Anything, mock.Anything).Return(nil)
+
+	msg := &reporterpb.DeadLetterMessage{Topic: pkg.BrokerGenerateReportInteractiveTopicName, FileId: "1", Payload: payload}
+	assert.NoError(suite.T(), suite.app.requeueDeadLetter(msg))
+	suite.interactiveBroker.AssertNumberOfCalls(suite.T(), "Publish", 1)
+	suite.generateReportBroker.AssertNotCalled(suite.T(), "Publish", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *DeadLetterTestSuite) TestDeadLetter_requeueDeadLetter_Erased() {
+	payload, err := protobufProto.Marshal(&reporterpb.ReportFile{Id: "2"})
+	assert.NoError(suite.T(), err)
+
+	msg := &reporterpb.DeadLetterMessage{Topic: pkg.BrokerGenerateReportTopicName, FileId: "2", Payload: payload}
+	assert.NoError(suite.T(), suite.app.requeueDeadLetter(msg))
+	suite.generateReportBroker.AssertNotCalled(suite.T(), "Publish", mock.Anything, mock.Anything, mock.Anything)
+	suite.reportFileRepository.AssertNotCalled(suite.T(), "SetStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *DeadLetterTestSuite) TestDeadLetter_requeueDeadLetter_Error_UnknownTopic() {
+	msg := &reporterpb.DeadLetterMessage{Topic: "unknown", FileId: "1"}
+	assert.Equal(suite.T(), errDeadLetterUnknownTopic, suite.app.requeueDeadLetter(msg))
+}
+
+func (suite *DeadLetterTestSuite) TestDeadLetter_requeueDeadLetter_Error_Publish() {
+	payload, err := protobufProto.Marshal(&reporterpb.ReportFile{Id: "1"})
+	assert.NoError(suite.T(), err)
+
+	suite.generateReportBroker.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(errs.New("error"))
+
+	msg := &reporterpb.DeadLetterMessage{Topic: pkg.BrokerGenerateReportTopicName, FileId: "1", Payload: payload}
+	assert.Error(suite.T(), suite.app.requeueDeadLetter(msg))
+	suite.reportFileRepository.AssertNotCalled(suite.T(), "SetStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *DeadLetterTestSuite) TestDeadLetter_getProcessResult_RetriesExhausted() {
+	suite.deadLetterQueue.On("Publish", mock.Anything).Return(nil)
+
+	jobErr := &proto.ReportFileJobError{Code: "rf000008", Message: "render failed", Details: "timeout"}
+	d := amqp.Delivery{Headers: amqp.Table{"x-retry-count": int32(3)}}
+	payload := &reporterpb.ReportFile{Id: "1"}
+
+	err := suite.app.getProcessResult(
+		suite.generateReportBroker,
+		pkg.BrokerGenerateReportTopicName,
+		payload,
+		d,
+		payload.Id,
+		pkg.ReportFileStatusRendering,
+		jobErr,
+	)
+
+	assert.NoError(suite.T(), err)
+	suite.generateReportBroker.AssertNotCalled(suite.T(), "Publish", mock.Anything, mock.Anything, mock.Anything)
+	suite.deadLetterQueue.AssertCalled(suite.T(), "Publish", mock.MatchedBy(func(msg *reporterpb.DeadLetterMessage) bool {
+		return msg.Topic == pkg.BrokerGenerateReportTopicName &&
+			msg.FileId == payload.Id &&
+			msg.Stage == pkg.ReportFileStatusRendering &&
+			msg.RetryCount == int32(3) &&
+			msg.Error.Code == jobErr.Code &&
+			msg.ErrorDetails == jobErr.Details
+	}))
+}
+
+func (suite *DeadLetterTestSuite) TestDeadLetter_getProcessResult_RetriesExhausted_PublishFailed() {
+	suite.deadLetterQueue.On("Publish", mock.Anything).Return(errs.New("error"))
+
+	d := amqp.Delivery{Headers: amqp.Table{"x-retry-count": int32(3)}}
+	payload := &reporterpb.ReportFile{Id: "1"}
+
+	err := suite.app.getProcessResult(
+		suite.generateReportBroker,
+		pkg.BrokerGenerateReportTopicName,
+		payload,
+		d,
+		payload.Id,
+		pkg.ReportFileStatusRendering,
+		nil,
+	)
+
+	assert.Equal(suite.T(), errMessageRedelivery, err)
+	suite.generateReportBroker.AssertNotCalled(suite.T(), "Publish", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *DeadLetterTestSuite) TestDeadLetter_getProcessResult_Retry() {
+	suite.retryQueue.On("Publish", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	d := amqp.Delivery{Headers: amqp.Table{"x-retry-count": int32(1)}}
+	payload := &reporterpb.ReportFile{Id: "1"}
+
+	err := suite.app.getProcessResult(
+		suite.generateReportBroker,
+		pkg.BrokerGenerateReportTopicName,
+		payload,
+		d,
+		payload.Id,
+		pkg.ReportFileStatusRendering,
+		nil,
+	)
+
+	assert.NoError(suite.T(), err)
+	suite.retryQueue.AssertCalled(suite.T(), "Publish", pkg.BrokerGenerateReportTopicName, payload, int32(2), mock.Anything)
+	suite.generateReportBroker.AssertNotCalled(suite.T(), "Publish", mock.Anything, mock.Anything, mock.Anything)
+	suite.deadLetterQueue.AssertNotCalled(suite.T(), "Publish", mock.Anything)
+}
+
+func (suite *DeadLetterTestSuite) TestDeadLetter_getProcessResult_Retry_FallbackWithoutDelay() {
+	suite.retryQueue.On("Publish", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errs.New("error"))
+	suite.postProcessBroker.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	payload := &reporterpb.PostProcessRequest{ReportFile: &reporterpb.ReportFile{Id: "1"}}
+
+	err := suite.app.getProcessResult(
+		suite.postProcessBroker,
+		pkg.BrokerPostProcessTopicName,
+		payload,
+		amqp.Delivery{},
+		payload.ReportFile.Id,
+		pkg.ReportFileStatusPostProcessing,
+		nil,
+	)
+
+	assert.NoError(suite.T(), err)
+	suite.postProcessBroker.AssertCalled(
+		suite.T(),
+		"Publish",
+		pkg.BrokerPostProcessTopicName,
+		payload,
+		amqp.Table{"x-retry-count": int32(1)},
+	)
+}
+
+func (suite *DeadLetterTestSuite) TestDeadLetter_getProcessResult_Retry_FallbackFailed() {
+	suite.retryQueue.On("Publish", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errs.New("error"))
+	suite.postProcessBroker.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(errs.New("error"))
+
+	payload := &reporterpb.PostProcessRequest{ReportFile: &reporterpb.ReportFile{Id: "1"}}
+
+	err := suite.app.getProcessResult(
+		suite.postProcessBroker,
+		pkg.BrokerPostProcessTopicName,
+		payload,
+		amqp.Delivery{},
+		payload.ReportFile.Id,
+		pkg.ReportFileStatusPostProcessing,
+		nil,
+	)
+
+	assert.Equal(suite.T(), errMessageRedelivery, err)
+}
@@ -2,8 +2,10 @@ package internal
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/paysuper/paysuper-reporter/internal/config"
 	"github.com/paysuper/paysuper-reporter/pkg"
 	errs "github.com/paysuper/paysuper-reporter/pkg/errors"
@@ -12,10 +14,11 @@ import (
 	"go.uber.org/zap"
 	"io/ioutil"
 	"net/http"
+	"time"
 )
 
 type DocumentGeneratorInterface interface {
-	Render(payload *proto.GeneratorPayload) ([]byte, error)
+	Render(ctx context.Context, payload *proto.GeneratorPayload) ([]byte, error)
 }
 
 type DocumentGeneratorRenderRequest struct {
@@ -30,6 +33,19 @@ type DocumentGenerator struct {
 	username   string
 	password   string
 	httpClient *http.Client
+	retry      *RetryPolicy
+	breaker    *CircuitBreaker
+}
+
+// documentGeneratorError is the failure of the render attempt. Only the failures caused by the
+// service availability are retried and counted by the circuit breaker.
+type documentGeneratorError struct {
+	err       error
+	retryable bool
+}
+
+func (e *documentGeneratorError) Error() string {
+	return e.err.Error()
 }
 
 func newDocumentGenerator(config *config.DocumentGeneratorConfig) DocumentGeneratorInterface {
@@ -39,19 +55,82 @@ func newDocumentGenerator(config *config.DocumentGeneratorConfig) DocumentGenera
 		username:   config.Username,
 		password:   config.Password,
 		httpClient: tools.NewLoggedHttpClient(zap.S()),
+		retry:      newRetryPolicy(config.RetryMaxCount, config.RetryBaseDelay, config.RetryMaxDelay, config.RetryJitter),
+		breaker:    newCircuitBreaker(config.BreakerThreshold, time.Duration(config.BreakerCooldown)*time.Millisecond),
 	}
 
 	return client
 }
 
-func (dg DocumentGenerator) Render(payload *proto.GeneratorPayload) ([]byte, error) {
+func (dg *DocumentGenerator) Render(ctx context.Context, payload *proto.GeneratorPayload) ([]byte, error) {
+	if dg.timeout > 0 && (payload.Options == nil || payload.Options.Timeout <= 0) {
+		p := *payload
+		p.Options = &proto.GeneratorOptions{Timeout: int64(dg.timeout)}
+		payload = &p
+	}
+
 	b, err := json.Marshal(payload)
 
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", dg.apiUrl+"/api/report", bytes.NewBuffer(b))
+	for attempt := int32(0); ; attempt++ {
+		if err := dg.breaker.Allow(); err != nil {
+			return nil, err
+		}
+
+		msg, err := dg.render(ctx, b)
+
+		if ctx.Err() != nil {
+			// The failure is caused by the job cancellation, so it is not the service failure
+			dg.breaker.Release()
+			return nil, ctx.Err()
+		}
+
+		if err == nil {
+			dg.breaker.Success()
+			return msg, nil
+		}
+
+		rErr, ok := err.(*documentGeneratorError)
+
+		if !ok || !rErr.retryable {
+			dg.breaker.Success()
+			return nil, err
+		}
+
+		dg.breaker.Failure()
+
+		if attempt >= dg.retry.MaxCount {
+			return nil, rErr.err
+		}
+
+		delay := dg.retry.Delay(attempt)
+
+		zap.L().Warn(
+			"Document generator request failed, retrying",
+			zap.Error(rErr.err),
+			zap.Int32("attempt", attempt+1),
+			zap.Duration("delay", delay),
+		)
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (dg *DocumentGenerator) render(ctx context.Context, b []byte) ([]byte, error) {
+	if dg.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(dg.timeout)*time.Millisecond)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, dg.apiUrl+"/api/report", bytes.NewBuffer(b))
 
 	if err != nil {
 		return nil, err
@@ -65,7 +144,7 @@ func (dg DocumentGenerator) Render(payload *proto.GeneratorPayload) ([]byte, err
 	rsp, err := dg.httpClient.Do(req)
 
 	if err != nil {
-		return nil, err
+		return nil, &documentGeneratorError{err: err, retryable: true}
 	}
 
 	defer rsp.Body.Close()
@@ -74,10 +153,16 @@ func (dg DocumentGenerator) Render(payload *proto.GeneratorPayload) ([]byte, err
 	msg, err = ioutil.ReadAll(rsp.Body)
 
 	if err != nil {
-		return nil, err
+		return nil, &documentGeneratorError{err: err, retryable: true}
 	}
 
-	if rsp.StatusCode != 200 {
+	if rsp.StatusCode != http.StatusOK {
+		switch rsp.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			err = fmt.Errorf("error jsreport response code %d: %s", rsp.StatusCode, string(msg))
+			return nil, &documentGeneratorError{err: err, retryable: true}
+		}
+
 		var rspErr map[string]interface{}
 
 		if err = json.Unmarshal(msg, &rspErr); err != nil {
@@ -1,11 +1,17 @@
 package internal
 
 import (
+	"context"
+	"encoding/json"
 	"github.com/paysuper/paysuper-reporter/internal/config"
 	"github.com/paysuper/paysuper-reporter/pkg/proto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 type DocumentGeneratorTestSuite struct {
@@ -23,12 +29,150 @@ func (suite *DocumentGeneratorTestSuite) TestDocumentGenerator_newDocumentGenera
 
 func (suite *CentrifugoTestSuite) TestDocumentGenerator_Render_Error_Marshal() {
 	dg := newDocumentGenerator(&config.DocumentGeneratorConfig{})
-	_, err := dg.Render(&proto.GeneratorPayload{Data: make(chan int)})
+	_, err := dg.Render(context.Background(), &proto.GeneratorPayload{Data: make(chan int)})
 	assert.Error(suite.T(), err)
 }
 
 func (suite *CentrifugoTestSuite) TestDocumentGenerator_Render_Error_Client() {
 	dg := newDocumentGenerator(&config.DocumentGeneratorConfig{})
-	_, err := dg.Render(&proto.GeneratorPayload{})
+	_, err := dg.Render(context.Background(), &proto.GeneratorPayload{})
 	assert.Error(suite.T(), err)
 }
+
+func (suite *DocumentGeneratorTestSuite) TestDocumentGenerator_Render_Ok_Timeout() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload := &proto.GeneratorPayload{}
+		assert.NoError(suite.T(), json.NewDecoder(r.Body).Decode(payload))
+		assert.NotNil(suite.T(), payload.Options)
+		assert.EqualValues(suite.T(), 1000, payload.Options.Timeout)
+		_, _ = w.Write([]byte("file"))
+	}))
+	defer server.Close()
+
+	dg := newDocumentGenerator(&config.DocumentGeneratorConfig{ApiUrl: server.URL, Timeout: 1000})
+	b, err := dg.Render(context.Background(), &proto.GeneratorPayload{})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "file", string(b))
+}
+
+func (suite *DocumentGeneratorTestSuite) TestDocumentGenerator_Render_Error_Deadline() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	dg := newDocumentGenerator(&config.DocumentGeneratorConfig{ApiUrl: server.URL, Timeout: 20})
+	_, err := dg.Render(context.Background(), &proto.GeneratorPayload{})
+	assert.Error(suite.T(), err)
+}
+
+func (suite *DocumentGeneratorTestSuite) TestDocumentGenerator_Render_Ok_Retry() {
+	var calls int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		_, _ = w.Write([]byte("file"))
+	}))
+	defer server.Close()
+
+	dg := newDocumentGenerator(&config.DocumentGeneratorConfig{
+		ApiUrl:         server.URL,
+		RetryMaxCount:  2,
+		RetryBaseDelay: 1,
+		RetryMaxDelay:  1,
+	})
+	b, err := dg.Render(context.Background(), &proto.GeneratorPayload{})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "file", string(b))
+	assert.EqualValues(suite.T(), 3, atomic.LoadInt32(&calls))
+}
+
+func (suite *DocumentGeneratorTestSuite) TestDocumentGenerator_Render_Error_RetriesExhausted() {
+	var calls int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	dg := newDocumentGenerator(&config.DocumentGeneratorConfig{
+		ApiUrl:         server.URL,
+		RetryMaxCount:  1,
+		RetryBaseDelay: 1,
+		RetryMaxDelay:  1,
+	})
+	_, err := dg.Render(context.Background(), &proto.GeneratorPayload{})
+	assert.Error(suite.T(), err)
+	assert.EqualValues(suite.T(), 2, atomic.LoadInt32(&calls))
+}
+
+func (suite *DocumentGeneratorTestSuite) TestDocumentGenerator_Render_Error_NotRetryable() {
+	var calls int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"message": "template not found"}`))
+	}))
+	defer server.Close()
+
+	dg := newDocumentGenerator(&config.DocumentGeneratorConfig{ApiUrl: server.URL, RetryMaxCount: 3, BreakerThreshold: 1})
+	_, err := dg.Render(context.Background(), &proto.GeneratorPayload{})
+	assert.Error(suite.T(), err)
+	assert.EqualValues(suite.T(), 1, atomic.LoadInt32(&calls))
+
+	_, err = dg.Render(context.Background(), &proto.GeneratorPayload{})
+	assert.NotEqual(suite.T(), errCircuitBreakerOpen, err)
+}
+
+func (suite *DocumentGeneratorTestSuite) TestDocumentGenerator_Render_Error_CircuitBreakerOpen() {
+	var calls int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	dg := newDocumentGenerator(&config.DocumentGeneratorConfig{
+		ApiUrl:           server.URL,
+		BreakerThreshold: 2,
+		BreakerCooldown:  60000,
+	})
+
+	for i := 0; i < 2; i++ {
+		_, err := dg.Render(context.Background(), &proto.GeneratorPayload{})
+		assert.Error(suite.T(), err)
+		assert.NotEqual(suite.T(), errCircuitBreakerOpen, err)
+	}
+
+	_, err := dg.Render(context.Background(), &proto.GeneratorPayload{})
+	assert.Equal(suite.T(), errCircuitBreakerOpen, err)
+	assert.EqualValues(suite.T(), 2, atomic.LoadInt32(&calls))
+}
+
+func (suite *DocumentGeneratorTestSuite) TestDocumentGenerator_Render_Error_ContextDeadline() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	dg := newDocumentGenerator(&config.DocumentGeneratorConfig{
+		ApiUrl:           server.URL,
+		RetryMaxCount:    3,
+		BreakerThreshold: 1,
+		BreakerCooldown:  60000,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := dg.Render(ctx, &proto.GeneratorPayload{})
+	assert.Equal(suite.T(), context.DeadlineExceeded, err)
+	assert.NoError(suite.T(), dg.(*DocumentGenerator).breaker.Allow())
+}
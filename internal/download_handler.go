@@ -0,0 +1,148 @@
+package internal
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	errDownloadTokenInvalid = errors.New("download token is invalid")
+	errDownloadTokenExpired = errors.New("download token has expired")
+)
+
+// downloadToken identifies the report file downloaded through the download server until the expiration time.
+type downloadToken struct {
+	Bucket      string `json:"b"`
+	Key         string `json:"k"`
+	ContentType string `json:"t,omitempty"`
+	ExpiresAt   int64  `json:"e"`
+}
+
+// DownloadHandler serves the encrypted report files decrypted by the download tokens signed by EncryptedStorage.
+type DownloadHandler struct {
+	storages *StorageRouter
+	secret   []byte
+	now      func() time.Time
+}
+
+func newDownloadHandler(storages *StorageRouter, secret string) *DownloadHandler {
+	return &DownloadHandler{storages: storages, secret: []byte(secret), now: time.Now}
+}
+
+func (h *DownloadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	token, err := parseDownloadToken(h.secret, r.URL.Query().Get("token"), h.now())
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	storage, err := h.storages.GetStorage(token.Bucket, "")
+
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	content, err := storage.Get(r.Context(), token.Key)
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+
+		zap.L().Error(
+			"Unable to download report file",
+			zap.Error(err),
+			zap.String("bucket", token.Bucket),
+			zap.String("file_name", token.Key),
+		)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+		return
+	}
+
+	contentType := token.ContentType
+
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", path.Base(token.Key)))
+	w.Header().Set("Cache-Control", "no-store")
+
+	if _, err = w.Write(content); err != nil {
+		zap.L().Error("Unable to write report file to the response", zap.Error(err), zap.String("file_name", token.Key))
+	}
+}
+
+// signDownloadToken returns the token encoded to base64 and its HMAC-SHA256 signature joined by the dot.
+func signDownloadToken(secret []byte, token *downloadToken) (string, error) {
+	b, err := json.Marshal(token)
+
+	if err != nil {
+		return "", err
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString(b)
+
+	return payload + "." + base64.RawURLEncoding.EncodeToString(getDownloadTokenSignature(secret, payload)), nil
+}
+
+func parseDownloadToken(secret []byte, value string, now time.Time) (*downloadToken, error) {
+	parts := strings.Split(value, ".")
+
+	if len(parts) != 2 {
+		return nil, errDownloadTokenInvalid
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+
+	if err != nil || !hmac.Equal(signature, getDownloadTokenSignature(secret, parts[0])) {
+		return nil, errDownloadTokenInvalid
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(parts[0])
+
+	if err != nil {
+		return nil, errDownloadTokenInvalid
+	}
+
+	token := &downloadToken{}
+
+	if err = json.Unmarshal(b, token); err != nil || token.Bucket == "" || token.Key == "" {
+		return nil, errDownloadTokenInvalid
+	}
+
+	if now.Unix() > token.ExpiresAt {
+		return nil, errDownloadTokenExpired
+	}
+
+	return token, nil
+}
+
+func getDownloadTokenSignature(secret []byte, payload string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,119 @@
+package internal
+
+import (
+	"context"
+	"github.com/paysuper/paysuper-reporter/pkg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+)
+
+type DownloadHandlerTestSuite struct {
+	suite.Suite
+	dir     string
+	storage *EncryptedStorage
+	handler *DownloadHandler
+	now     time.Time
+}
+
+func Test_DownloadHandler(t *testing.T) {
+	suite.Run(t, new(DownloadHandlerTestSuite))
+}
+
+func (suite *DownloadHandlerTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "download_handler")
+	assert.NoError(suite.T(), err)
+	suite.dir = dir
+
+	local, err := newLocalStorage(dir, "", "reports")
+	assert.NoError(suite.T(), err)
+
+	keys, err := newLocalKeyProvider(getTestKeyFile("key1"), "key1")
+	assert.NoError(suite.T(), err)
+
+	suite.now = time.Date(2020, 1, 15, 10, 30, 0, 0, time.UTC)
+	suite.storage = newEncryptedStorage(local, keys, "", "secret").(*EncryptedStorage)
+	suite.storage.now = func() time.Time { return suite.now }
+
+	router, err := newStorageRouter(map[string]StorageInterface{pkg.StorageTargetReports: suite.storage}, nil)
+	assert.NoError(suite.T(), err)
+
+	suite.handler = newDownloadHandler(router, "secret")
+	suite.handler.now = func() time.Time { return suite.now }
+}
+
+func (suite *DownloadHandlerTestSuite) TearDownTest() {
+	_ = os.RemoveAll(suite.dir)
+}
+
+func (suite *DownloadHandlerTestSuite) TestDownloadHandler_ServeHTTP_Ok() {
+	assert.NoError(suite.T(), suite.storage.Upload(context.TODO(), "payouts/report.pdf", []byte("content"), nil))
+
+	link, err := suite.storage.Presign("payouts/report.pdf", "application/pdf", time.Hour)
+	assert.NoError(suite.T(), err)
+
+	rec := suite.serve(link)
+	assert.Equal(suite.T(), http.StatusOK, rec.Code)
+	assert.Equal(suite.T(), "content", rec.Body.String())
+	assert.Equal(suite.T(), "application/pdf", rec.Header().Get("Content-Type"))
+	assert.Equal(suite.T(), `attachment; filename="report.pdf"`, rec.Header().Get("Content-Disposition"))
+	assert.Equal(suite.T(), "no-store", rec.Header().Get("Cache-Control"))
+}
+
+func (suite *DownloadHandlerTestSuite) TestDownloadHandler_ServeHTTP_Error_Token() {
+	link, err := suite.storage.Presign("report.pdf", "", time.Hour)
+	assert.NoError(suite.T(), err)
+
+	u, _ := url.Parse(link)
+	token := u.Query().Get("token")
+
+	rec := suite.serve(pkg.EncryptionDownloadPath + "?token=" + url.QueryEscape(token+"a"))
+	assert.Equal(suite.T(), http.StatusForbidden, rec.Code)
+
+	rec = suite.serve(pkg.EncryptionDownloadPath + "?token=invalid")
+	assert.Equal(suite.T(), http.StatusForbidden, rec.Code)
+
+	suite.now = suite.now.Add(2 * time.Hour)
+	rec = suite.serve(link)
+	assert.Equal(suite.T(), http.StatusForbidden, rec.Code)
+	assert.Contains(suite.T(), rec.Body.String(), errDownloadTokenExpired.Error())
+
+	forged, err := signDownloadToken([]byte("other"), &downloadToken{Bucket: "reports", Key: "report.pdf", ExpiresAt: suite.now.Unix()})
+	assert.NoError(suite.T(), err)
+	rec = suite.serve(pkg.EncryptionDownloadPath + "?token=" + url.QueryEscape(forged))
+	assert.Equal(suite.T(), http.StatusForbidden, rec.Code)
+}
+
+func (suite *DownloadHandlerTestSuite) TestDownloadHandler_ServeHTTP_Error_NotFound() {
+	link, err := suite.storage.Presign("report.pdf", "", time.Hour)
+	assert.NoError(suite.T(), err)
+
+	rec := suite.serve(link)
+	assert.Equal(suite.T(), http.StatusNotFound, rec.Code)
+
+	token, err := signDownloadToken([]byte("secret"), &downloadToken{Bucket: "unknown", Key: "report.pdf", ExpiresAt: suite.now.Unix()})
+	assert.NoError(suite.T(), err)
+	rec = suite.serve(pkg.EncryptionDownloadPath + "?token=" + url.QueryEscape(token))
+	assert.Equal(suite.T(), http.StatusNotFound, rec.Code)
+}
+
+func (suite *DownloadHandlerTestSuite) TestDownloadHandler_ServeHTTP_Error_Method() {
+	req := httptest.NewRequest(http.MethodPost, pkg.EncryptionDownloadPath, nil)
+	rec := httptest.NewRecorder()
+	suite.handler.ServeHTTP(rec, req)
+	assert.Equal(suite.T(), http.StatusMethodNotAllowed, rec.Code)
+}
+
+func (suite *DownloadHandlerTestSuite) serve(target string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	rec := httptest.NewRecorder()
+	suite.handler.ServeHTTP(rec, req)
+
+	return rec
+}
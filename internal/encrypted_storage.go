@@ -0,0 +1,188 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"github.com/paysuper/paysuper-reporter/pkg"
+	"github.com/paysuper/paysuper-reporter/pkg/proto"
+	"io"
+	"net/url"
+	"time"
+)
+
+const encryptionKeyIdMaxLength = 255
+
+var (
+	encryptedFileMagic = []byte("RPTENC01")
+
+	errEncryptedFileCorrupted = errors.New("encrypted report file is corrupted")
+)
+
+// EncryptedStorage encrypts the report files by the envelope encryption before the upload to the storage and
+// decrypts them on read. Every file is encrypted by AES-256-GCM with its own data key, the data key encrypted by
+// the key provider and the key identifier are stored in the header of the file:
+//
+//	magic | key id length (1 byte) | key id | encrypted data key length (2 bytes) | encrypted data key | nonce | data
+//
+// Files without the header were uploaded before the encryption was enabled and are read as is. The storage can not
+// decrypt the files on the presigned download, so the download URLs point to the download server of the service.
+type EncryptedStorage struct {
+	storage        StorageInterface
+	keys           KeyProviderInterface
+	downloadUrl    string
+	downloadSecret []byte
+	now            func() time.Time
+}
+
+func newEncryptedStorage(
+	storage StorageInterface,
+	keys KeyProviderInterface,
+	downloadUrl, downloadSecret string,
+) StorageInterface {
+	return &EncryptedStorage{
+		storage:        storage,
+		keys:           keys,
+		downloadUrl:    downloadUrl,
+		downloadSecret: []byte(downloadSecret),
+		now:            time.Now,
+	}
+}
+
+func (s *EncryptedStorage) Upload(ctx context.Context, fileName string, content []byte, opts *proto.StorageUploadOptions) error {
+	encrypted, err := s.encrypt(content)
+
+	if err != nil {
+		return err
+	}
+
+	return s.storage.Upload(ctx, fileName, encrypted, opts)
+}
+
+func (s *EncryptedStorage) Get(ctx context.Context, fileName string) ([]byte, error) {
+	content, err := s.storage.Get(ctx, fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.decrypt(content)
+}
+
+func (s *EncryptedStorage) Delete(ctx context.Context, fileName string) error {
+	return s.storage.Delete(ctx, fileName)
+}
+
+// Presign returns the URL of the download server signed until the expiration time.
+func (s *EncryptedStorage) Presign(fileName, contentType string, expire time.Duration) (string, error) {
+	token := &downloadToken{
+		Bucket:      s.storage.Bucket(),
+		Key:         fileName,
+		ContentType: contentType,
+		ExpiresAt:   s.now().Add(expire).Unix(),
+	}
+	signed, err := signDownloadToken(s.downloadSecret, token)
+
+	if err != nil {
+		return "", err
+	}
+
+	return s.downloadUrl + pkg.EncryptionDownloadPath + "?" + url.Values{"token": {signed}}.Encode(), nil
+}
+
+func (s *EncryptedStorage) Bucket() string {
+	return s.storage.Bucket()
+}
+
+func (s *EncryptedStorage) encrypt(content []byte) ([]byte, error) {
+	keyId := s.keys.ActiveKeyId()
+	dataKey := make([]byte, encryptionKeySize)
+
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, err
+	}
+
+	wrapped, err := s.keys.WrapKey(keyId, dataKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newGcm(dataKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	header := &bytes.Buffer{}
+	header.Write(encryptedFileMagic)
+	header.WriteByte(byte(len(keyId)))
+	header.WriteString(keyId)
+	_ = binary.Write(header, binary.BigEndian, uint16(len(wrapped)))
+	header.Write(wrapped)
+	header.Write(nonce)
+
+	// The header is authenticated with the content, so the key identifier can not be replaced
+	return aead.Seal(header.Bytes(), nonce, content, header.Bytes()), nil
+}
+
+func (s *EncryptedStorage) decrypt(content []byte) ([]byte, error) {
+	if !bytes.HasPrefix(content, encryptedFileMagic) {
+		return content, nil
+	}
+
+	r := bytes.NewReader(content[len(encryptedFileMagic):])
+	keyIdLength, err := r.ReadByte()
+
+	if err != nil {
+		return nil, errEncryptedFileCorrupted
+	}
+
+	keyId := make([]byte, keyIdLength)
+
+	if _, err = io.ReadFull(r, keyId); err != nil {
+		return nil, errEncryptedFileCorrupted
+	}
+
+	var wrappedLength uint16
+
+	if err = binary.Read(r, binary.BigEndian, &wrappedLength); err != nil {
+		return nil, errEncryptedFileCorrupted
+	}
+
+	wrapped := make([]byte, wrappedLength)
+
+	if _, err = io.ReadFull(r, wrapped); err != nil {
+		return nil, errEncryptedFileCorrupted
+	}
+
+	dataKey, err := s.keys.UnwrapKey(string(keyId), wrapped)
+
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newGcm(dataKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+
+	if _, err = io.ReadFull(r, nonce); err != nil {
+		return nil, errEncryptedFileCorrupted
+	}
+
+	headerLength := len(content) - r.Len()
+
+	return aead.Open(nil, nonce, content[headerLength:], content[:headerLength])
+}
@@ -0,0 +1,142 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"github.com/paysuper/paysuper-reporter/pkg/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+)
+
+type EncryptedStorageTestSuite struct {
+	suite.Suite
+	dir     string
+	local   StorageInterface
+	keys    KeyProviderInterface
+	storage *EncryptedStorage
+}
+
+func Test_EncryptedStorage(t *testing.T) {
+	suite.Run(t, new(EncryptedStorageTestSuite))
+}
+
+func (suite *EncryptedStorageTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "encrypted_storage")
+	assert.NoError(suite.T(), err)
+
+	suite.dir = dir
+	suite.local, err = newLocalStorage(dir, "", "reports")
+	assert.NoError(suite.T(), err)
+
+	suite.keys, err = newLocalKeyProvider(getTestKeyFile("key1", "key2"), "key1")
+	assert.NoError(suite.T(), err)
+
+	suite.storage = newEncryptedStorage(suite.local, suite.keys, "https://reporter.local", "secret").(*EncryptedStorage)
+}
+
+func (suite *EncryptedStorageTestSuite) TearDownTest() {
+	_ = os.RemoveAll(suite.dir)
+}
+
+func (suite *EncryptedStorageTestSuite) TestEncryptedStorage_Upload_Ok() {
+	content := []byte("%PDF merchant bank details")
+	err := suite.storage.Upload(context.TODO(), "report.pdf", content, &proto.StorageUploadOptions{})
+	assert.NoError(suite.T(), err)
+
+	stored, err := suite.local.Get(context.TODO(), "report.pdf")
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), bytes.HasPrefix(stored, encryptedFileMagic))
+	assert.False(suite.T(), bytes.Contains(stored, []byte("bank details")))
+	assert.True(suite.T(), bytes.Contains(stored, []byte("key1")))
+
+	decrypted, err := suite.storage.Get(context.TODO(), "report.pdf")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), content, decrypted)
+}
+
+func (suite *EncryptedStorageTestSuite) TestEncryptedStorage_Get_Rotated() {
+	content := []byte("content")
+	assert.NoError(suite.T(), suite.storage.Upload(context.TODO(), "report.pdf", content, nil))
+
+	rotated, err := newLocalKeyProvider(getTestKeyFile("key1", "key2"), "key2")
+	assert.NoError(suite.T(), err)
+	storage := newEncryptedStorage(suite.local, rotated, "", "secret")
+
+	decrypted, err := storage.Get(context.TODO(), "report.pdf")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), content, decrypted)
+
+	assert.NoError(suite.T(), storage.Upload(context.TODO(), "report2.pdf", content, nil))
+	stored, err := suite.local.Get(context.TODO(), "report2.pdf")
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), bytes.Contains(stored, []byte("key2")))
+
+	retired, err := newLocalKeyProvider(getTestKeyFile("key2"), "key2")
+	assert.NoError(suite.T(), err)
+
+	_, err = newEncryptedStorage(suite.local, retired, "", "secret").Get(context.TODO(), "report.pdf")
+	assert.EqualError(suite.T(), err, "encryption key key1 is not found")
+}
+
+func (suite *EncryptedStorageTestSuite) TestEncryptedStorage_Get_Plain() {
+	assert.NoError(suite.T(), suite.local.Upload(context.TODO(), "report.pdf", []byte("content"), nil))
+
+	content, err := suite.storage.Get(context.TODO(), "report.pdf")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []byte("content"), content)
+}
+
+func (suite *EncryptedStorageTestSuite) TestEncryptedStorage_Get_Error_Corrupted() {
+	assert.NoError(suite.T(), suite.storage.Upload(context.TODO(), "report.pdf", []byte("content"), nil))
+	stored, err := suite.local.Get(context.TODO(), "report.pdf")
+	assert.NoError(suite.T(), err)
+
+	tampered := append([]byte{}, stored...)
+	tampered[len(tampered)-1] ^= 1
+	assert.NoError(suite.T(), suite.local.Upload(context.TODO(), "report.pdf", tampered, nil))
+	_, err = suite.storage.Get(context.TODO(), "report.pdf")
+	assert.Error(suite.T(), err)
+
+	assert.NoError(suite.T(), suite.local.Upload(context.TODO(), "report.pdf", stored[:len(encryptedFileMagic)+3], nil))
+	_, err = suite.storage.Get(context.TODO(), "report.pdf")
+	assert.Equal(suite.T(), errEncryptedFileCorrupted, err)
+
+	_, err = suite.storage.Get(context.TODO(), "unknown.pdf")
+	assert.True(suite.T(), os.IsNotExist(err))
+}
+
+func (suite *EncryptedStorageTestSuite) TestEncryptedStorage_Presign() {
+	now := time.Date(2020, 1, 15, 10, 30, 0, 0, time.UTC)
+	suite.storage.now = func() time.Time { return now }
+
+	link, err := suite.storage.Presign("payouts/report.pdf", "application/pdf", time.Hour)
+	assert.NoError(suite.T(), err)
+
+	u, err := url.Parse(link)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "reporter.local", u.Host)
+	assert.Equal(suite.T(), "/download", u.Path)
+
+	token, err := parseDownloadToken([]byte("secret"), u.Query().Get("token"), now)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), &downloadToken{
+		Bucket:      "reports",
+		Key:         "payouts/report.pdf",
+		ContentType: "application/pdf",
+		ExpiresAt:   now.Add(time.Hour).Unix(),
+	}, token)
+}
+
+func (suite *EncryptedStorageTestSuite) TestEncryptedStorage_Delete() {
+	assert.NoError(suite.T(), suite.storage.Upload(context.TODO(), "report.pdf", []byte("content"), nil))
+	assert.NoError(suite.T(), suite.storage.Delete(context.TODO(), "report.pdf"))
+
+	_, err := suite.local.Get(context.TODO(), "report.pdf")
+	assert.True(suite.T(), os.IsNotExist(err))
+	assert.Equal(suite.T(), "reports", suite.storage.Bucket())
+}
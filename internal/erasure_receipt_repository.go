@@ -0,0 +1,36 @@
+package internal
+
+import (
+	"context"
+	"github.com/paysuper/paysuper-reporter/pkg"
+	"github.com/paysuper/paysuper-reporter/pkg/errors"
+	"github.com/paysuper/paysuper-reporter/pkg/proto"
+	"go.uber.org/zap"
+	mongodb "gopkg.in/paysuper/paysuper-database-mongo.v2"
+)
+
+type ErasureReceiptRepositoryInterface interface {
+	Insert(ctx context.Context, receipt *proto.ErasureReceipt) error
+}
+
+type ErasureReceiptRepository struct {
+	db mongodb.SourceInterface
+}
+
+func newErasureReceiptRepository(db mongodb.SourceInterface) ErasureReceiptRepositoryInterface {
+	return &ErasureReceiptRepository{db: db}
+}
+
+func (r *ErasureReceiptRepository) Insert(ctx context.Context, receipt *proto.ErasureReceipt) error {
+	if _, err := r.db.Collection(pkg.CollectionErasureReceipt).InsertOne(ctx, receipt); err != nil {
+		zap.L().Error(
+			errors.ErrorDatabaseQueryFailed.Message,
+			zap.Error(err),
+			zap.String("collection", pkg.CollectionErasureReceipt),
+			zap.Any("document", receipt),
+		)
+		return err
+	}
+
+	return nil
+}
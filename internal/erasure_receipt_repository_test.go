@@ -0,0 +1,67 @@
+package internal
+
+import (
+	"context"
+	"github.com/paysuper/paysuper-reporter/pkg"
+	"github.com/paysuper/paysuper-reporter/pkg/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongodb "gopkg.in/paysuper/paysuper-database-mongo.v2"
+	"testing"
+	"time"
+)
+
+type ErasureReceiptRepositoryTestSuite struct {
+	suite.Suite
+	db         mongodb.SourceInterface
+	repository ErasureReceiptRepositoryInterface
+}
+
+func Test_ErasureReceiptRepository(t *testing.T) {
+	suite.Run(t, new(ErasureReceiptRepositoryTestSuite))
+}
+
+func (suite *ErasureReceiptRepositoryTestSuite) SetupTest() {
+	db, err := mongodb.NewDatabase()
+	assert.NoError(suite.T(), err, "Database connection failed")
+
+	suite.db = db
+	suite.repository = newErasureReceiptRepository(db)
+}
+
+func (suite *ErasureReceiptRepositoryTestSuite) TearDownTest() {
+	if err := suite.db.Drop(); err != nil {
+		suite.FailNow("Database deletion failed", "%v", err)
+	}
+
+	if err := suite.db.Close(); err != nil {
+		suite.FailNow("Database close failed", "%v", err)
+	}
+}
+
+func (suite *ErasureReceiptRepositoryTestSuite) TestErasureReceiptRepository_Insert_Ok() {
+	now := time.Now()
+	receipt := &proto.ErasureReceipt{
+		Id:         primitive.NewObjectID(),
+		MerchantId: "ffffffffffffffffffffffff",
+		Reason:     "gdpr",
+		Status:     pkg.ErasureStatusCompleted,
+		Erased:     1,
+		Items: []*proto.ErasureReceiptItem{
+			{FileId: primitive.NewObjectID().Hex(), KeyHash: getErasureKeyHash("report.pdf"), Result: pkg.ErasureResultErased},
+		},
+		CreatedAt:   now,
+		CompletedAt: now,
+	}
+	assert.NoError(suite.T(), suite.repository.Insert(context.TODO(), receipt))
+
+	stored := &proto.ErasureReceipt{}
+	err := suite.db.Collection(pkg.CollectionErasureReceipt).FindOne(context.TODO(), bson.M{"_id": receipt.Id}).Decode(stored)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), receipt.MerchantId, stored.MerchantId)
+	assert.Equal(suite.T(), int32(1), stored.Erased)
+	assert.Len(suite.T(), stored.Items, 1)
+	assert.Equal(suite.T(), receipt.Items[0].KeyHash, stored.Items[0].KeyHash)
+}
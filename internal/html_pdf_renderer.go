@@ -0,0 +1,81 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/paysuper/paysuper-reporter/pkg"
+	"github.com/paysuper/paysuper-reporter/pkg/proto"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
+	tools "github.com/paysuper/paysuper-tools/http"
+	"go.uber.org/zap"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// HtmlPdfRenderer renders the report template to HTML with the document generator and converts it
+// to PDF with the HTML to PDF sidecar service.
+type HtmlPdfRenderer struct {
+	html       DocumentGeneratorInterface
+	apiUrl     string
+	httpClient *http.Client
+}
+
+func newHtmlPdfRenderer(html DocumentGeneratorInterface, apiUrl string, timeout int) DocumentGeneratorInterface {
+	httpClient := tools.NewLoggedHttpClient(zap.S())
+	httpClient.Timeout = time.Duration(timeout) * time.Millisecond
+
+	return &HtmlPdfRenderer{
+		html:       html,
+		apiUrl:     apiUrl,
+		httpClient: httpClient,
+	}
+}
+
+func (r *HtmlPdfRenderer) Supports(_, fileType string) bool {
+	return fileType == reporterpb.OutputExtensionPdf
+}
+
+func (r *HtmlPdfRenderer) Render(ctx context.Context, payload *proto.GeneratorPayload) ([]byte, error) {
+	htmlPayload := *payload
+
+	if payload.Template != nil {
+		template := *payload.Template
+		template.Recipe = pkg.RecipeHtml
+		htmlPayload.Template = &template
+	}
+
+	html, err := r.html.Render(ctx, &htmlPayload)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.apiUrl, bytes.NewBuffer(html))
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", pkg.MIMETextHtml)
+	rsp, err := r.httpClient.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rsp.Body.Close()
+
+	b, err := ioutil.ReadAll(rsp.Body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if rsp.StatusCode != http.StatusOK {
+		return nil, errors.New("error html to pdf response code: " + string(b))
+	}
+
+	return b, nil
+}
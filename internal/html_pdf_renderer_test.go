@@ -0,0 +1,80 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"github.com/paysuper/paysuper-reporter/internal/mocks"
+	"github.com/paysuper/paysuper-reporter/pkg"
+	"github.com/paysuper/paysuper-reporter/pkg/proto"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type HtmlPdfRendererTestSuite struct {
+	suite.Suite
+	html *mocks.DocumentGeneratorInterface
+}
+
+func Test_HtmlPdfRenderer(t *testing.T) {
+	suite.Run(t, new(HtmlPdfRendererTestSuite))
+}
+
+func (suite *HtmlPdfRendererTestSuite) SetupTest() {
+	suite.html = &mocks.DocumentGeneratorInterface{}
+}
+
+func (suite *HtmlPdfRendererTestSuite) TestHtmlPdfRenderer_Render_Ok() {
+	suite.html.On("Render", mock.Anything, mock.Anything).Return([]byte("<html></html>"), nil)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		assert.Equal(suite.T(), "<html></html>", string(body))
+		assert.Equal(suite.T(), pkg.MIMETextHtml, r.Header.Get("Content-Type"))
+		_, _ = w.Write([]byte("%PDF"))
+	}))
+	defer server.Close()
+
+	template := &proto.GeneratorTemplate{ShortId: "template", Recipe: pkg.RecipePdf}
+	r := newHtmlPdfRenderer(suite.html, server.URL, 1000)
+	b, err := r.Render(context.Background(), &proto.GeneratorPayload{Template: template})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "%PDF", string(b))
+	assert.Equal(suite.T(), pkg.RecipePdf, template.Recipe)
+	suite.html.AssertCalled(suite.T(), "Render", mock.Anything, mock.MatchedBy(func(payload *proto.GeneratorPayload) bool {
+		return payload.Template.ShortId == "template" && payload.Template.Recipe == pkg.RecipeHtml
+	}))
+}
+
+func (suite *HtmlPdfRendererTestSuite) TestHtmlPdfRenderer_Render_Error_Html() {
+	suite.html.On("Render", mock.Anything, mock.Anything).Return(nil, errors.New("error"))
+
+	r := newHtmlPdfRenderer(suite.html, "http://127.0.0.1:1", 1000)
+	_, err := r.Render(context.Background(), &proto.GeneratorPayload{})
+	assert.EqualError(suite.T(), err, "error")
+}
+
+func (suite *HtmlPdfRendererTestSuite) TestHtmlPdfRenderer_Render_Error_Status() {
+	suite.html.On("Render", mock.Anything, mock.Anything).Return([]byte("<html></html>"), nil)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	r := newHtmlPdfRenderer(suite.html, server.URL, 1000)
+	_, err := r.Render(context.Background(), &proto.GeneratorPayload{})
+	assert.Error(suite.T(), err)
+}
+
+func (suite *HtmlPdfRendererTestSuite) TestHtmlPdfRenderer_Supports() {
+	r := newHtmlPdfRenderer(suite.html, "", 1000).(RendererSupportInterface)
+	assert.True(suite.T(), r.Supports(reporterpb.ReportTypeRoyalty, reporterpb.OutputExtensionPdf))
+	assert.False(suite.T(), r.Supports(reporterpb.ReportTypeRoyalty, reporterpb.OutputExtensionXlsx))
+}
@@ -0,0 +1,76 @@
+package internal
+
+import (
+	"sync"
+	"time"
+)
+
+// JobTracker counts the report file jobs in progress, so the application is able to wait for them on shutdown.
+type JobTracker struct {
+	mx      sync.Mutex
+	count   int
+	stopped bool
+	closed  bool
+	done    chan struct{}
+}
+
+func newJobTracker() *JobTracker {
+	return &JobTracker{done: make(chan struct{})}
+}
+
+// Start registers the new job. It returns false when the tracker is stopped and the job must not be processed.
+func (t *JobTracker) Start() bool {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	if t.stopped {
+		return false
+	}
+
+	t.count++
+
+	return true
+}
+
+func (t *JobTracker) Done() {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	t.count--
+	t.closeIfDrained()
+}
+
+// Stop rejects the new jobs, the jobs in progress are processed as usual.
+func (t *JobTracker) Stop() {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	t.stopped = true
+	t.closeIfDrained()
+}
+
+// Wait waits for the jobs in progress of the stopped tracker. It returns false when the timeout has been reached
+// before all the jobs were done.
+func (t *JobTracker) Wait(timeout time.Duration) bool {
+	select {
+	case <-t.done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// Count returns the count of the jobs in progress.
+func (t *JobTracker) Count() int {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	return t.count
+}
+
+func (t *JobTracker) closeIfDrained() {
+	if t.stopped && t.count == 0 && !t.closed {
+		t.closed = true
+		close(t.done)
+	}
+}
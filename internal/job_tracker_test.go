@@ -0,0 +1,50 @@
+package internal
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type JobTrackerTestSuite struct {
+	suite.Suite
+	tracker *JobTracker
+}
+
+func Test_JobTracker(t *testing.T) {
+	suite.Run(t, new(JobTrackerTestSuite))
+}
+
+func (suite *JobTrackerTestSuite) SetupTest() {
+	suite.tracker = newJobTracker()
+}
+
+func (suite *JobTrackerTestSuite) TestJobTracker_Wait_NoJobs() {
+	suite.tracker.Stop()
+	assert.True(suite.T(), suite.tracker.Wait(time.Millisecond))
+}
+
+func (suite *JobTrackerTestSuite) TestJobTracker_Wait_JobsDone() {
+	assert.True(suite.T(), suite.tracker.Start())
+	assert.True(suite.T(), suite.tracker.Start())
+	assert.Equal(suite.T(), 2, suite.tracker.Count())
+
+	suite.tracker.Stop()
+	suite.tracker.Done()
+	assert.False(suite.T(), suite.tracker.Wait(time.Millisecond))
+
+	go suite.tracker.Done()
+	assert.True(suite.T(), suite.tracker.Wait(time.Second))
+	assert.Equal(suite.T(), 0, suite.tracker.Count())
+}
+
+func (suite *JobTrackerTestSuite) TestJobTracker_Start_Stopped() {
+	suite.tracker.Stop()
+	assert.False(suite.T(), suite.tracker.Start())
+	assert.Equal(suite.T(), 0, suite.tracker.Count())
+}
+
+func (suite *JobTrackerTestSuite) TestJobTracker_Wait_NotStopped() {
+	assert.False(suite.T(), suite.tracker.Wait(time.Millisecond))
+}
@@ -0,0 +1,146 @@
+package internal
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/paysuper/paysuper-reporter/internal/config"
+	"github.com/paysuper/paysuper-reporter/pkg"
+	"io"
+	"io/ioutil"
+)
+
+const encryptionKeySize = 32
+
+var (
+	errKeyFileEmpty       = errors.New("encryption keyfile is empty")
+	errWrappedKeyTooShort = errors.New("wrapped data key is too short")
+)
+
+// KeyProviderInterface encrypts the data keys of the report files by the key encryption keys. The identifier
+// of the key is stored with every file, so the files encrypted by the retired keys are decrypted after the rotation.
+type KeyProviderInterface interface {
+	// ActiveKeyId returns the identifier of the key encrypting the data keys of the new files.
+	ActiveKeyId() string
+	WrapKey(keyId string, dataKey []byte) ([]byte, error)
+	UnwrapKey(keyId string, wrapped []byte) ([]byte, error)
+}
+
+// LocalKeyProvider encrypts the data keys by AES-256-GCM with the keys read from the local keyfile.
+type LocalKeyProvider struct {
+	keys        map[string]cipher.AEAD
+	activeKeyId string
+}
+
+// newKeyProvider creates the key provider set in the config.
+func newKeyProvider(cfg *config.EncryptionConfig) (KeyProviderInterface, error) {
+	switch cfg.KeyProvider {
+	case pkg.EncryptionKeyProviderLocal:
+		if cfg.KeyFile == "" {
+			return nil, errKeyFileEmpty
+		}
+
+		data, err := ioutil.ReadFile(cfg.KeyFile)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return newLocalKeyProvider(data, cfg.ActiveKeyId)
+	}
+
+	return nil, fmt.Errorf("unknown encryption key provider %s", cfg.KeyProvider)
+}
+
+// newLocalKeyProvider creates the provider of the keyfile content, a JSON object of the key identifiers and
+// the base64 encoded 256-bit keys.
+func newLocalKeyProvider(data []byte, activeKeyId string) (KeyProviderInterface, error) {
+	var encoded map[string]string
+
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, fmt.Errorf("encryption keyfile is invalid: %s", err)
+	}
+
+	p := &LocalKeyProvider{keys: make(map[string]cipher.AEAD), activeKeyId: activeKeyId}
+
+	for keyId, value := range encoded {
+		if keyId == "" || len(keyId) > encryptionKeyIdMaxLength {
+			return nil, fmt.Errorf("encryption key identifier %s is invalid", keyId)
+		}
+
+		key, err := base64.StdEncoding.DecodeString(value)
+
+		if err != nil || len(key) != encryptionKeySize {
+			return nil, fmt.Errorf("encryption key %s must be base64 encoded %d bytes", keyId, encryptionKeySize)
+		}
+
+		if p.keys[keyId], err = newGcm(key); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.keys[activeKeyId] == nil {
+		return nil, fmt.Errorf("active encryption key %s is not found in the keyfile", activeKeyId)
+	}
+
+	return p, nil
+}
+
+func (p *LocalKeyProvider) ActiveKeyId() string {
+	return p.activeKeyId
+}
+
+// WrapKey encrypts the data key, the nonce is prepended to the encrypted key.
+func (p *LocalKeyProvider) WrapKey(keyId string, dataKey []byte) ([]byte, error) {
+	aead, err := p.getKey(keyId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, dataKey, []byte(keyId)), nil
+}
+
+func (p *LocalKeyProvider) UnwrapKey(keyId string, wrapped []byte) ([]byte, error) {
+	aead, err := p.getKey(keyId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(wrapped) < aead.NonceSize() {
+		return nil, errWrappedKeyTooShort
+	}
+
+	return aead.Open(nil, wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():], []byte(keyId))
+}
+
+func (p *LocalKeyProvider) getKey(keyId string) (cipher.AEAD, error) {
+	aead, ok := p.keys[keyId]
+
+	if !ok {
+		return nil, fmt.Errorf("encryption key %s is not found", keyId)
+	}
+
+	return aead, nil
+}
+
+func newGcm(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,115 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/base64"
+	"github.com/paysuper/paysuper-reporter/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+type KeyProviderTestSuite struct {
+	suite.Suite
+	keyFile []byte
+}
+
+func Test_KeyProvider(t *testing.T) {
+	suite.Run(t, new(KeyProviderTestSuite))
+}
+
+func (suite *KeyProviderTestSuite) SetupTest() {
+	suite.keyFile = getTestKeyFile("key1", "key2")
+}
+
+func (suite *KeyProviderTestSuite) TestKeyProvider_WrapKey_Ok() {
+	keys, err := newLocalKeyProvider(suite.keyFile, "key1")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "key1", keys.ActiveKeyId())
+
+	dataKey := bytes.Repeat([]byte{1}, encryptionKeySize)
+	wrapped, err := keys.WrapKey("key1", dataKey)
+	assert.NoError(suite.T(), err)
+	assert.NotContains(suite.T(), string(wrapped), string(dataKey))
+
+	unwrapped, err := keys.UnwrapKey("key1", wrapped)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), dataKey, unwrapped)
+
+	_, err = keys.UnwrapKey("key2", wrapped)
+	assert.Error(suite.T(), err)
+
+	_, err = keys.UnwrapKey("key1", wrapped[:4])
+	assert.Equal(suite.T(), errWrappedKeyTooShort, err)
+
+	_, err = keys.WrapKey("key3", dataKey)
+	assert.EqualError(suite.T(), err, "encryption key key3 is not found")
+}
+
+func (suite *KeyProviderTestSuite) TestKeyProvider_Rotation() {
+	keys, err := newLocalKeyProvider(suite.keyFile, "key1")
+	assert.NoError(suite.T(), err)
+
+	dataKey := bytes.Repeat([]byte{1}, encryptionKeySize)
+	wrapped, err := keys.WrapKey(keys.ActiveKeyId(), dataKey)
+	assert.NoError(suite.T(), err)
+
+	rotated, err := newLocalKeyProvider(suite.keyFile, "key2")
+	assert.NoError(suite.T(), err)
+
+	unwrapped, err := rotated.UnwrapKey("key1", wrapped)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), dataKey, unwrapped)
+}
+
+func (suite *KeyProviderTestSuite) TestKeyProvider_Error_KeyFile() {
+	cases := []struct {
+		data string
+		err  string
+	}{
+		{`[]`, "encryption keyfile is invalid: json: cannot unmarshal array into Go value of type map[string]string"},
+		{`{"key1":"key"}`, "encryption key key1 must be base64 encoded 32 bytes"},
+		{`{"key1":"` + base64.StdEncoding.EncodeToString([]byte("key")) + `"}`, "encryption key key1 must be base64 encoded 32 bytes"},
+		{`{"":"key"}`, "encryption key identifier  is invalid"},
+		{string(getTestKeyFile("key2")), "active encryption key key1 is not found in the keyfile"},
+	}
+
+	for _, c := range cases {
+		_, err := newLocalKeyProvider([]byte(c.data), "key1")
+		assert.EqualError(suite.T(), err, c.err)
+	}
+}
+
+func (suite *KeyProviderTestSuite) TestKeyProvider_newKeyProvider() {
+	file, err := ioutil.TempFile("", "keyfile")
+	assert.NoError(suite.T(), err)
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(suite.keyFile)
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), file.Close())
+
+	keys, err := newKeyProvider(&config.EncryptionConfig{KeyProvider: "local", KeyFile: file.Name(), ActiveKeyId: "key2"})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "key2", keys.ActiveKeyId())
+
+	_, err = newKeyProvider(&config.EncryptionConfig{KeyProvider: "local"})
+	assert.Equal(suite.T(), errKeyFileEmpty, err)
+
+	_, err = newKeyProvider(&config.EncryptionConfig{KeyProvider: "kms"})
+	assert.EqualError(suite.T(), err, "unknown encryption key provider kms")
+}
+
+func getTestKeyFile(keyIds ...string) []byte {
+	keys := make([]string, 0, len(keyIds))
+
+	for i, keyId := range keyIds {
+		key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{byte(i + 1)}, encryptionKeySize))
+		keys = append(keys, `"`+keyId+`":"`+key+`"`)
+	}
+
+	return []byte("{" + strings.Join(keys, ",") + "}")
+}
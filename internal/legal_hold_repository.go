@@ -0,0 +1,112 @@
+package internal
+
+import (
+	"context"
+	errs "errors"
+	"github.com/paysuper/paysuper-reporter/pkg"
+	"github.com/paysuper/paysuper-reporter/pkg/errors"
+	"github.com/paysuper/paysuper-reporter/pkg/proto"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	mongodb "gopkg.in/paysuper/paysuper-database-mongo.v2"
+	"time"
+)
+
+type LegalHoldRepositoryInterface interface {
+	Insert(ctx context.Context, hold *proto.LegalHold) error
+	Release(ctx context.Context, id, merchantId, userId string) (*proto.LegalHold, error)
+	FindActive(ctx context.Context, merchantId string) ([]*proto.LegalHold, error)
+}
+
+type LegalHoldRepository struct {
+	db mongodb.SourceInterface
+}
+
+func newLegalHoldRepository(db mongodb.SourceInterface) LegalHoldRepositoryInterface {
+	return &LegalHoldRepository{db: db}
+}
+
+func (r *LegalHoldRepository) Insert(ctx context.Context, hold *proto.LegalHold) error {
+	if _, err := r.db.Collection(pkg.CollectionLegalHold).InsertOne(ctx, hold); err != nil {
+		zap.L().Error(
+			errors.ErrorDatabaseQueryFailed.Message,
+			zap.Error(err),
+			zap.String("collection", pkg.CollectionLegalHold),
+			zap.Any("document", hold),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// Release releases the active hold of the merchant and returns the released hold. Unknown or already released
+// hold is not found.
+func (r *LegalHoldRepository) Release(ctx context.Context, id, merchantId, userId string) (*proto.LegalHold, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+
+	if err != nil {
+		return nil, errs.New(errors.ErrorMongoDbOidIncorrect.Message)
+	}
+
+	query := bson.M{"_id": oid, "merchant_id": merchantId, "released_at": nil}
+	update := bson.M{"$set": bson.M{"released_at": time.Now(), "released_by": userId}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	hold := &proto.LegalHold{}
+	err = r.db.Collection(pkg.CollectionLegalHold).FindOneAndUpdate(ctx, query, update, opts).Decode(hold)
+
+	if err != nil {
+		if err != mongo.ErrNoDocuments {
+			zap.L().Error(
+				errors.ErrorDatabaseQueryFailed.Message,
+				zap.Error(err),
+				zap.String("collection", pkg.CollectionLegalHold),
+				zap.String("id", id),
+			)
+		}
+
+		return nil, err
+	}
+
+	return hold, nil
+}
+
+// FindActive returns the holds of the merchant which have not been released yet, the holds of all merchants
+// are returned for the empty merchant identifier.
+func (r *LegalHoldRepository) FindActive(ctx context.Context, merchantId string) ([]*proto.LegalHold, error) {
+	query := bson.M{"released_at": nil}
+
+	if merchantId != "" {
+		query["merchant_id"] = merchantId
+	}
+
+	cursor, err := r.db.Collection(pkg.CollectionLegalHold).Find(ctx, query)
+
+	if err != nil {
+		zap.L().Error(
+			errors.ErrorDatabaseQueryFailed.Message,
+			zap.Error(err),
+			zap.String("collection", pkg.CollectionLegalHold),
+			zap.Any("query", query),
+		)
+		return nil, err
+	}
+
+	var holds []*proto.LegalHold
+
+	if err = cursor.All(ctx, &holds); err != nil {
+		zap.L().Error(
+			errors.ErrorDatabaseQueryFailed.Message,
+			zap.Error(err),
+			zap.String("collection", pkg.CollectionLegalHold),
+			zap.Any("query", query),
+		)
+		return nil, err
+	}
+
+	return holds, nil
+}
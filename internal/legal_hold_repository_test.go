@@ -0,0 +1,103 @@
+package internal
+
+import (
+	"context"
+	"github.com/paysuper/paysuper-reporter/pkg/errors"
+	"github.com/paysuper/paysuper-reporter/pkg/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	mongodb "gopkg.in/paysuper/paysuper-database-mongo.v2"
+	"testing"
+	"time"
+)
+
+type LegalHoldRepositoryTestSuite struct {
+	suite.Suite
+	db         mongodb.SourceInterface
+	repository LegalHoldRepositoryInterface
+}
+
+func Test_LegalHoldRepository(t *testing.T) {
+	suite.Run(t, new(LegalHoldRepositoryTestSuite))
+}
+
+func (suite *LegalHoldRepositoryTestSuite) SetupTest() {
+	db, err := mongodb.NewDatabase()
+	assert.NoError(suite.T(), err, "Database connection failed")
+
+	suite.db = db
+	suite.repository = newLegalHoldRepository(db)
+}
+
+func (suite *LegalHoldRepositoryTestSuite) TearDownTest() {
+	if err := suite.db.Drop(); err != nil {
+		suite.FailNow("Database deletion failed", "%v", err)
+	}
+
+	if err := suite.db.Close(); err != nil {
+		suite.FailNow("Database close failed", "%v", err)
+	}
+}
+
+func (suite *LegalHoldRepositoryTestSuite) TestLegalHoldRepository_FindActive_Ok() {
+	hold1 := suite.getHold("ffffffffffffffffffffffff", "")
+	hold2 := suite.getHold("ffffffffffffffffffffffff", primitive.NewObjectID().Hex())
+	hold3 := suite.getHold("fffffffffffffffffffffff1", "")
+	assert.NoError(suite.T(), suite.repository.Insert(context.TODO(), hold1))
+	assert.NoError(suite.T(), suite.repository.Insert(context.TODO(), hold2))
+	assert.NoError(suite.T(), suite.repository.Insert(context.TODO(), hold3))
+
+	holds, err := suite.repository.FindActive(context.TODO(), "ffffffffffffffffffffffff")
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), holds, 2)
+
+	holds, err = suite.repository.FindActive(context.TODO(), "")
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), holds, 3)
+
+	_, err = suite.repository.Release(context.TODO(), hold1.Id.Hex(), hold1.MerchantId, "fffffffffffffffffffffff2")
+	assert.NoError(suite.T(), err)
+
+	holds, err = suite.repository.FindActive(context.TODO(), "ffffffffffffffffffffffff")
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), holds, 1)
+	assert.Equal(suite.T(), hold2.Id, holds[0].Id)
+}
+
+func (suite *LegalHoldRepositoryTestSuite) TestLegalHoldRepository_Release_Ok() {
+	hold := suite.getHold("ffffffffffffffffffffffff", "")
+	assert.NoError(suite.T(), suite.repository.Insert(context.TODO(), hold))
+
+	released, err := suite.repository.Release(context.TODO(), hold.Id.Hex(), hold.MerchantId, "fffffffffffffffffffffff2")
+	assert.NoError(suite.T(), err)
+	assert.NotNil(suite.T(), released.ReleasedAt)
+	assert.Equal(suite.T(), "fffffffffffffffffffffff2", released.ReleasedBy)
+	assert.Equal(suite.T(), hold.Reason, released.Reason)
+
+	_, err = suite.repository.Release(context.TODO(), hold.Id.Hex(), hold.MerchantId, "fffffffffffffffffffffff2")
+	assert.Equal(suite.T(), mongo.ErrNoDocuments, err)
+}
+
+func (suite *LegalHoldRepositoryTestSuite) TestLegalHoldRepository_Release_Error() {
+	hold := suite.getHold("ffffffffffffffffffffffff", "")
+	assert.NoError(suite.T(), suite.repository.Insert(context.TODO(), hold))
+
+	_, err := suite.repository.Release(context.TODO(), hold.Id.Hex(), "fffffffffffffffffffffff1", "")
+	assert.Equal(suite.T(), mongo.ErrNoDocuments, err)
+
+	_, err = suite.repository.Release(context.TODO(), "id", hold.MerchantId, "")
+	assert.EqualError(suite.T(), err, errors.ErrorMongoDbOidIncorrect.Message)
+}
+
+func (suite *LegalHoldRepositoryTestSuite) getHold(merchantId, fileId string) *proto.LegalHold {
+	return &proto.LegalHold{
+		Id:         primitive.NewObjectID(),
+		MerchantId: merchantId,
+		FileId:     fileId,
+		Reason:     "litigation",
+		UserId:     "fffffffffffffffffffffff1",
+		CreatedAt:  time.Now(),
+	}
+}
@@ -0,0 +1,96 @@
+package internal
+
+import (
+	"context"
+	"github.com/paysuper/paysuper-reporter/pkg/proto"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// LocalStorage stores the report files in the directory of the bucket in the local filesystem, so the reports
+// are generated without the cloud storage in the development and CI environments. Files never expire, and the
+// presigned URLs point to the base URL serving the storage directory or to the files themselves.
+type LocalStorage struct {
+	dir     string
+	baseUrl string
+	bucket  string
+}
+
+func newLocalStorage(dir, baseUrl, bucket string) (StorageInterface, error) {
+	dir, err := filepath.Abs(filepath.Join(dir, bucket))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	return &LocalStorage{dir: dir, baseUrl: strings.TrimRight(baseUrl, "/"), bucket: bucket}, nil
+}
+
+// Upload writes the file to the temporary file first, so the partially written file is never read.
+// The upload options are ignored, because local files never expire and have no ACL.
+func (s *LocalStorage) Upload(_ context.Context, fileName string, content []byte, _ *proto.StorageUploadOptions) error {
+	path := s.getPath(fileName)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), ".upload-")
+
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(content); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *LocalStorage) Get(_ context.Context, fileName string) ([]byte, error) {
+	return ioutil.ReadFile(s.getPath(fileName))
+}
+
+func (s *LocalStorage) Delete(_ context.Context, fileName string) error {
+	err := os.Remove(s.getPath(fileName))
+
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *LocalStorage) Presign(fileName, _ string, _ time.Duration) (string, error) {
+	if s.baseUrl == "" {
+		u := url.URL{Scheme: "file", Path: s.getPath(fileName)}
+		return u.String(), nil
+	}
+
+	return s.baseUrl + "/" + url.PathEscape(s.bucket) + "/" + (&url.URL{Path: fileName}).EscapedPath(), nil
+}
+
+func (s *LocalStorage) Bucket() string {
+	return s.bucket
+}
+
+// getPath returns the path of the file inside the bucket directory, the file name can not point outside of it.
+func (s *LocalStorage) getPath(fileName string) string {
+	return filepath.Join(s.dir, filepath.Clean(string(filepath.Separator)+fileName))
+}
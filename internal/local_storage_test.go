@@ -0,0 +1,86 @@
+package internal
+
+import (
+	"context"
+	"github.com/paysuper/paysuper-reporter/pkg/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type LocalStorageTestSuite struct {
+	suite.Suite
+	dir     string
+	storage StorageInterface
+}
+
+func Test_LocalStorage(t *testing.T) {
+	suite.Run(t, new(LocalStorageTestSuite))
+}
+
+func (suite *LocalStorageTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "local_storage")
+	assert.NoError(suite.T(), err)
+
+	suite.dir = dir
+	suite.storage, err = newLocalStorage(dir, "", "reports")
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *LocalStorageTestSuite) TearDownTest() {
+	_ = os.RemoveAll(suite.dir)
+}
+
+func (suite *LocalStorageTestSuite) TestLocalStorage_Upload_Ok() {
+	err := suite.storage.Upload(context.TODO(), "report.pdf", []byte("content"), &proto.StorageUploadOptions{Expires: time.Now().Add(time.Hour)})
+	assert.NoError(suite.T(), err)
+
+	content, err := suite.storage.Get(context.TODO(), "report.pdf")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []byte("content"), content)
+
+	files, err := ioutil.ReadDir(filepath.Join(suite.dir, "reports"))
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), files, 1)
+}
+
+func (suite *LocalStorageTestSuite) TestLocalStorage_Upload_PathTraversal() {
+	err := suite.storage.Upload(context.TODO(), "../../report.pdf", []byte("content"), nil)
+	assert.NoError(suite.T(), err)
+
+	_, err = os.Stat(filepath.Join(suite.dir, "reports", "report.pdf"))
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *LocalStorageTestSuite) TestLocalStorage_Get_Error_NotFound() {
+	_, err := suite.storage.Get(context.TODO(), "report.pdf")
+	assert.True(suite.T(), os.IsNotExist(err))
+}
+
+func (suite *LocalStorageTestSuite) TestLocalStorage_Delete_Ok() {
+	assert.NoError(suite.T(), suite.storage.Upload(context.TODO(), "report.pdf", []byte("content"), nil))
+	assert.NoError(suite.T(), suite.storage.Delete(context.TODO(), "report.pdf"))
+	assert.NoError(suite.T(), suite.storage.Delete(context.TODO(), "report.pdf"))
+
+	_, err := suite.storage.Get(context.TODO(), "report.pdf")
+	assert.True(suite.T(), os.IsNotExist(err))
+}
+
+func (suite *LocalStorageTestSuite) TestLocalStorage_Presign_File() {
+	url, err := suite.storage.Presign("report.pdf", "application/pdf", time.Hour)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "file://"+filepath.Join(suite.dir, "reports", "report.pdf"), url)
+}
+
+func (suite *LocalStorageTestSuite) TestLocalStorage_Presign_BaseUrl() {
+	storage, err := newLocalStorage(suite.dir, "http://127.0.0.1:8080/files/", "agreements")
+	assert.NoError(suite.T(), err)
+
+	url, err := storage.Presign("License Agreement_#1.pdf", "application/pdf", time.Hour)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "http://127.0.0.1:8080/files/agreements/License%20Agreement_%231.pdf", url)
+}
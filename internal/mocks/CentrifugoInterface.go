@@ -33,13 +33,13 @@ func (_m *CentrifugoInterface) Info(ctx context.Context) (gocent.InfoResult, err
 	return r0, r1
 }
 
-// Publish provides a mock function with given fields: _a0, _a1
-func (_m *CentrifugoInterface) Publish(_a0 string, _a1 interface{}) error {
-	ret := _m.Called(_a0, _a1)
+// Publish provides a mock function with given fields: _a0, _a1, _a2
+func (_m *CentrifugoInterface) Publish(_a0 context.Context, _a1 string, _a2 interface{}) error {
+	ret := _m.Called(_a0, _a1, _a2)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, interface{}) error); ok {
-		r0 = rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, string, interface{}) error); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
 		r0 = ret.Error(0)
 	}
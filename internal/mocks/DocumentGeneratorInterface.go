@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	proto "github.com/paysuper/paysuper-reporter/pkg/proto"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -12,13 +14,13 @@ type DocumentGeneratorInterface struct {
 	mock.Mock
 }
 
-// Render provides a mock function with given fields: payload
-func (_m *DocumentGeneratorInterface) Render(payload *proto.GeneratorPayload) ([]byte, error) {
-	ret := _m.Called(payload)
+// Render provides a mock function with given fields: ctx, payload
+func (_m *DocumentGeneratorInterface) Render(ctx context.Context, payload *proto.GeneratorPayload) ([]byte, error) {
+	ret := _m.Called(ctx, payload)
 
 	var r0 []byte
-	if rf, ok := ret.Get(0).(func(*proto.GeneratorPayload) []byte); ok {
-		r0 = rf(payload)
+	if rf, ok := ret.Get(0).(func(context.Context, *proto.GeneratorPayload) []byte); ok {
+		r0 = rf(ctx, payload)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]byte)
@@ -26,8 +28,8 @@ func (_m *DocumentGeneratorInterface) Render(payload *proto.GeneratorPayload) ([
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*proto.GeneratorPayload) error); ok {
-		r1 = rf(payload)
+	if rf, ok := ret.Get(1).(func(context.Context, *proto.GeneratorPayload) error); ok {
+		r1 = rf(ctx, payload)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,67 @@
+package internal
+
+import (
+	"context"
+	"runtime"
+	"time"
+)
+
+const renderLimiterMemoryCheckInterval = 500 * time.Millisecond
+
+// RenderLimiter limits the count of the rendered report files held in memory at the same time. When the memory
+// limit is set, the new file is not rendered until the heap in use falls below the limit.
+type RenderLimiter struct {
+	slots       chan struct{}
+	memoryLimit uint64
+	heapInUse   func() uint64
+	interval    time.Duration
+}
+
+func newRenderLimiter(maxFiles int, memoryLimit uint64) *RenderLimiter {
+	if maxFiles < 1 {
+		maxFiles = 1
+	}
+
+	return &RenderLimiter{
+		slots:       make(chan struct{}, maxFiles),
+		memoryLimit: memoryLimit,
+		heapInUse:   getHeapInUse,
+		interval:    renderLimiterMemoryCheckInterval,
+	}
+}
+
+// Acquire waits for the free slot and the free memory to render the file. The slot must be released
+// when the rendered file is no longer needed.
+func (l *RenderLimiter) Acquire(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	for l.memoryLimit > 0 && l.heapInUse() >= l.memoryLimit {
+		select {
+		case <-time.After(l.interval):
+		case <-ctx.Done():
+			l.Release()
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+func (l *RenderLimiter) Release() {
+	<-l.slots
+}
+
+func getHeapInUse() uint64 {
+	stats := runtime.MemStats{}
+	runtime.ReadMemStats(&stats)
+
+	return stats.HeapInuse
+}
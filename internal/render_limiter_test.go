@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type RenderLimiterTestSuite struct {
+	suite.Suite
+}
+
+func Test_RenderLimiter(t *testing.T) {
+	suite.Run(t, new(RenderLimiterTestSuite))
+}
+
+func (suite *RenderLimiterTestSuite) TestRenderLimiter_Acquire_Ok() {
+	limiter := newRenderLimiter(2, 0)
+	assert.NoError(suite.T(), limiter.Acquire(context.Background()))
+	assert.NoError(suite.T(), limiter.Acquire(context.Background()))
+
+	limiter.Release()
+	assert.NoError(suite.T(), limiter.Acquire(context.Background()))
+}
+
+func (suite *RenderLimiterTestSuite) TestRenderLimiter_Acquire_Error_NoSlots() {
+	limiter := newRenderLimiter(1, 0)
+	assert.NoError(suite.T(), limiter.Acquire(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.Equal(suite.T(), context.DeadlineExceeded, limiter.Acquire(ctx))
+}
+
+func (suite *RenderLimiterTestSuite) TestRenderLimiter_Acquire_Error_ContextDone() {
+	limiter := newRenderLimiter(1, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.Equal(suite.T(), context.Canceled, limiter.Acquire(ctx))
+	assert.NoError(suite.T(), limiter.Acquire(context.Background()))
+}
+
+func (suite *RenderLimiterTestSuite) TestRenderLimiter_Acquire_Ok_MemoryReleased() {
+	heap := uint64(200)
+	limiter := newRenderLimiter(1, 100)
+	limiter.interval = time.Millisecond
+	limiter.heapInUse = func() uint64 {
+		return atomic.AddUint64(&heap, ^uint64(49))
+	}
+
+	assert.NoError(suite.T(), limiter.Acquire(context.Background()))
+	assert.EqualValues(suite.T(), 50, atomic.LoadUint64(&heap))
+}
+
+func (suite *RenderLimiterTestSuite) TestRenderLimiter_Acquire_Error_MemoryLimit() {
+	limiter := newRenderLimiter(1, 100)
+	limiter.interval = time.Millisecond
+	limiter.heapInUse = func() uint64 {
+		return 100
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.Equal(suite.T(), context.DeadlineExceeded, limiter.Acquire(ctx))
+
+	limiter.heapInUse = func() uint64 {
+		return 0
+	}
+	assert.NoError(suite.T(), limiter.Acquire(context.Background()))
+}
@@ -0,0 +1,120 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"github.com/paysuper/paysuper-reporter/pkg"
+	"github.com/paysuper/paysuper-reporter/pkg/proto"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
+)
+
+// RendererSupportInterface is implemented by the renderers that can render only some of the report files.
+// The registry uses the default renderer for the files that are not supported by the routed one.
+type RendererSupportInterface interface {
+	Supports(reportType, fileType string) bool
+}
+
+// RendererRegistry routes the report files to the renderers by the report type and the file type.
+// The route key is "<report type>.<file type>", where either part may be replaced by the "*" wildcard.
+type RendererRegistry struct {
+	renderers   map[string]DocumentGeneratorInterface
+	routes      map[string]string
+	defaultName string
+}
+
+// NativeRenderer renders the report files in the formats supported by the service without external services.
+type NativeRenderer struct {
+	renderers map[string]DocumentGeneratorInterface
+}
+
+func newRendererRegistry(defaultName string, routes map[string]string) *RendererRegistry {
+	if routes == nil {
+		routes = make(map[string]string)
+	}
+
+	return &RendererRegistry{
+		renderers:   make(map[string]DocumentGeneratorInterface),
+		routes:      routes,
+		defaultName: defaultName,
+	}
+}
+
+func (r *RendererRegistry) Register(name string, renderer DocumentGeneratorInterface) {
+	r.renderers[name] = renderer
+}
+
+// Validate checks that the default renderer and the renderers of all routes are registered.
+func (r *RendererRegistry) Validate() error {
+	if _, ok := r.renderers[r.defaultName]; !ok {
+		return fmt.Errorf("default renderer \"%s\" is not registered", r.defaultName)
+	}
+
+	for key, name := range r.routes {
+		if _, ok := r.renderers[name]; !ok {
+			return fmt.Errorf("renderer \"%s\" of the route \"%s\" is not registered", name, key)
+		}
+	}
+
+	return nil
+}
+
+// Get returns the renderer of the most specific route: the exact report and file type, then the report type
+// with any file type, then any report type with the file type. The default renderer is used when no route
+// is found or the routed renderer does not support the report file.
+func (r *RendererRegistry) Get(reportType, fileType string) DocumentGeneratorInterface {
+	keys := []string{
+		fmt.Sprintf(pkg.RendererRouteMask, reportType, fileType),
+		fmt.Sprintf(pkg.RendererRouteMask, reportType, pkg.RendererRouteWildcard),
+		fmt.Sprintf(pkg.RendererRouteMask, pkg.RendererRouteWildcard, fileType),
+	}
+
+	for _, key := range keys {
+		name, ok := r.routes[key]
+
+		if !ok {
+			continue
+		}
+
+		renderer, ok := r.renderers[name]
+
+		if !ok {
+			continue
+		}
+
+		if support, ok := renderer.(RendererSupportInterface); ok && !support.Supports(reportType, fileType) {
+			continue
+		}
+
+		return renderer
+	}
+
+	return r.renderers[r.defaultName]
+}
+
+func newNativeRenderer(csvRenderer, xlsxRenderer DocumentGeneratorInterface) DocumentGeneratorInterface {
+	return &NativeRenderer{
+		renderers: map[string]DocumentGeneratorInterface{
+			reporterpb.OutputExtensionCsv:  csvRenderer,
+			reporterpb.OutputExtensionXlsx: xlsxRenderer,
+		},
+	}
+}
+
+func (r *NativeRenderer) Supports(reportType, fileType string) bool {
+	if _, ok := reportFileSections[reportType]; !ok {
+		return false
+	}
+
+	_, ok := r.renderers[fileType]
+	return ok
+}
+
+func (r *NativeRenderer) Render(ctx context.Context, payload *proto.GeneratorPayload) ([]byte, error) {
+	renderer, ok := r.renderers[payload.FileType]
+
+	if !ok {
+		return nil, fmt.Errorf("native renderer does not support \"%s\" file type", payload.FileType)
+	}
+
+	return renderer.Render(ctx, payload)
+}
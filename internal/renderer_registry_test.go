@@ -0,0 +1,103 @@
+package internal
+
+import (
+	"context"
+	"github.com/paysuper/paysuper-reporter/internal/config"
+	"github.com/paysuper/paysuper-reporter/internal/mocks"
+	"github.com/paysuper/paysuper-reporter/pkg"
+	"github.com/paysuper/paysuper-reporter/pkg/proto"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type RendererRegistryTestSuite struct {
+	suite.Suite
+	jsreport *mocks.DocumentGeneratorInterface
+	htmlPdf  *mocks.DocumentGeneratorInterface
+	native   DocumentGeneratorInterface
+}
+
+func Test_RendererRegistry(t *testing.T) {
+	suite.Run(t, new(RendererRegistryTestSuite))
+}
+
+func (suite *RendererRegistryTestSuite) SetupTest() {
+	csvRenderer, err := newCsvRenderer(&config.CsvConfig{Delimiter: ","})
+	assert.NoError(suite.T(), err)
+
+	suite.jsreport = &mocks.DocumentGeneratorInterface{}
+	suite.htmlPdf = &mocks.DocumentGeneratorInterface{}
+	suite.native = newNativeRenderer(csvRenderer, newXlsxRenderer())
+}
+
+func (suite *RendererRegistryTestSuite) TestRendererRegistry_Get_Routes() {
+	registry := suite.getRegistry(map[string]string{
+		"*.csv":       pkg.RendererNative,
+		"*.xlsx":      pkg.RendererNative,
+		"royalty.*":   pkg.RendererHtmlPdf,
+		"royalty.csv": pkg.RendererJsReport,
+	})
+
+	assert.Equal(suite.T(), suite.native, registry.Get(reporterpb.ReportTypeVat, reporterpb.OutputExtensionCsv))
+	assert.Equal(suite.T(), suite.native, registry.Get(reporterpb.ReportTypeVat, reporterpb.OutputExtensionXlsx))
+	assert.Equal(suite.T(), suite.jsreport, registry.Get(reporterpb.ReportTypeVat, reporterpb.OutputExtensionPdf))
+	assert.Equal(suite.T(), suite.jsreport, registry.Get(reporterpb.ReportTypeRoyalty, reporterpb.OutputExtensionCsv))
+	assert.Equal(suite.T(), suite.htmlPdf, registry.Get(reporterpb.ReportTypeRoyalty, reporterpb.OutputExtensionPdf))
+	assert.Equal(suite.T(), suite.htmlPdf, registry.Get(reporterpb.ReportTypeRoyalty, reporterpb.OutputExtensionXlsx))
+}
+
+func (suite *RendererRegistryTestSuite) TestRendererRegistry_Get_NotSupported() {
+	registry := suite.getRegistry(map[string]string{"*.csv": pkg.RendererNative})
+
+	assert.Equal(suite.T(), suite.jsreport, registry.Get(reporterpb.ReportTypeAgreement, reporterpb.OutputExtensionCsv))
+	assert.Equal(suite.T(), suite.jsreport, registry.Get(reporterpb.ReportTypePayout, reporterpb.OutputExtensionCsv))
+}
+
+func (suite *RendererRegistryTestSuite) TestRendererRegistry_Validate_Ok() {
+	registry := suite.getRegistry(map[string]string{"*.csv": pkg.RendererNative})
+	assert.NoError(suite.T(), registry.Validate())
+}
+
+func (suite *RendererRegistryTestSuite) TestRendererRegistry_Validate_Error_UnknownRouteRenderer() {
+	registry := suite.getRegistry(map[string]string{"*.csv": "unknown"})
+	assert.EqualError(suite.T(), registry.Validate(), "renderer \"unknown\" of the route \"*.csv\" is not registered")
+}
+
+func (suite *RendererRegistryTestSuite) TestRendererRegistry_Validate_Error_UnknownDefault() {
+	registry := newRendererRegistry("unknown", nil)
+	assert.Error(suite.T(), registry.Validate())
+}
+
+func (suite *RendererRegistryTestSuite) TestNativeRenderer_Render_ByFileType() {
+	payload := &proto.GeneratorPayload{
+		ReportType: reporterpb.ReportTypeTransactions,
+		FileType:   reporterpb.OutputExtensionCsv,
+		Data:       map[string]interface{}{},
+	}
+
+	b, err := suite.native.Render(context.Background(), payload)
+	assert.NoError(suite.T(), err)
+	assert.Contains(suite.T(), string(b), "Project,Product")
+
+	payload.FileType = reporterpb.OutputExtensionXlsx
+	b, err = suite.native.Render(context.Background(), payload)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "PK", string(b[:2]))
+
+	payload.FileType = reporterpb.OutputExtensionPdf
+	_, err = suite.native.Render(context.Background(), payload)
+	assert.Error(suite.T(), err)
+	suite.jsreport.AssertNotCalled(suite.T(), "Render", mock.Anything, mock.Anything)
+}
+
+func (suite *RendererRegistryTestSuite) getRegistry(routes map[string]string) *RendererRegistry {
+	registry := newRendererRegistry(pkg.RendererJsReport, routes)
+	registry.Register(pkg.RendererJsReport, suite.jsreport)
+	registry.Register(pkg.RendererNative, suite.native)
+	registry.Register(pkg.RendererHtmlPdf, suite.htmlPdf)
+
+	return registry
+}
@@ -2,15 +2,24 @@ package internal
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	errs "errors"
-	"github.com/paysuper/paysuper-proto/go/reporterpb"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/paysuper/paysuper-reporter/internal/builder"
 	"github.com/paysuper/paysuper-reporter/pkg"
 	"github.com/paysuper/paysuper-reporter/pkg/errors"
+	"github.com/paysuper/paysuper-reporter/pkg/proto"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
 	"github.com/streadway/amqp"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.uber.org/zap"
+	rabbitmq "gopkg.in/ProtocolONE/rabbitmq.v1/pkg"
 	"sort"
+	"strings"
+	"time"
 )
 
 var (
@@ -29,6 +38,20 @@ var (
 		reporterpb.OutputExtensionPdf:  pkg.OutputContentTypePdf,
 	}
 
+	errIdempotencyKeyConflict = errs.New(errors.ErrorIdempotencyKeyConflict.Message)
+
+	// Single documents requested by the user interactively are generated in the separate lane, so they never
+	// wait behind the bulk exports
+	reportTypePriorities = map[string]string{
+		reporterpb.ReportTypeVat:                 pkg.ReportFilePriorityBulk,
+		reporterpb.ReportTypeVatTransactions:     pkg.ReportFilePriorityBulk,
+		reporterpb.ReportTypeRoyalty:             pkg.ReportFilePriorityInteractive,
+		reporterpb.ReportTypeRoyaltyTransactions: pkg.ReportFilePriorityBulk,
+		reporterpb.ReportTypeTransactions:        pkg.ReportFilePriorityBulk,
+		reporterpb.ReportTypePayout:              pkg.ReportFilePriorityInteractive,
+		reporterpb.ReportTypeAgreement:           pkg.ReportFilePriorityInteractive,
+	}
+
 	reportFileRecipes = map[string]string{
 		reporterpb.OutputExtensionXlsx: pkg.RecipeXlsx,
 		reporterpb.OutputExtensionCsv:  pkg.RecipeCsv,
@@ -44,7 +67,7 @@ type ReportFileTemplate struct {
 	Group      string
 }
 
-func (app *Application) CreateFile(_ context.Context, file *reporterpb.ReportFile, res *reporterpb.CreateFileResponse) error {
+func (app *Application) CreateFile(ctx context.Context, file *reporterpb.ReportFile, res *reporterpb.CreateFileResponse) error {
 	var err error
 
 	if _, ok := reportFileContentTypes[file.FileType]; !ok {
@@ -65,6 +88,18 @@ func (app *Application) CreateFile(_ context.Context, file *reporterpb.ReportFil
 		return nil
 	}
 
+	if file.Priority != "" &&
+		file.Priority != pkg.ReportFilePriorityInteractive &&
+		file.Priority != pkg.ReportFilePriorityBulk {
+		zap.L().Error(errors.ErrorReportFilePriorityInvalid.Message, zap.Any("file", file))
+		res.Status = pkg.ResponseStatusBadData
+		res.Message = errors.ErrorReportFilePriorityInvalid
+
+		return nil
+	}
+
+	file.Priority = getReportFilePriority(file)
+
 	if file.Template, err = app.getTemplate(file); err != nil {
 		res.Status = pkg.ResponseStatusBadData
 		res.Message = errors.ErrorTemplateNotFound
@@ -73,6 +108,7 @@ func (app *Application) CreateFile(_ context.Context, file *reporterpb.ReportFil
 	}
 
 	file.Id = primitive.NewObjectID().Hex()
+	file.CreatedAt = ptypes.TimestampNow()
 
 	h := builder.NewBuilder(
 		app.service,
@@ -97,10 +133,64 @@ func (app *Application) CreateFile(_ context.Context, file *reporterpb.ReportFil
 		return nil
 	}
 
+	duplicate, err := app.findDuplicate(ctx, file)
+
+	if err == errIdempotencyKeyConflict {
+		zap.L().Warn(err.Error(), zap.String("file_id", duplicate.Id.Hex()), zap.Any("file", file))
+		res.Status = pkg.ResponseStatusConflict
+		res.Message = errors.ErrorIdempotencyKeyConflict
+
+		return nil
+	}
+
+	if err != nil {
+		res.Status = pkg.ResponseStatusSystemError
+		res.Message = errors.ErrorDatabaseQueryFailed
+
+		return nil
+	}
+
+	if duplicate != nil {
+		zap.L().Info("Report file deduplicated", zap.String("file_id", duplicate.Id.Hex()), zap.Any("file", file))
+		res.Status = pkg.ResponseStatusOk
+		res.FileId = duplicate.Id.Hex()
+
+		return nil
+	}
+
+	limit, err := newReportLimiter(&app.cfg.Limit, app.reportFileRepository).Check(ctx, file)
+
+	if err != nil {
+		res.Status = pkg.ResponseStatusSystemError
+		res.Message = errors.ErrorDatabaseQueryFailed
+
+		return nil
+	}
+
+	if limit != "" {
+		zap.L().Warn(errors.ErrorReportFileLimitExceeded.Message, zap.String("limit", limit), zap.Any("file", file))
+		res.Status = pkg.ResponseStatusTooManyRequests
+		res.Message = &reporterpb.ResponseErrorMessage{
+			Code:    errors.ErrorReportFileLimitExceeded.Code,
+			Message: errors.ErrorReportFileLimitExceeded.Message,
+			Details: limit,
+		}
+
+		return nil
+	}
+
+	if err = app.reportFileRepository.Insert(ctx, file); err != nil {
+		res.Status = pkg.ResponseStatusSystemError
+		res.Message = errors.ErrorDatabaseQueryFailed
+
+		return nil
+	}
+
 	amqpHeaders := amqp.Table{
 		"x-retry-count": int32(0),
 	}
-	err = app.generateReportBroker.Publish(pkg.BrokerGenerateReportTopicName, file, amqpHeaders)
+	topic, broker := app.getGenerateLane(file)
+	err = broker.Publish(topic, file, amqpHeaders)
 
 	if err != nil {
 		zap.L().Error(
@@ -108,6 +198,7 @@ func (app *Application) CreateFile(_ context.Context, file *reporterpb.ReportFil
 			zap.Error(err),
 			zap.Any("file", file),
 		)
+		app.setJobStatus(file.Id, pkg.ReportFileStatusFailed, errors.ErrorMessageBrokerFailed, err)
 		res.Status = pkg.ResponseStatusSystemError
 		res.Message = errors.ErrorMessageBrokerFailed
 		return nil
@@ -119,6 +210,283 @@ func (app *Application) CreateFile(_ context.Context, file *reporterpb.ReportFil
 	return nil
 }
 
+func (app *Application) GetFile(ctx context.Context, req *reporterpb.GetFileRequest, res *reporterpb.GetFileResponse) error {
+	job, status, msg := app.getReportFileJob(ctx, req.FileId, req.MerchantId)
+
+	if msg != nil {
+		res.Status = status
+		res.Message = msg
+
+		return nil
+	}
+
+	res.Status = pkg.ResponseStatusOk
+	res.Item = newFileInfo(job)
+
+	return nil
+}
+
+func (app *Application) ListFiles(ctx context.Context, req *reporterpb.ListFilesRequest, res *reporterpb.ListFilesResponse) error {
+	if req.MerchantId == "" {
+		res.Status = pkg.ResponseStatusBadData
+		res.Message = errors.ErrorParamMerchantIdNotFound
+
+		return nil
+	}
+
+	limit := req.Limit
+
+	if limit <= 0 {
+		limit = pkg.ListFilesDefaultLimit
+	}
+
+	if limit > pkg.ListFilesMaxLimit {
+		limit = pkg.ListFilesMaxLimit
+	}
+
+	offset := req.Offset
+
+	if offset < 0 {
+		offset = 0
+	}
+
+	count, err := app.reportFileRepository.FindCount(ctx, req.MerchantId, req.UserId, req.ReportType)
+
+	if err != nil {
+		res.Status = pkg.ResponseStatusSystemError
+		res.Message = errors.ErrorDatabaseQueryFailed
+
+		return nil
+	}
+
+	res.Status = pkg.ResponseStatusOk
+	res.Item = &reporterpb.ListFilesResponseItem{Count: count, Items: []*reporterpb.FileInfo{}}
+
+	if count <= 0 || offset >= count {
+		return nil
+	}
+
+	jobs, err := app.reportFileRepository.Find(ctx, req.MerchantId, req.UserId, req.ReportType, offset, limit)
+
+	if err != nil {
+		res.Status = pkg.ResponseStatusSystemError
+		res.Message = errors.ErrorDatabaseQueryFailed
+		res.Item = nil
+
+		return nil
+	}
+
+	for _, job := range jobs {
+		res.Item.Items = append(res.Item.Items, newFileInfo(job))
+	}
+
+	return nil
+}
+
+func (app *Application) GetFileDownloadUrl(
+	ctx context.Context,
+	req *reporterpb.GetFileDownloadUrlRequest,
+	res *reporterpb.GetFileDownloadUrlResponse,
+) error {
+	job, status, msg := app.getReportFileJob(ctx, req.FileId, req.MerchantId)
+
+	if msg != nil {
+		res.Status = status
+		res.Message = msg
+
+		return nil
+	}
+
+	if job.Status == pkg.ReportFileStatusCancelled || job.Status == pkg.ReportFileStatusErased {
+		res.Status = pkg.ResponseStatusNotFound
+		res.Message = errors.ErrorReportFileNotFound
+
+		return nil
+	}
+
+	if job.FileName == "" {
+		res.Status = pkg.ResponseStatusBadData
+		res.Message = errors.ErrorReportFileNotReady
+
+		return nil
+	}
+
+	if job.Status == pkg.ReportFileStatusExpired || job.ExpiresAt != nil && !job.ExpiresAt.After(time.Now()) {
+		res.Status = pkg.ResponseStatusNotFound
+		res.Message = errors.ErrorReportFileExpired
+
+		return nil
+	}
+
+	item, err := app.getDownloadUrl(job, req.Ttl)
+
+	if err != nil {
+		zap.L().Error(errors.ErrorDownloadUrlFailed.Message, zap.Error(err), zap.String("file_id", req.FileId))
+		res.Status = pkg.ResponseStatusSystemError
+		res.Message = errors.ErrorDownloadUrlFailed
+
+		return nil
+	}
+
+	res.Status = pkg.ResponseStatusOk
+	res.Item = item
+
+	return nil
+}
+
+func (app *Application) CancelFile(ctx context.Context, req *reporterpb.CancelFileRequest, res *reporterpb.CancelFileResponse) error {
+	job, status, msg := app.getReportFileJob(ctx, req.FileId, req.MerchantId)
+
+	if msg != nil {
+		res.Status = status
+		res.Message = msg
+
+		return nil
+	}
+
+	if job.Status == pkg.ReportFileStatusCancelled {
+		res.Status = pkg.ResponseStatusOk
+		return nil
+	}
+
+	if job.Status == pkg.ReportFileStatusErased {
+		res.Status = pkg.ResponseStatusNotFound
+		res.Message = errors.ErrorReportFileNotFound
+
+		return nil
+	}
+
+	if job.Status == pkg.ReportFileStatusCompleted || job.Status == pkg.ReportFileStatusExpired {
+		res.Status = pkg.ResponseStatusBadData
+		res.Message = errors.ErrorReportFileCancelNotAllowed
+
+		return nil
+	}
+
+	if err := app.reportFileRepository.Cancel(ctx, req.FileId); err != nil {
+		if err == mongo.ErrNoDocuments {
+			res.Status = pkg.ResponseStatusBadData
+			res.Message = errors.ErrorReportFileCancelNotAllowed
+
+			return nil
+		}
+
+		res.Status = pkg.ResponseStatusSystemError
+		res.Message = errors.ErrorDatabaseQueryFailed
+
+		return nil
+	}
+
+	// The file was uploaded before cancellation, but the job has not completed, so it is removed right away
+	// instead of waiting for the next processing attempt
+	if job.FileName != "" {
+		app.deleteJobFile(job.Bucket, job.ReportType, job.FileName)
+	}
+
+	res.Status = pkg.ResponseStatusOk
+
+	return nil
+}
+
+// getDownloadUrl signs a download link for the uploaded report file. The link lifetime is limited
+// by the requested ttl, the configured default and the time left until the file retention expires.
+func (app *Application) getDownloadUrl(job *proto.ReportFileJob, ttl int64) (*reporterpb.FileDownloadUrl, error) {
+	lifetime := time.Duration(app.cfg.S3.DownloadUrlTtl) * time.Second
+
+	if ttl > 0 && time.Duration(ttl)*time.Second < lifetime {
+		lifetime = time.Duration(ttl) * time.Second
+	}
+
+	if maxLifetime := time.Duration(pkg.DownloadUrlMaxTtl) * time.Second; lifetime > maxLifetime {
+		lifetime = maxLifetime
+	}
+
+	now := time.Now()
+
+	if job.ExpiresAt != nil {
+		if left := job.ExpiresAt.Sub(now); left < lifetime {
+			lifetime = left
+		}
+	}
+
+	client, err := app.storages.GetStorage(job.Bucket, job.ReportType)
+
+	if err != nil {
+		return nil, err
+	}
+
+	url, err := client.Presign(job.FileName, job.ContentType, lifetime)
+
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt, err := ptypes.TimestampProto(now.Add(lifetime))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &reporterpb.FileDownloadUrl{Url: url, ExpiresAt: expiresAt}, nil
+}
+
+// getReportFileJob returns the report file job of the merchant, the jobs of other merchants are not found.
+func (app *Application) getReportFileJob(
+	ctx context.Context,
+	fileId, merchantId string,
+) (*proto.ReportFileJob, int32, *reporterpb.ResponseErrorMessage) {
+	if merchantId == "" {
+		return nil, pkg.ResponseStatusBadData, errors.ErrorParamMerchantIdNotFound
+	}
+
+	if _, err := primitive.ObjectIDFromHex(fileId); err != nil {
+		return nil, pkg.ResponseStatusNotFound, errors.ErrorReportFileNotFound
+	}
+
+	job, err := app.reportFileRepository.GetById(ctx, fileId)
+
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, pkg.ResponseStatusNotFound, errors.ErrorReportFileNotFound
+		}
+
+		return nil, pkg.ResponseStatusSystemError, errors.ErrorDatabaseQueryFailed
+	}
+
+	if merchantId != job.MerchantId {
+		return nil, pkg.ResponseStatusNotFound, errors.ErrorReportFileNotFound
+	}
+
+	return job, pkg.ResponseStatusOk, nil
+}
+
+func newFileInfo(job *proto.ReportFileJob) *reporterpb.FileInfo {
+	info := &reporterpb.FileInfo{
+		Id:          job.Id.Hex(),
+		UserId:      job.UserId,
+		MerchantId:  job.MerchantId,
+		ReportType:  job.ReportType,
+		FileType:    job.FileType,
+		Status:      job.Status,
+		FileName:    job.FileName,
+		Size:        job.Size,
+		ContentType: job.ContentType,
+	}
+
+	if job.ExpiresAt != nil {
+		info.ExpiresAt, _ = ptypes.TimestampProto(*job.ExpiresAt)
+	}
+
+	if (job.Status == pkg.ReportFileStatusFailed || job.Status == pkg.ReportFileStatusRetrying) && job.LastError != nil {
+		info.Error = &reporterpb.ResponseErrorMessage{Code: job.LastError.Code, Message: job.LastError.Message}
+	}
+
+	info.CreatedAt, _ = ptypes.TimestampProto(job.CreatedAt)
+	info.UpdatedAt, _ = ptypes.TimestampProto(job.UpdatedAt)
+
+	return info
+}
+
 func (app *Application) getTemplate(file *reporterpb.ReportFile) (string, error) {
 	if file.Template != "" {
 		return file.Template, nil
@@ -143,3 +511,100 @@ func (app *Application) getTemplate(file *reporterpb.ReportFile) (string, error)
 
 	return file.Template, errs.New(errors.ErrorTemplateNotFound.Message)
 }
+
+// getReportFilePriority returns the priority set explicitly or inferred from the report type.
+func getReportFilePriority(file *reporterpb.ReportFile) string {
+	if file.Priority != "" {
+		return file.Priority
+	}
+
+	if priority, ok := reportTypePriorities[file.ReportType]; ok {
+		return priority
+	}
+
+	return pkg.ReportFilePriorityBulk
+}
+
+// getGenerateLane returns the topic and the broker of the report generation lane by the report file priority.
+func (app *Application) getGenerateLane(file *reporterpb.ReportFile) (string, rabbitmq.BrokerInterface) {
+	if getReportFilePriority(file) == pkg.ReportFilePriorityInteractive {
+		return pkg.BrokerGenerateReportInteractiveTopicName, app.generateInteractiveBroker
+	}
+
+	return pkg.BrokerGenerateReportTopicName, app.generateReportBroker
+}
+
+// getRenderLimiter returns the limiter of the rendered files of the report generation lane.
+func (app *Application) getRenderLimiter(topic string) *RenderLimiter {
+	if topic == pkg.BrokerGenerateReportInteractiveTopicName {
+		return app.interactiveRenderLimiter
+	}
+
+	return app.renderLimiter
+}
+
+// findDuplicate returns the job in progress or recently completed with the same fingerprint as the report file,
+// or nil when there is no such job. The job with the same idempotency key, but requested with the other report type,
+// file type, template or params is returned with errIdempotencyKeyConflict. The lookup precedes the insert of the job,
+// so it does not protect from the exactly simultaneous requests.
+func (app *Application) findDuplicate(ctx context.Context, file *reporterpb.ReportFile) (*proto.ReportFileJob, error) {
+	window := app.cfg.Idempotency.Window
+
+	if file.IdempotencyKey != "" {
+		window = app.cfg.Idempotency.KeyTtl
+	}
+
+	if window <= 0 {
+		return nil, nil
+	}
+
+	createdFrom := time.Now().Add(-time.Duration(window) * time.Second)
+	job, err := app.reportFileRepository.FindByFingerprint(ctx, file.MerchantId, getReportFileFingerprint(file), createdFrom)
+
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// The jobs created before the params fingerprint was stored are deduplicated by the key only
+	if file.IdempotencyKey != "" && job.ParamsFingerprint != "" &&
+		job.ParamsFingerprint != getReportFileParamsFingerprint(file) {
+		return job, errIdempotencyKeyConflict
+	}
+
+	return job, nil
+}
+
+// getReportFileFingerprint returns the hash identifying the report file request. With the idempotency key
+// the hash is based on the merchant and the key only, otherwise it is the params fingerprint of the report file.
+func getReportFileFingerprint(file *reporterpb.ReportFile) string {
+	if file.IdempotencyKey != "" {
+		return getFingerprintHash("key", file.MerchantId, file.IdempotencyKey)
+	}
+
+	return getReportFileParamsFingerprint(file)
+}
+
+// getReportFileParamsFingerprint returns the hash of the merchant, report type, file type, template and params
+// of the report file. Params are compacted to the canonical JSON, so the order of the keys does not matter.
+func getReportFileParamsFingerprint(file *reporterpb.ReportFile) string {
+	params := file.Params
+	var decoded interface{}
+
+	if err := json.Unmarshal(file.Params, &decoded); err == nil {
+		if b, err := json.Marshal(decoded); err == nil {
+			params = b
+		}
+	}
+
+	return getFingerprintHash("file", file.MerchantId, file.ReportType, file.FileType, file.Template, string(params))
+}
+
+func getFingerprintHash(parts ...string) string {
+	hash := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+
+	return hex.EncodeToString(hash[:])
+}
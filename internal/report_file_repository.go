@@ -0,0 +1,537 @@
+package internal
+
+import (
+	"context"
+	errs "errors"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/paysuper/paysuper-reporter/pkg"
+	"github.com/paysuper/paysuper-reporter/pkg/errors"
+	"github.com/paysuper/paysuper-reporter/pkg/proto"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	mongodb "gopkg.in/paysuper/paysuper-database-mongo.v2"
+	"time"
+)
+
+// expirableStatuses are the statuses of the finished jobs whose uploaded files are purged when the retention
+// time expires. Files of the jobs in progress or waiting for the retry are never purged.
+var expirableStatuses = []string{pkg.ReportFileStatusCompleted, pkg.ReportFileStatusFailed}
+
+// stoppedStatuses are the statuses of the jobs the report pipeline stops processing.
+var stoppedStatuses = []string{pkg.ReportFileStatusCancelled, pkg.ReportFileStatusExpired, pkg.ReportFileStatusErased}
+
+// reportFileIndexes are the indexes of the report file requests limits and deduplication queried on every request
+// of the report file and of the expired files queried by the retention sweeper.
+var reportFileIndexes = []mongo.IndexModel{
+	{Keys: bson.D{{Key: "merchant_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	{Keys: bson.D{{Key: "merchant_id", Value: 1}, {Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	{Keys: bson.D{{Key: "merchant_id", Value: 1}, {Key: "status", Value: 1}}},
+	{Keys: bson.D{{Key: "merchant_id", Value: 1}, {Key: "fingerprint", Value: 1}, {Key: "created_at", Value: -1}}},
+	{Keys: bson.D{{Key: "status", Value: 1}, {Key: "expires_at", Value: 1}}},
+}
+
+type ReportFileRepositoryInterface interface {
+	CreateIndexes(ctx context.Context) error
+	Insert(ctx context.Context, file *reporterpb.ReportFile) error
+	GetById(ctx context.Context, id string) (*proto.ReportFileJob, error)
+	SetStatus(ctx context.Context, id, status string, jobErr *proto.ReportFileJobError) error
+	SetFile(ctx context.Context, id, bucket, fileName, contentType string, size int64, expiresAt *time.Time) error
+	Find(ctx context.Context, merchantId, userId, reportType string, offset, limit int64) ([]*proto.ReportFileJob, error)
+	FindCount(ctx context.Context, merchantId, userId, reportType string) (int64, error)
+	Count(ctx context.Context, query *proto.ReportFileJobCountQuery) (int64, error)
+	FindByFingerprint(ctx context.Context, merchantId, fingerprint string, createdFrom time.Time) (*proto.ReportFileJob, error)
+	Cancel(ctx context.Context, id string) error
+	FindExpired(ctx context.Context, query *proto.ReportFileJobExpiredQuery) ([]*proto.ReportFileJob, error)
+	SetExpired(ctx context.Context, id string) error
+	Erase(ctx context.Context, id string) error
+}
+
+type ReportFileRepository struct {
+	db mongodb.SourceInterface
+}
+
+func newReportFileRepository(db mongodb.SourceInterface) ReportFileRepositoryInterface {
+	return &ReportFileRepository{db: db}
+}
+
+// CreateIndexes creates the indexes of the report file queries, the existing indexes are left as is.
+func (r *ReportFileRepository) CreateIndexes(ctx context.Context) error {
+	_, err := r.db.Collection(pkg.CollectionReportFile).Indexes().CreateMany(ctx, reportFileIndexes)
+
+	if err != nil {
+		zap.L().Error(
+			errors.ErrorDatabaseQueryFailed.Message,
+			zap.Error(err),
+			zap.String("collection", pkg.CollectionReportFile),
+		)
+		return err
+	}
+
+	return nil
+}
+
+func (r *ReportFileRepository) Insert(ctx context.Context, file *reporterpb.ReportFile) error {
+	oid, err := primitive.ObjectIDFromHex(file.Id)
+
+	if err != nil {
+		return errs.New(errors.ErrorMongoDbOidIncorrect.Message)
+	}
+
+	now := time.Now()
+
+	if file.CreatedAt != nil {
+		if createdAt, err := ptypes.Timestamp(file.CreatedAt); err == nil {
+			now = createdAt
+		}
+	}
+
+	job := &proto.ReportFileJob{
+		Id:                oid,
+		UserId:            file.UserId,
+		MerchantId:        file.MerchantId,
+		ReportType:        file.ReportType,
+		FileType:          file.FileType,
+		Params:            file.Params,
+		Template:          file.Template,
+		RetentionTime:     file.RetentionTime,
+		SendNotification:  file.SendNotification,
+		Fingerprint:       getReportFileFingerprint(file),
+		ParamsFingerprint: getReportFileParamsFingerprint(file),
+		IdempotencyKey:    file.IdempotencyKey,
+		Status:            pkg.ReportFileStatusQueued,
+		History: []*proto.ReportFileJobHistory{
+			{Status: pkg.ReportFileStatusQueued, CreatedAt: now},
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if _, err = r.db.Collection(pkg.CollectionReportFile).InsertOne(ctx, job); err != nil {
+		zap.L().Error(
+			errors.ErrorDatabaseQueryFailed.Message,
+			zap.Error(err),
+			zap.String("collection", pkg.CollectionReportFile),
+			zap.Any("document", job),
+		)
+		return err
+	}
+
+	return nil
+}
+
+func (r *ReportFileRepository) GetById(ctx context.Context, id string) (*proto.ReportFileJob, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+
+	if err != nil {
+		return nil, errs.New(errors.ErrorMongoDbOidIncorrect.Message)
+	}
+
+	job := &proto.ReportFileJob{}
+	err = r.db.Collection(pkg.CollectionReportFile).FindOne(ctx, bson.M{"_id": oid}).Decode(job)
+
+	if err != nil {
+		if err != mongo.ErrNoDocuments {
+			zap.L().Error(
+				errors.ErrorDatabaseQueryFailed.Message,
+				zap.Error(err),
+				zap.String("collection", pkg.CollectionReportFile),
+				zap.String("id", id),
+			)
+		}
+
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func (r *ReportFileRepository) SetStatus(ctx context.Context, id, status string, jobErr *proto.ReportFileJobError) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+
+	if err != nil {
+		return errs.New(errors.ErrorMongoDbOidIncorrect.Message)
+	}
+
+	now := time.Now()
+	set := bson.M{"status": status, "updated_at": now}
+
+	if jobErr != nil {
+		set["last_error"] = jobErr
+	}
+
+	update := bson.M{
+		"$set":  set,
+		"$push": bson.M{"history": &proto.ReportFileJobHistory{Status: status, Error: jobErr, CreatedAt: now}},
+	}
+	res, err := r.db.Collection(pkg.CollectionReportFile).UpdateOne(ctx, r.getActiveQuery(oid), update)
+
+	if err != nil {
+		zap.L().Error(
+			errors.ErrorDatabaseQueryFailed.Message,
+			zap.Error(err),
+			zap.String("collection", pkg.CollectionReportFile),
+			zap.String("id", id),
+			zap.String("status", status),
+		)
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+func (r *ReportFileRepository) SetFile(
+	ctx context.Context,
+	id, bucket, fileName, contentType string,
+	size int64,
+	expiresAt *time.Time,
+) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+
+	if err != nil {
+		return errs.New(errors.ErrorMongoDbOidIncorrect.Message)
+	}
+
+	set := bson.M{
+		"bucket":       bucket,
+		"file_name":    fileName,
+		"content_type": contentType,
+		"size":         size,
+		"expires_at":   expiresAt,
+		"updated_at":   time.Now(),
+	}
+	res, err := r.db.Collection(pkg.CollectionReportFile).UpdateOne(ctx, r.getActiveQuery(oid), bson.M{"$set": set})
+
+	if err != nil {
+		zap.L().Error(
+			errors.ErrorDatabaseQueryFailed.Message,
+			zap.Error(err),
+			zap.String("collection", pkg.CollectionReportFile),
+			zap.String("id", id),
+			zap.Any("set", set),
+		)
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+func (r *ReportFileRepository) Find(
+	ctx context.Context,
+	merchantId, userId, reportType string,
+	offset, limit int64,
+) ([]*proto.ReportFileJob, error) {
+	query := r.getFindQuery(merchantId, userId, reportType)
+	opts := options.Find().
+		SetSort(bson.M{"created_at": -1}).
+		SetSkip(offset).
+		SetLimit(limit)
+	cursor, err := r.db.Collection(pkg.CollectionReportFile).Find(ctx, query, opts)
+
+	if err != nil {
+		zap.L().Error(
+			errors.ErrorDatabaseQueryFailed.Message,
+			zap.Error(err),
+			zap.String("collection", pkg.CollectionReportFile),
+			zap.Any("query", query),
+		)
+		return nil, err
+	}
+
+	var jobs []*proto.ReportFileJob
+
+	if err = cursor.All(ctx, &jobs); err != nil {
+		zap.L().Error(
+			errors.ErrorDatabaseQueryFailed.Message,
+			zap.Error(err),
+			zap.String("collection", pkg.CollectionReportFile),
+			zap.Any("query", query),
+		)
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+func (r *ReportFileRepository) FindCount(ctx context.Context, merchantId, userId, reportType string) (int64, error) {
+	query := r.getFindQuery(merchantId, userId, reportType)
+	count, err := r.db.Collection(pkg.CollectionReportFile).CountDocuments(ctx, query)
+
+	if err != nil {
+		zap.L().Error(
+			errors.ErrorDatabaseQueryFailed.Message,
+			zap.Error(err),
+			zap.String("collection", pkg.CollectionReportFile),
+			zap.Any("query", query),
+		)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *ReportFileRepository) Count(ctx context.Context, q *proto.ReportFileJobCountQuery) (int64, error) {
+	query := bson.M{"merchant_id": q.MerchantId}
+
+	if q.UserId != "" {
+		query["user_id"] = q.UserId
+	}
+
+	if len(q.ReportTypes) > 0 {
+		query["report_type"] = bson.M{"$in": q.ReportTypes}
+	}
+
+	if len(q.Statuses) > 0 {
+		query["status"] = bson.M{"$in": q.Statuses}
+	}
+
+	if !q.CreatedFrom.IsZero() {
+		query["created_at"] = bson.M{"$gte": q.CreatedFrom}
+	}
+
+	count, err := r.db.Collection(pkg.CollectionReportFile).CountDocuments(ctx, query)
+
+	if err != nil {
+		zap.L().Error(
+			errors.ErrorDatabaseQueryFailed.Message,
+			zap.Error(err),
+			zap.String("collection", pkg.CollectionReportFile),
+			zap.Any("query", query),
+		)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// FindByFingerprint returns the latest job of the merchant with the fingerprint created since the time,
+// which is in progress or completed.
+func (r *ReportFileRepository) FindByFingerprint(
+	ctx context.Context,
+	merchantId, fingerprint string,
+	createdFrom time.Time,
+) (*proto.ReportFileJob, error) {
+	query := bson.M{
+		"merchant_id": merchantId,
+		"fingerprint": fingerprint,
+		"status":      bson.M{"$in": append([]string{pkg.ReportFileStatusCompleted}, inFlightStatuses...)},
+		"created_at":  bson.M{"$gte": createdFrom},
+	}
+	opts := options.FindOne().SetSort(bson.M{"created_at": -1})
+	job := &proto.ReportFileJob{}
+	err := r.db.Collection(pkg.CollectionReportFile).FindOne(ctx, query, opts).Decode(job)
+
+	if err != nil {
+		if err != mongo.ErrNoDocuments {
+			zap.L().Error(
+				errors.ErrorDatabaseQueryFailed.Message,
+				zap.Error(err),
+				zap.String("collection", pkg.CollectionReportFile),
+				zap.Any("query", query),
+			)
+		}
+
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// Cancel marks the job as cancelled unless it has already been completed, cancelled, expired or erased.
+func (r *ReportFileRepository) Cancel(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+
+	if err != nil {
+		return errs.New(errors.ErrorMongoDbOidIncorrect.Message)
+	}
+
+	now := time.Now()
+	query := bson.M{
+		"_id":    oid,
+		"status": bson.M{"$nin": append([]string{pkg.ReportFileStatusCompleted}, stoppedStatuses...)},
+	}
+	update := bson.M{
+		"$set":  bson.M{"status": pkg.ReportFileStatusCancelled, "updated_at": now},
+		"$push": bson.M{"history": &proto.ReportFileJobHistory{Status: pkg.ReportFileStatusCancelled, CreatedAt: now}},
+	}
+	res, err := r.db.Collection(pkg.CollectionReportFile).UpdateOne(ctx, query, update)
+
+	if err != nil {
+		zap.L().Error(
+			errors.ErrorDatabaseQueryFailed.Message,
+			zap.Error(err),
+			zap.String("collection", pkg.CollectionReportFile),
+			zap.String("id", id),
+		)
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// FindExpired returns the finished jobs with the uploaded files whose retention time has expired by the time,
+// the earliest expired first.
+func (r *ReportFileRepository) FindExpired(
+	ctx context.Context,
+	q *proto.ReportFileJobExpiredQuery,
+) ([]*proto.ReportFileJob, error) {
+	query := bson.M{
+		"status":     bson.M{"$in": expirableStatuses},
+		"file_name":  bson.M{"$ne": ""},
+		"expires_at": bson.M{"$lte": q.ExpiredAt},
+	}
+
+	if len(q.ExcludeMerchantIds) > 0 {
+		query["merchant_id"] = bson.M{"$nin": q.ExcludeMerchantIds}
+	}
+
+	if len(q.ExcludeIds) > 0 {
+		query["_id"] = bson.M{"$nin": q.ExcludeIds}
+	}
+
+	opts := options.Find().
+		SetSort(bson.M{"expires_at": 1}).
+		SetLimit(q.Limit)
+	cursor, err := r.db.Collection(pkg.CollectionReportFile).Find(ctx, query, opts)
+
+	if err != nil {
+		zap.L().Error(
+			errors.ErrorDatabaseQueryFailed.Message,
+			zap.Error(err),
+			zap.String("collection", pkg.CollectionReportFile),
+			zap.Any("query", query),
+		)
+		return nil, err
+	}
+
+	var jobs []*proto.ReportFileJob
+
+	if err = cursor.All(ctx, &jobs); err != nil {
+		zap.L().Error(
+			errors.ErrorDatabaseQueryFailed.Message,
+			zap.Error(err),
+			zap.String("collection", pkg.CollectionReportFile),
+			zap.Any("query", query),
+		)
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// SetExpired marks the finished job as expired after its file has been purged.
+func (r *ReportFileRepository) SetExpired(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+
+	if err != nil {
+		return errs.New(errors.ErrorMongoDbOidIncorrect.Message)
+	}
+
+	now := time.Now()
+	query := bson.M{"_id": oid, "status": bson.M{"$in": expirableStatuses}}
+	update := bson.M{
+		"$set":  bson.M{"status": pkg.ReportFileStatusExpired, "updated_at": now},
+		"$push": bson.M{"history": &proto.ReportFileJobHistory{Status: pkg.ReportFileStatusExpired, CreatedAt: now}},
+	}
+	res, err := r.db.Collection(pkg.CollectionReportFile).UpdateOne(ctx, query, update)
+
+	if err != nil {
+		zap.L().Error(
+			errors.ErrorDatabaseQueryFailed.Message,
+			zap.Error(err),
+			zap.String("collection", pkg.CollectionReportFile),
+			zap.String("id", id),
+		)
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// Erase removes the params and the file info of the job erased on the merchant request. The job itself is kept
+// with the erased status, so the report pipeline stops processing it.
+func (r *ReportFileRepository) Erase(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+
+	if err != nil {
+		return errs.New(errors.ErrorMongoDbOidIncorrect.Message)
+	}
+
+	now := time.Now()
+	query := bson.M{"_id": oid, "status": bson.M{"$ne": pkg.ReportFileStatusErased}}
+	update := bson.M{
+		"$set": bson.M{"status": pkg.ReportFileStatusErased, "updated_at": now},
+		"$unset": bson.M{
+			"params":             "",
+			"template":           "",
+			"bucket":             "",
+			"file_name":          "",
+			"content_type":       "",
+			"expires_at":         "",
+			"last_error":         "",
+			"fingerprint":        "",
+			"params_fingerprint": "",
+			"idempotency_key":    "",
+		},
+		"$push": bson.M{"history": &proto.ReportFileJobHistory{Status: pkg.ReportFileStatusErased, CreatedAt: now}},
+	}
+	res, err := r.db.Collection(pkg.CollectionReportFile).UpdateOne(ctx, query, update)
+
+	if err != nil {
+		zap.L().Error(
+			errors.ErrorDatabaseQueryFailed.Message,
+			zap.Error(err),
+			zap.String("collection", pkg.CollectionReportFile),
+			zap.String("id", id),
+		)
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// getActiveQuery returns the filter that protects a cancelled, expired or erased job from being updated by
+// the report pipeline.
+func (r *ReportFileRepository) getActiveQuery(oid primitive.ObjectID) bson.M {
+	return bson.M{
+		"_id":    oid,
+		"status": bson.M{"$nin": stoppedStatuses},
+	}
+}
+
+func (r *ReportFileRepository) getFindQuery(merchantId, userId, reportType string) bson.M {
+	query := bson.M{"merchant_id": merchantId}
+
+	if userId != "" {
+		query["user_id"] = userId
+	}
+
+	if reportType != "" {
+		query["report_type"] = reportType
+	}
+
+	return query
+}
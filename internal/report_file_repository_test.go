@@ -0,0 +1,396 @@
+package internal
+
+import (
+	"context"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/paysuper/paysuper-reporter/pkg"
+	"github.com/paysuper/paysuper-reporter/pkg/errors"
+	"github.com/paysuper/paysuper-reporter/pkg/proto"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	mongodb "gopkg.in/paysuper/paysuper-database-mongo.v2"
+	"testing"
+	"time"
+)
+
+type ReportFileRepositoryTestSuite struct {
+	suite.Suite
+	db         mongodb.SourceInterface
+	repository ReportFileRepositoryInterface
+}
+
+func Test_ReportFileRepository(t *testing.T) {
+	suite.Run(t, new(ReportFileRepositoryTestSuite))
+}
+
+func (suite *ReportFileRepositoryTestSuite) SetupTest() {
+	db, err := mongodb.NewDatabase()
+	assert.NoError(suite.T(), err, "Database connection failed")
+
+	suite.db = db
+	suite.repository = newReportFileRepository(db)
+}
+
+func (suite *ReportFileRepositoryTestSuite) TearDownTest() {
+	if err := suite.db.Drop(); err != nil {
+		suite.FailNow("Database deletion failed", "%v", err)
+	}
+
+	if err := suite.db.Close(); err != nil {
+		suite.FailNow("Database close failed", "%v", err)
+	}
+}
+
+func (suite *ReportFileRepositoryTestSuite) TestReportFileRepository_CreateIndexes_Ok() {
+	assert.NoError(suite.T(), suite.repository.CreateIndexes(context.TODO()))
+	// The existing indexes are kept on the next start
+	assert.NoError(suite.T(), suite.repository.CreateIndexes(context.TODO()))
+
+	cursor, err := suite.db.Collection(pkg.CollectionReportFile).Indexes().List(context.TODO())
+	assert.NoError(suite.T(), err)
+
+	var indexes []bson.M
+	assert.NoError(suite.T(), cursor.All(context.TODO(), &indexes))
+	// The default index of the identifier and the indexes of the report file queries
+	assert.Len(suite.T(), indexes, len(reportFileIndexes)+1)
+}
+
+func (suite *ReportFileRepositoryTestSuite) TestReportFileRepository_Insert_Ok() {
+	file := suite.getReportFileTemplate()
+	assert.NoError(suite.T(), suite.repository.Insert(context.TODO(), file))
+
+	job, err := suite.repository.GetById(context.TODO(), file.Id)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), file.Id, job.Id.Hex())
+	assert.Equal(suite.T(), file.MerchantId, job.MerchantId)
+	assert.Equal(suite.T(), file.ReportType, job.ReportType)
+	assert.Equal(suite.T(), pkg.ReportFileStatusQueued, job.Status)
+	assert.Len(suite.T(), job.History, 1)
+	assert.Nil(suite.T(), job.LastError)
+}
+
+func (suite *ReportFileRepositoryTestSuite) TestReportFileRepository_Insert_Error_InvalidId() {
+	file := suite.getReportFileTemplate()
+	file.Id = "invalid"
+
+	err := suite.repository.Insert(context.TODO(), file)
+	assert.EqualError(suite.T(), err, errors.ErrorMongoDbOidIncorrect.Message)
+}
+
+func (suite *ReportFileRepositoryTestSuite) TestReportFileRepository_GetById_Error_NotFound() {
+	_, err := suite.repository.GetById(context.TODO(), primitive.NewObjectID().Hex())
+	assert.Equal(suite.T(), mongo.ErrNoDocuments, err)
+}
+
+func (suite *ReportFileRepositoryTestSuite) TestReportFileRepository_SetStatus_Ok() {
+	file := suite.getReportFileTemplate()
+	assert.NoError(suite.T(), suite.repository.Insert(context.TODO(), file))
+
+	jobErr := &proto.ReportFileJobError{
+		Code:    errors.ErrorDocumentGeneratorRender.Code,
+		Message: errors.ErrorDocumentGeneratorRender.Message,
+		Details: "connection refused",
+	}
+	err := suite.repository.SetStatus(context.TODO(), file.Id, pkg.ReportFileStatusFailed, jobErr)
+	assert.NoError(suite.T(), err)
+
+	err = suite.repository.SetStatus(context.TODO(), file.Id, pkg.ReportFileStatusBuilding, nil)
+	assert.NoError(suite.T(), err)
+
+	job, err := suite.repository.GetById(context.TODO(), file.Id)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ReportFileStatusBuilding, job.Status)
+	assert.Equal(suite.T(), jobErr, job.LastError)
+	assert.Len(suite.T(), job.History, 3)
+	assert.Equal(suite.T(), pkg.ReportFileStatusFailed, job.History[1].Status)
+	assert.Equal(suite.T(), jobErr, job.History[1].Error)
+	assert.Nil(suite.T(), job.History[2].Error)
+}
+
+func (suite *ReportFileRepositoryTestSuite) TestReportFileRepository_SetStatus_Error_NotFound() {
+	err := suite.repository.SetStatus(context.TODO(), primitive.NewObjectID().Hex(), pkg.ReportFileStatusBuilding, nil)
+	assert.Equal(suite.T(), mongo.ErrNoDocuments, err)
+}
+
+func (suite *ReportFileRepositoryTestSuite) TestReportFileRepository_SetFile_Ok() {
+	file := suite.getReportFileTemplate()
+	assert.NoError(suite.T(), suite.repository.Insert(context.TODO(), file))
+
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Millisecond)
+	err := suite.repository.SetFile(context.TODO(), file.Id, "reports", "report.pdf", "application/pdf", 1024, &expiresAt)
+	assert.NoError(suite.T(), err)
+
+	job, err := suite.repository.GetById(context.TODO(), file.Id)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "reports", job.Bucket)
+	assert.Equal(suite.T(), "report.pdf", job.FileName)
+	assert.Equal(suite.T(), "application/pdf", job.ContentType)
+	assert.EqualValues(suite.T(), 1024, job.Size)
+	assert.NotNil(suite.T(), job.ExpiresAt)
+	assert.Equal(suite.T(), expiresAt, job.ExpiresAt.UTC())
+}
+
+func (suite *ReportFileRepositoryTestSuite) TestReportFileRepository_SetFile_Error_NotFound() {
+	err := suite.repository.SetFile(context.TODO(), primitive.NewObjectID().Hex(), "reports", "report.pdf", "application/pdf", 1, nil)
+	assert.Equal(suite.T(), mongo.ErrNoDocuments, err)
+}
+
+func (suite *ReportFileRepositoryTestSuite) TestReportFileRepository_Find_Ok() {
+	for i := 0; i < 3; i++ {
+		assert.NoError(suite.T(), suite.repository.Insert(context.TODO(), suite.getReportFileTemplate()))
+	}
+
+	file := suite.getReportFileTemplate()
+	file.ReportType = reporterpb.ReportTypeRoyalty
+	assert.NoError(suite.T(), suite.repository.Insert(context.TODO(), file))
+
+	file = suite.getReportFileTemplate()
+	file.MerchantId = primitive.NewObjectID().Hex()
+	assert.NoError(suite.T(), suite.repository.Insert(context.TODO(), file))
+
+	merchantId := "ffffffffffffffffffffffff"
+
+	count, err := suite.repository.FindCount(context.TODO(), merchantId, "", "")
+	assert.NoError(suite.T(), err)
+	assert.EqualValues(suite.T(), 4, count)
+
+	jobs, err := suite.repository.Find(context.TODO(), merchantId, "", "", 0, 2)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), jobs, 2)
+
+	count, err = suite.repository.FindCount(context.TODO(), merchantId, "", reporterpb.ReportTypeVat)
+	assert.NoError(suite.T(), err)
+	assert.EqualValues(suite.T(), 3, count)
+
+	jobs, err = suite.repository.Find(context.TODO(), merchantId, "", reporterpb.ReportTypeRoyalty, 0, 10)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), jobs, 1)
+	assert.Equal(suite.T(), reporterpb.ReportTypeRoyalty, jobs[0].ReportType)
+}
+
+func (suite *ReportFileRepositoryTestSuite) TestReportFileRepository_Find_Empty() {
+	jobs, err := suite.repository.Find(context.TODO(), primitive.NewObjectID().Hex(), "", "", 0, 10)
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), jobs)
+}
+
+func (suite *ReportFileRepositoryTestSuite) TestReportFileRepository_Count_Ok() {
+	for i := 0; i < 2; i++ {
+		assert.NoError(suite.T(), suite.repository.Insert(context.TODO(), suite.getReportFileTemplate()))
+	}
+
+	file := suite.getReportFileTemplate()
+	file.UserId = primitive.NewObjectID().Hex()
+	file.ReportType = reporterpb.ReportTypeTransactions
+	assert.NoError(suite.T(), suite.repository.Insert(context.TODO(), file))
+	assert.NoError(suite.T(), suite.repository.SetStatus(context.TODO(), file.Id, pkg.ReportFileStatusCompleted, nil))
+
+	file = suite.getReportFileTemplate()
+	file.CreatedAt, _ = ptypes.TimestampProto(time.Now().Add(-48 * time.Hour))
+	assert.NoError(suite.T(), suite.repository.Insert(context.TODO(), file))
+
+	merchantId := "ffffffffffffffffffffffff"
+
+	count, err := suite.repository.Count(context.TODO(), &proto.ReportFileJobCountQuery{MerchantId: merchantId})
+	assert.NoError(suite.T(), err)
+	assert.EqualValues(suite.T(), 4, count)
+
+	count, err = suite.repository.Count(
+		context.TODO(),
+		&proto.ReportFileJobCountQuery{MerchantId: merchantId, CreatedFrom: time.Now().Add(-time.Hour)},
+	)
+	assert.NoError(suite.T(), err)
+	assert.EqualValues(suite.T(), 3, count)
+
+	count, err = suite.repository.Count(
+		context.TODO(),
+		&proto.ReportFileJobCountQuery{MerchantId: merchantId, Statuses: []string{pkg.ReportFileStatusQueued}},
+	)
+	assert.NoError(suite.T(), err)
+	assert.EqualValues(suite.T(), 3, count)
+
+	count, err = suite.repository.Count(
+		context.TODO(),
+		&proto.ReportFileJobCountQuery{
+			MerchantId:  merchantId,
+			UserId:      file.UserId,
+			ReportTypes: []string{reporterpb.ReportTypeVat, reporterpb.ReportTypeRoyalty},
+		},
+	)
+	assert.NoError(suite.T(), err)
+	assert.EqualValues(suite.T(), 3, count)
+}
+
+func (suite *ReportFileRepositoryTestSuite) TestReportFileRepository_FindByFingerprint_Ok() {
+	file := suite.getReportFileTemplate()
+	file.Params = []byte(`{"country":"RU"}`)
+	assert.NoError(suite.T(), suite.repository.Insert(context.TODO(), file))
+
+	fingerprint := getReportFileFingerprint(file)
+	job, err := suite.repository.FindByFingerprint(context.TODO(), file.MerchantId, fingerprint, time.Now().Add(-time.Minute))
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), file.Id, job.Id.Hex())
+	assert.Equal(suite.T(), fingerprint, job.Fingerprint)
+
+	_, err = suite.repository.FindByFingerprint(context.TODO(), file.MerchantId, fingerprint, time.Now().Add(time.Minute))
+	assert.Equal(suite.T(), mongo.ErrNoDocuments, err)
+
+	assert.NoError(suite.T(), suite.repository.SetStatus(context.TODO(), file.Id, pkg.ReportFileStatusFailed, nil))
+	_, err = suite.repository.FindByFingerprint(context.TODO(), file.MerchantId, fingerprint, time.Now().Add(-time.Minute))
+	assert.Equal(suite.T(), mongo.ErrNoDocuments, err)
+}
+
+func (suite *ReportFileRepositoryTestSuite) TestReportFileRepository_Cancel_Ok() {
+	file := suite.getReportFileTemplate()
+	assert.NoError(suite.T(), suite.repository.Insert(context.TODO(), file))
+	assert.NoError(suite.T(), suite.repository.Cancel(context.TODO(), file.Id))
+
+	err := suite.repository.SetStatus(context.TODO(), file.Id, pkg.ReportFileStatusRendering, nil)
+	assert.Equal(suite.T(), mongo.ErrNoDocuments, err)
+
+	err = suite.repository.SetFile(context.TODO(), file.Id, "reports", "report.pdf", "application/pdf", 1, nil)
+	assert.Equal(suite.T(), mongo.ErrNoDocuments, err)
+
+	job, err := suite.repository.GetById(context.TODO(), file.Id)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ReportFileStatusCancelled, job.Status)
+	assert.Empty(suite.T(), job.FileName)
+	assert.Len(suite.T(), job.History, 2)
+}
+
+func (suite *ReportFileRepositoryTestSuite) TestReportFileRepository_Cancel_Error_Completed() {
+	file := suite.getReportFileTemplate()
+	assert.NoError(suite.T(), suite.repository.Insert(context.TODO(), file))
+	assert.NoError(suite.T(), suite.repository.SetStatus(context.TODO(), file.Id, pkg.ReportFileStatusCompleted, nil))
+
+	err := suite.repository.Cancel(context.TODO(), file.Id)
+	assert.Equal(suite.T(), mongo.ErrNoDocuments, err)
+}
+
+func (suite *ReportFileRepositoryTestSuite) TestReportFileRepository_FindExpired_Ok() {
+	now := time.Now()
+	expiresAt := []time.Time{now.Add(-time.Minute), now.Add(-time.Hour), now.Add(time.Hour), now.Add(-time.Hour)}
+	statuses := []string{
+		pkg.ReportFileStatusCompleted,
+		pkg.ReportFileStatusFailed,
+		pkg.ReportFileStatusCompleted,
+		pkg.ReportFileStatusPostProcessing,
+	}
+	var ids []string
+
+	for i := range expiresAt {
+		file := suite.getReportFileTemplate()
+		ids = append(ids, file.Id)
+		assert.NoError(suite.T(), suite.repository.Insert(context.TODO(), file))
+		assert.NoError(suite.T(), suite.repository.SetFile(context.TODO(), file.Id, "reports", "report.pdf", "application/pdf", 1, &expiresAt[i]))
+		assert.NoError(suite.T(), suite.repository.SetStatus(context.TODO(), file.Id, statuses[i], nil))
+	}
+
+	file := suite.getReportFileTemplate()
+	assert.NoError(suite.T(), suite.repository.Insert(context.TODO(), file))
+	assert.NoError(suite.T(), suite.repository.SetStatus(context.TODO(), file.Id, pkg.ReportFileStatusCompleted, nil))
+
+	query := &proto.ReportFileJobExpiredQuery{ExpiredAt: now, Limit: 10}
+	jobs, err := suite.repository.FindExpired(context.TODO(), query)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), jobs, 2)
+	assert.Equal(suite.T(), ids[1], jobs[0].Id.Hex())
+	assert.Equal(suite.T(), ids[0], jobs[1].Id.Hex())
+
+	query.Limit = 1
+	jobs, err = suite.repository.FindExpired(context.TODO(), query)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), jobs, 1)
+
+	oid, _ := primitive.ObjectIDFromHex(ids[1])
+	query = &proto.ReportFileJobExpiredQuery{ExpiredAt: now, ExcludeIds: []primitive.ObjectID{oid}, Limit: 10}
+	jobs, err = suite.repository.FindExpired(context.TODO(), query)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), jobs, 1)
+	assert.Equal(suite.T(), ids[0], jobs[0].Id.Hex())
+
+	query = &proto.ReportFileJobExpiredQuery{ExpiredAt: now, ExcludeMerchantIds: []string{file.MerchantId}, Limit: 10}
+	jobs, err = suite.repository.FindExpired(context.TODO(), query)
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), jobs)
+}
+
+func (suite *ReportFileRepositoryTestSuite) TestReportFileRepository_SetExpired_Ok() {
+	file := suite.getReportFileTemplate()
+	assert.NoError(suite.T(), suite.repository.Insert(context.TODO(), file))
+	assert.NoError(suite.T(), suite.repository.SetStatus(context.TODO(), file.Id, pkg.ReportFileStatusCompleted, nil))
+	assert.NoError(suite.T(), suite.repository.SetExpired(context.TODO(), file.Id))
+
+	job, err := suite.repository.GetById(context.TODO(), file.Id)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ReportFileStatusExpired, job.Status)
+	assert.Len(suite.T(), job.History, 3)
+
+	err = suite.repository.SetExpired(context.TODO(), file.Id)
+	assert.Equal(suite.T(), mongo.ErrNoDocuments, err)
+
+	err = suite.repository.SetStatus(context.TODO(), file.Id, pkg.ReportFileStatusCompleted, nil)
+	assert.Equal(suite.T(), mongo.ErrNoDocuments, err)
+
+	err = suite.repository.Cancel(context.TODO(), file.Id)
+	assert.Equal(suite.T(), mongo.ErrNoDocuments, err)
+}
+
+func (suite *ReportFileRepositoryTestSuite) TestReportFileRepository_SetExpired_Error_InProgress() {
+	file := suite.getReportFileTemplate()
+	assert.NoError(suite.T(), suite.repository.Insert(context.TODO(), file))
+
+	err := suite.repository.SetExpired(context.TODO(), file.Id)
+	assert.Equal(suite.T(), mongo.ErrNoDocuments, err)
+}
+
+func (suite *ReportFileRepositoryTestSuite) TestReportFileRepository_Erase_Ok() {
+	file := suite.getReportFileTemplate()
+	file.Params = []byte(`{"country":"RU"}`)
+	file.IdempotencyKey = "key"
+	expiresAt := time.Now().Add(time.Hour)
+	assert.NoError(suite.T(), suite.repository.Insert(context.TODO(), file))
+	assert.NoError(suite.T(), suite.repository.SetFile(context.TODO(), file.Id, "reports", "report.pdf", "application/pdf", 1, &expiresAt))
+	assert.NoError(suite.T(), suite.repository.SetStatus(context.TODO(), file.Id, pkg.ReportFileStatusCompleted, nil))
+	assert.NoError(suite.T(), suite.repository.Erase(context.TODO(), file.Id))
+
+	job, err := suite.repository.GetById(context.TODO(), file.Id)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ReportFileStatusErased, job.Status)
+	assert.Empty(suite.T(), job.Params)
+	assert.Empty(suite.T(), job.Bucket)
+	assert.Empty(suite.T(), job.FileName)
+	assert.Empty(suite.T(), job.Fingerprint)
+	assert.Empty(suite.T(), job.ParamsFingerprint)
+	assert.Empty(suite.T(), job.IdempotencyKey)
+	assert.Nil(suite.T(), job.ExpiresAt)
+	assert.Len(suite.T(), job.History, 3)
+
+	err = suite.repository.Erase(context.TODO(), file.Id)
+	assert.Equal(suite.T(), mongo.ErrNoDocuments, err)
+
+	err = suite.repository.SetStatus(context.TODO(), file.Id, pkg.ReportFileStatusCompleted, nil)
+	assert.Equal(suite.T(), mongo.ErrNoDocuments, err)
+
+	err = suite.repository.Cancel(context.TODO(), file.Id)
+	assert.Equal(suite.T(), mongo.ErrNoDocuments, err)
+}
+
+func (suite *ReportFileRepositoryTestSuite) TestReportFileRepository_Erase_Error_Id() {
+	err := suite.repository.Erase(context.TODO(), "id")
+	assert.EqualError(suite.T(), err, errors.ErrorMongoDbOidIncorrect.Message)
+}
+
+func (suite *ReportFileRepositoryTestSuite) getReportFileTemplate() *reporterpb.ReportFile {
+	return &reporterpb.ReportFile{
+		Id:         primitive.NewObjectID().Hex(),
+		UserId:     "ffffffffffffffffffffffff",
+		MerchantId: "ffffffffffffffffffffffff",
+		ReportType: reporterpb.ReportTypeVat,
+		FileType:   reporterpb.OutputExtensionPdf,
+	}
+}
@@ -0,0 +1,117 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"github.com/paysuper/paysuper-reporter/internal/config"
+	"github.com/paysuper/paysuper-reporter/pkg"
+	"github.com/paysuper/paysuper-reporter/pkg/proto"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
+	"time"
+)
+
+// inFlightStatuses are the statuses of the report file jobs counted by the in-flight limits. The failed jobs
+// are not counted, because the dead lettered ones would hold the limit forever.
+var inFlightStatuses = []string{
+	pkg.ReportFileStatusQueued,
+	pkg.ReportFileStatusBuilding,
+	pkg.ReportFileStatusRendering,
+	pkg.ReportFileStatusUploading,
+	pkg.ReportFileStatusUploaded,
+	pkg.ReportFileStatusPostProcessing,
+	pkg.ReportFileStatusRetrying,
+}
+
+type reportLimit struct {
+	limit   int64
+	query   *proto.ReportFileJobCountQuery
+	message string
+}
+
+// ReportLimiter checks the new report file against the request, in-flight and daily quota limits of the merchant
+// and the user. Limits are counted by the stored jobs, so the concurrent requests may exceed them slightly.
+type ReportLimiter struct {
+	cfg        *config.LimitConfig
+	repository ReportFileRepositoryInterface
+	now        func() time.Time
+}
+
+func newReportLimiter(cfg *config.LimitConfig, repository ReportFileRepositoryInterface) *ReportLimiter {
+	return &ReportLimiter{cfg: cfg, repository: repository, now: time.Now}
+}
+
+// Check returns the description of the exceeded limit or an empty string when the report file is allowed.
+func (l *ReportLimiter) Check(ctx context.Context, file *reporterpb.ReportFile) (string, error) {
+	for _, limit := range l.getLimits(file) {
+		if limit.limit <= 0 {
+			continue
+		}
+
+		count, err := l.repository.Count(ctx, limit.query)
+
+		if err != nil {
+			return "", err
+		}
+
+		if count >= limit.limit {
+			return fmt.Sprintf(limit.message, limit.limit), nil
+		}
+	}
+
+	return "", nil
+}
+
+func (l *ReportLimiter) getLimits(file *reporterpb.ReportFile) []*reportLimit {
+	now := l.now().UTC()
+	window := now.Add(-time.Duration(l.cfg.RequestWindow) * time.Second)
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	limits := []*reportLimit{
+		{
+			limit:   l.cfg.MerchantRequests,
+			query:   &proto.ReportFileJobCountQuery{MerchantId: file.MerchantId, CreatedFrom: window},
+			message: "merchant limit of %d requests per " + fmt.Sprintf("%d seconds", l.cfg.RequestWindow),
+		},
+		{
+			limit:   l.cfg.MerchantInFlight,
+			query:   &proto.ReportFileJobCountQuery{MerchantId: file.MerchantId, Statuses: inFlightStatuses},
+			message: "merchant limit of %d report files in progress",
+		},
+	}
+
+	if file.UserId != "" {
+		limits = append(
+			limits,
+			&reportLimit{
+				limit: l.cfg.UserRequests,
+				query: &proto.ReportFileJobCountQuery{
+					MerchantId:  file.MerchantId,
+					UserId:      file.UserId,
+					CreatedFrom: window,
+				},
+				message: "user limit of %d requests per " + fmt.Sprintf("%d seconds", l.cfg.RequestWindow),
+			},
+			&reportLimit{
+				limit: l.cfg.UserInFlight,
+				query: &proto.ReportFileJobCountQuery{
+					MerchantId: file.MerchantId,
+					UserId:     file.UserId,
+					Statuses:   inFlightStatuses,
+				},
+				message: "user limit of %d report files in progress",
+			},
+		)
+	}
+
+	limits = append(limits, &reportLimit{
+		limit: l.cfg.DailyQuotas[file.ReportType],
+		query: &proto.ReportFileJobCountQuery{
+			MerchantId:  file.MerchantId,
+			ReportTypes: []string{file.ReportType},
+			CreatedFrom: day,
+		},
+		message: "merchant daily quota of %d " + file.ReportType + " report files",
+	})
+
+	return limits
+}
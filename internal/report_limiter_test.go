@@ -0,0 +1,128 @@
+package internal
+
+import (
+	"context"
+	errs "errors"
+	"github.com/paysuper/paysuper-reporter/internal/config"
+	"github.com/paysuper/paysuper-reporter/internal/mocks"
+	"github.com/paysuper/paysuper-reporter/pkg"
+	"github.com/paysuper/paysuper-reporter/pkg/proto"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type ReportLimiterTestSuite struct {
+	suite.Suite
+	cfg        *config.LimitConfig
+	repository *mocks.ReportFileRepositoryInterface
+	limiter    *ReportLimiter
+	now        time.Time
+	file       *reporterpb.ReportFile
+}
+
+func Test_ReportLimiter(t *testing.T) {
+	suite.Run(t, new(ReportLimiterTestSuite))
+}
+
+func (suite *ReportLimiterTestSuite) SetupTest() {
+	suite.cfg = &config.LimitConfig{
+		RequestWindow:    60,
+		MerchantRequests: 10,
+		UserRequests:     5,
+		MerchantInFlight: 4,
+		UserInFlight:     2,
+		DailyQuotas:      map[string]int64{reporterpb.ReportTypeTransactions: 3},
+	}
+	suite.repository = &mocks.ReportFileRepositoryInterface{}
+	suite.now = time.Date(2020, 1, 15, 10, 30, 0, 0, time.UTC)
+	suite.limiter = newReportLimiter(suite.cfg, suite.repository)
+	suite.limiter.now = func() time.Time { return suite.now }
+	suite.file = &reporterpb.ReportFile{
+		MerchantId: "ffffffffffffffffffffffff",
+		UserId:     "fffffffffffffffffffffff1",
+		ReportType: reporterpb.ReportTypeTransactions,
+	}
+}
+
+func (suite *ReportLimiterTestSuite) TestReportLimiter_Check_Ok() {
+	suite.repository.On("Count", mock.Anything, mock.Anything).Return(int64(1), nil)
+
+	limit, err := suite.limiter.Check(context.TODO(), suite.file)
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), limit)
+	suite.repository.AssertNumberOfCalls(suite.T(), "Count", 5)
+}
+
+func (suite *ReportLimiterTestSuite) TestReportLimiter_Check_MerchantRequests() {
+	suite.repository.On("Count", mock.Anything, mock.MatchedBy(func(q *proto.ReportFileJobCountQuery) bool {
+		return q.UserId == "" && q.Statuses == nil && q.ReportTypes == nil &&
+			q.CreatedFrom.Equal(suite.now.Add(-time.Minute))
+	})).Return(int64(10), nil)
+
+	limit, err := suite.limiter.Check(context.TODO(), suite.file)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "merchant limit of 10 requests per 60 seconds", limit)
+}
+
+func (suite *ReportLimiterTestSuite) TestReportLimiter_Check_UserInFlight() {
+	suite.repository.On("Count", mock.Anything, mock.MatchedBy(func(q *proto.ReportFileJobCountQuery) bool {
+		return q.UserId == suite.file.UserId && len(q.Statuses) > 0
+	})).Return(int64(2), nil)
+	suite.repository.On("Count", mock.Anything, mock.Anything).Return(int64(0), nil)
+
+	limit, err := suite.limiter.Check(context.TODO(), suite.file)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "user limit of 2 report files in progress", limit)
+	suite.repository.AssertCalled(suite.T(), "Count", mock.Anything, mock.MatchedBy(func(q *proto.ReportFileJobCountQuery) bool {
+		for _, status := range q.Statuses {
+			if status == pkg.ReportFileStatusFailed || status == pkg.ReportFileStatusCompleted {
+				return false
+			}
+		}
+
+		return len(q.Statuses) == len(inFlightStatuses)
+	}))
+}
+
+func (suite *ReportLimiterTestSuite) TestReportLimiter_Check_DailyQuota() {
+	suite.repository.On("Count", mock.Anything, mock.MatchedBy(func(q *proto.ReportFileJobCountQuery) bool {
+		return len(q.ReportTypes) == 1 && q.ReportTypes[0] == reporterpb.ReportTypeTransactions &&
+			q.CreatedFrom.Equal(time.Date(2020, 1, 15, 0, 0, 0, 0, time.UTC))
+	})).Return(int64(3), nil)
+	suite.repository.On("Count", mock.Anything, mock.Anything).Return(int64(0), nil)
+
+	limit, err := suite.limiter.Check(context.TODO(), suite.file)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "merchant daily quota of 3 transactions report files", limit)
+}
+
+func (suite *ReportLimiterTestSuite) TestReportLimiter_Check_NoUser() {
+	suite.file.UserId = ""
+	suite.repository.On("Count", mock.Anything, mock.Anything).Return(int64(0), nil)
+
+	limit, err := suite.limiter.Check(context.TODO(), suite.file)
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), limit)
+	suite.repository.AssertNumberOfCalls(suite.T(), "Count", 3)
+}
+
+func (suite *ReportLimiterTestSuite) TestReportLimiter_Check_Disabled() {
+	suite.limiter = newReportLimiter(&config.LimitConfig{}, suite.repository)
+
+	limit, err := suite.limiter.Check(context.TODO(), suite.file)
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), limit)
+	suite.repository.AssertNotCalled(suite.T(), "Count", mock.Anything, mock.Anything)
+}
+
+func (suite *ReportLimiterTestSuite) TestReportLimiter_Check_Error() {
+	suite.repository.On("Count", mock.Anything, mock.Anything).Return(int64(0), errs.New("error"))
+
+	limit, err := suite.limiter.Check(context.TODO(), suite.file)
+	assert.Error(suite.T(), err)
+	assert.Empty(suite.T(), limit)
+}
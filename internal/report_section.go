@@ -0,0 +1,200 @@
+package internal
+
+import (
+	"errors"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
+)
+
+const (
+	ReportColumnTypeText     = "text"
+	ReportColumnTypeInteger  = "integer"
+	ReportColumnTypeNumber   = "number"
+	ReportColumnTypeCurrency = "currency"
+)
+
+var (
+	errReportSectionRowsInvalid = errors.New("report section rows have unsupported type")
+
+	// reportFileSections describes the tabular data of the builder output for the native renderers.
+	// The first section of the report type is the main table of the report.
+	reportFileSections = map[string][]*ReportSection{
+		reporterpb.ReportTypeTransactions: {
+			{
+				Name:      "Transactions",
+				RowsField: "transactions",
+				Columns: []*ReportColumn{
+					{Title: "Project", Field: "project_name"},
+					{Title: "Product", Field: "product_name"},
+					{Title: "Date", Field: "datetime"},
+					{Title: "Country", Field: "country"},
+					{Title: "Payment method", Field: "payment_method"},
+					{Title: "Transaction ID", Field: "transaction_id"},
+					{Title: "Amount", Field: "net_amount", Type: ReportColumnTypeCurrency, CurrencyField: "currency"},
+					{Title: "Currency", Field: "currency"},
+					{Title: "Status", Field: "status"},
+				},
+			},
+		},
+		reporterpb.ReportTypeVat: {
+			{
+				Name:      "VAT reports",
+				RowsField: "reports",
+				Columns: []*ReportColumn{
+					{Title: "Period from", Field: "period_from"},
+					{Title: "Period to", Field: "period_to"},
+					{Title: "VAT ID", Field: "vat_id"},
+					{Title: "Status", Field: "status"},
+					{Title: "Payment date", Field: "payment_date"},
+					{Title: "Tax amount", Field: "tax_amount", Type: ReportColumnTypeCurrency, CurrencyField: "currency"},
+					{Title: "Transactions", Field: "transactions_count", Type: ReportColumnTypeInteger},
+					{Title: "Gross amount", Field: "gross_amount", Type: ReportColumnTypeCurrency, CurrencyField: "currency"},
+					{Title: "Deduction amount", Field: "deduction_amount", Type: ReportColumnTypeCurrency, CurrencyField: "currency"},
+					{Title: "Correction amount", Field: "correction_amount", Type: ReportColumnTypeCurrency, CurrencyField: "currency"},
+					{Title: "Country annual turnover", Field: "country_annual_turnover", Type: ReportColumnTypeCurrency, CurrencyField: "currency"},
+					{Title: "World annual turnover", Field: "world_annual_turnover", Type: ReportColumnTypeCurrency, CurrencyField: "currency"},
+				},
+			},
+		},
+		reporterpb.ReportTypeVatTransactions: {
+			{
+				Name:      "Transactions",
+				RowsField: "transactions",
+				Columns: []*ReportColumn{
+					{Title: "Date", Field: "date"},
+					{Title: "Country", Field: "country"},
+					{Title: "Transaction ID", Field: "id"},
+					{Title: "Payment method", Field: "payment_method"},
+					{Title: "Amount", Field: "amount", Type: ReportColumnTypeCurrency, CurrencyField: "amount_currency"},
+					{Title: "Amount currency", Field: "amount_currency"},
+					{Title: "VAT", Field: "vat", Type: ReportColumnTypeCurrency, CurrencyField: "vat_currency"},
+					{Title: "VAT currency", Field: "vat_currency"},
+					{Title: "Fee", Field: "fee", Type: ReportColumnTypeCurrency, CurrencyField: "fee_currency"},
+					{Title: "Fee currency", Field: "fee_currency"},
+					{Title: "Payout", Field: "payout", Type: ReportColumnTypeCurrency, CurrencyField: "payout_currency"},
+					{Title: "Payout currency", Field: "payout_currency"},
+					{Title: "VAT deduction", Field: "is_vat_deduction"},
+				},
+			},
+		},
+		reporterpb.ReportTypeRoyalty: {
+			{
+				Name:      "Products",
+				RowsField: "products",
+				Columns: []*ReportColumn{
+					{Title: "Product", Field: "product"},
+					{Title: "Region", Field: "region"},
+					{Title: "Total end user sales", Field: "total_end_user_sales", Type: ReportColumnTypeInteger},
+					{Title: "Total end user fees", Field: "total_end_user_fees", Type: ReportColumnTypeCurrency, CurrencyField: "currency"},
+					{Title: "Returns qty", Field: "returns_qty", Type: ReportColumnTypeInteger},
+					{Title: "Returns amount", Field: "returns_amount", Type: ReportColumnTypeCurrency, CurrencyField: "currency"},
+					{Title: "End user sales", Field: "end_user_sales", Type: ReportColumnTypeInteger},
+					{Title: "End user fees", Field: "end_user_fees", Type: ReportColumnTypeCurrency, CurrencyField: "currency"},
+					{Title: "VAT on end user sales", Field: "vat_on_end_user_sales", Type: ReportColumnTypeCurrency, CurrencyField: "currency"},
+					{Title: "License revenue share", Field: "license_revenue_share", Type: ReportColumnTypeCurrency, CurrencyField: "currency"},
+					{Title: "License fee", Field: "license_fee", Type: ReportColumnTypeCurrency, CurrencyField: "currency"},
+				},
+			},
+			{
+				Name:      "Corrections",
+				RowsField: "corrections",
+				Columns: []*ReportColumn{
+					{Title: "Date", Field: "entry_date"},
+					{Title: "Amount", Field: "amount", Type: ReportColumnTypeCurrency, CurrencyField: "currency"},
+					{Title: "Reason", Field: "reason"},
+				},
+			},
+			{
+				Name:      "Totals",
+				RowsField: "products_total",
+				Columns: []*ReportColumn{
+					{Title: "Total end user sales", Field: "total_end_user_sales", Type: ReportColumnTypeInteger},
+					{Title: "Total end user fees", Field: "total_end_user_fees", Type: ReportColumnTypeCurrency, CurrencyField: "currency"},
+					{Title: "Returns qty", Field: "returns_qty", Type: ReportColumnTypeInteger},
+					{Title: "Returns amount", Field: "returns_amount", Type: ReportColumnTypeCurrency, CurrencyField: "currency"},
+					{Title: "End user sales", Field: "end_user_sales", Type: ReportColumnTypeInteger},
+					{Title: "End user fees", Field: "end_user_fees", Type: ReportColumnTypeCurrency, CurrencyField: "currency"},
+					{Title: "VAT on end user sales", Field: "vat_on_end_user_sales", Type: ReportColumnTypeCurrency, CurrencyField: "currency"},
+					{Title: "License revenue share", Field: "license_revenue_share", Type: ReportColumnTypeCurrency, CurrencyField: "currency"},
+					{Title: "License fee", Field: "license_fee", Type: ReportColumnTypeCurrency, CurrencyField: "currency"},
+				},
+			},
+		},
+		reporterpb.ReportTypeRoyaltyTransactions: {
+			{
+				Name:      "Transactions",
+				RowsField: "transactions",
+				Columns: []*ReportColumn{
+					{Title: "Date", Field: "datetime"},
+					{Title: "Transaction ID", Field: "id"},
+					{Title: "Project", Field: "project"},
+					{Title: "Country", Field: "country"},
+					{Title: "Payment method", Field: "method"},
+					{Title: "Status", Field: "status"},
+					{Title: "Gross amount", Field: "gross_amount", Type: ReportColumnTypeCurrency, CurrencyField: "currency"},
+					{Title: "VAT amount", Field: "vat_amount", Type: ReportColumnTypeCurrency, CurrencyField: "currency"},
+					{Title: "Fee amount", Field: "fee_amount", Type: ReportColumnTypeCurrency, CurrencyField: "currency"},
+					{Title: "Net amount", Field: "net_amount", Type: ReportColumnTypeCurrency, CurrencyField: "currency"},
+				},
+			},
+		},
+	}
+)
+
+// ReportSection is the table of the report file.
+type ReportSection struct {
+	Name      string
+	RowsField string
+	Columns   []*ReportColumn
+}
+
+// ReportColumn binds the field of the section row to the column of the table. The currency of the currency
+// column is taken from the CurrencyField of the row or, when the row has no such field, of the whole report.
+type ReportColumn struct {
+	Title         string
+	Field         string
+	Type          string
+	CurrencyField string
+}
+
+// getSectionRows returns the rows of the section from the builder output. The section field may contain
+// a list of rows or a single row.
+func getSectionRows(data interface{}, section *ReportSection) ([]map[string]interface{}, error) {
+	if rows, ok := data.([]map[string]interface{}); ok {
+		return rows, nil
+	}
+
+	fields, ok := data.(map[string]interface{})
+
+	if !ok {
+		if data == nil {
+			return nil, nil
+		}
+
+		return nil, errReportSectionRowsInvalid
+	}
+
+	switch val := fields[section.RowsField].(type) {
+	case nil:
+		return nil, nil
+	case []map[string]interface{}:
+		return val, nil
+	case map[string]interface{}:
+		return []map[string]interface{}{val}, nil
+	case []interface{}:
+		rows := make([]map[string]interface{}, 0, len(val))
+
+		for _, item := range val {
+			row, ok := item.(map[string]interface{})
+
+			if !ok {
+				return nil, errReportSectionRowsInvalid
+			}
+
+			rows = append(rows, row)
+		}
+
+		return rows, nil
+	}
+
+	return nil, errReportSectionRowsInvalid
+}
@@ -4,15 +4,20 @@ import (
 	"context"
 	"encoding/json"
 	errs "errors"
-	"github.com/paysuper/paysuper-proto/go/reporterpb"
 	"github.com/paysuper/paysuper-reporter/internal/config"
+	"github.com/paysuper/paysuper-reporter/internal/mocks"
 	"github.com/paysuper/paysuper-reporter/pkg"
 	"github.com/paysuper/paysuper-reporter/pkg/errors"
+	"github.com/paysuper/paysuper-reporter/pkg/proto"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	rabbitmqMock "gopkg.in/ProtocolONE/rabbitmq.v1/pkg/mocks"
 	"testing"
+	"time"
 )
 
 type ReportTestSuite struct {
@@ -25,7 +30,14 @@ func Test_Report(t *testing.T) {
 }
 
 func (suite *ReportTestSuite) SetupTest() {
-	suite.service = &Application{cfg: &config.Config{}}
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.On("Insert", mock.Anything, mock.Anything).Return(nil)
+	reportFileRepository.On("SetStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	suite.service = &Application{
+		cfg:                  &config.Config{},
+		reportFileRepository: reportFileRepository,
+	}
 }
 
 func (suite *ReportTestSuite) TestReport_CreateFile_Error_ReportType() {
@@ -85,6 +97,33 @@ func (suite *ReportTestSuite) TestReport_CreateFile_Error_Publish() {
 	assert.Equal(suite.T(), "", res.FileId)
 }
 
+func (suite *ReportTestSuite) TestReport_CreateFile_Error_InsertJob() {
+	res := &reporterpb.CreateFileResponse{}
+	params, _ := json.Marshal(map[string]interface{}{reporterpb.ParamsFieldCountry: "RU"})
+	report := &reporterpb.ReportFile{
+		FileType:   reporterpb.OutputExtensionPdf,
+		ReportType: reporterpb.ReportTypeVat,
+		MerchantId: "ffffffffffffffffffffffff",
+		Params:     params,
+	}
+
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.On("Insert", mock.Anything, mock.Anything).Return(errs.New("error"))
+	suite.service.reportFileRepository = reportFileRepository
+
+	broker := &rabbitmqMock.BrokerInterface{}
+	broker.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	suite.service.generateReportBroker = broker
+
+	err := suite.service.CreateFile(context.TODO(), report, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusSystemError, res.Status)
+	assert.Equal(suite.T(), errors.ErrorDatabaseQueryFailed, res.Message)
+	assert.Equal(suite.T(), "", res.FileId)
+	broker.AssertNotCalled(suite.T(), "Publish", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func (suite *ReportTestSuite) TestReport_CreateFile_Ok() {
 	res := &reporterpb.CreateFileResponse{}
 	params, _ := json.Marshal(map[string]interface{}{reporterpb.ParamsFieldCountry: "RU"})
@@ -106,6 +145,926 @@ func (suite *ReportTestSuite) TestReport_CreateFile_Ok() {
 	assert.NotEmpty(suite.T(), res.FileId)
 }
 
+func (suite *ReportTestSuite) TestReport_CreateFile_Error_LimitExceeded() {
+	res := &reporterpb.CreateFileResponse{}
+	params, _ := json.Marshal(map[string]interface{}{reporterpb.ParamsFieldCountry: "RU"})
+	report := &reporterpb.ReportFile{
+		ReportType: reporterpb.ReportTypeVat,
+		FileType:   reporterpb.OutputExtensionPdf,
+		MerchantId: "ffffffffffffffffffffffff",
+		Params:     params,
+	}
+
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.On("Count", mock.Anything, mock.Anything).Return(int64(5), nil)
+	suite.service.reportFileRepository = reportFileRepository
+	suite.service.cfg.Limit = config.LimitConfig{MerchantInFlight: 5}
+
+	broker := &rabbitmqMock.BrokerInterface{}
+	suite.service.generateReportBroker = broker
+
+	err := suite.service.CreateFile(context.TODO(), report, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusTooManyRequests, res.Status)
+	assert.Equal(suite.T(), errors.ErrorReportFileLimitExceeded.Code, res.Message.Code)
+	assert.Equal(suite.T(), "merchant limit of 5 report files in progress", res.Message.Details)
+	assert.Equal(suite.T(), "", res.FileId)
+	reportFileRepository.AssertNotCalled(suite.T(), "Insert", mock.Anything, mock.Anything)
+	broker.AssertNotCalled(suite.T(), "Publish", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *ReportTestSuite) TestReport_CreateFile_Error_LimitCount() {
+	res := &reporterpb.CreateFileResponse{}
+	params, _ := json.Marshal(map[string]interface{}{reporterpb.ParamsFieldCountry: "RU"})
+	report := &reporterpb.ReportFile{
+		ReportType: reporterpb.ReportTypeVat,
+		FileType:   reporterpb.OutputExtensionPdf,
+		MerchantId: "ffffffffffffffffffffffff",
+		Params:     params,
+	}
+
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.On("Count", mock.Anything, mock.Anything).Return(int64(0), errs.New("error"))
+	suite.service.reportFileRepository = reportFileRepository
+	suite.service.cfg.Limit = config.LimitConfig{MerchantRequests: 5}
+
+	err := suite.service.CreateFile(context.TODO(), report, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusSystemError, res.Status)
+	assert.Equal(suite.T(), errors.ErrorDatabaseQueryFailed, res.Message)
+	reportFileRepository.AssertNotCalled(suite.T(), "Insert", mock.Anything, mock.Anything)
+}
+
+func (suite *ReportTestSuite) TestReport_CreateFile_Ok_Duplicate() {
+	res := &reporterpb.CreateFileResponse{}
+	params, _ := json.Marshal(map[string]interface{}{reporterpb.ParamsFieldCountry: "RU"})
+	report := &reporterpb.ReportFile{
+		ReportType: reporterpb.ReportTypeVat,
+		FileType:   reporterpb.OutputExtensionPdf,
+		MerchantId: "ffffffffffffffffffffffff",
+		Params:     params,
+	}
+	job := &proto.ReportFileJob{Id: primitive.NewObjectID(), Status: pkg.ReportFileStatusRendering}
+
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.
+		On("FindByFingerprint", mock.Anything, report.MerchantId, mock.Anything, mock.MatchedBy(func(t time.Time) bool {
+			return t.Before(time.Now().Add(-9*time.Minute)) && t.After(time.Now().Add(-11*time.Minute))
+		})).
+		Return(job, nil)
+	suite.service.reportFileRepository = reportFileRepository
+	suite.service.cfg.Idempotency = config.IdempotencyConfig{Window: 600, KeyTtl: 86400}
+
+	broker := &rabbitmqMock.BrokerInterface{}
+	suite.service.generateReportBroker = broker
+
+	err := suite.service.CreateFile(context.TODO(), report, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusOk, res.Status)
+	assert.Equal(suite.T(), job.Id.Hex(), res.FileId)
+	reportFileRepository.AssertNotCalled(suite.T(), "Insert", mock.Anything, mock.Anything)
+	broker.AssertNotCalled(suite.T(), "Publish", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *ReportTestSuite) TestReport_CreateFile_Ok_IdempotencyKeyNotFound() {
+	res := &reporterpb.CreateFileResponse{}
+	params, _ := json.Marshal(map[string]interface{}{reporterpb.ParamsFieldCountry: "RU"})
+	report := &reporterpb.ReportFile{
+		ReportType:     reporterpb.ReportTypeVat,
+		FileType:       reporterpb.OutputExtensionPdf,
+		MerchantId:     "ffffffffffffffffffffffff",
+		Params:         params,
+		IdempotencyKey: "dashboard-click-1",
+	}
+
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.
+		On("FindByFingerprint", mock.Anything, report.MerchantId, mock.Anything, mock.MatchedBy(func(t time.Time) bool {
+			return t.Before(time.Now().Add(-23 * time.Hour))
+		})).
+		Return(nil, mongo.ErrNoDocuments)
+	reportFileRepository.On("Insert", mock.Anything, mock.Anything).Return(nil)
+	suite.service.reportFileRepository = reportFileRepository
+	suite.service.cfg.Idempotency = config.IdempotencyConfig{Window: 600, KeyTtl: 86400}
+
+	broker := &rabbitmqMock.BrokerInterface{}
+	broker.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	suite.service.generateReportBroker = broker
+
+	err := suite.service.CreateFile(context.TODO(), report, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusOk, res.Status)
+	assert.Equal(suite.T(), report.Id, res.FileId)
+	reportFileRepository.AssertCalled(suite.T(), "Insert", mock.Anything, report)
+}
+
+func (suite *ReportTestSuite) TestReport_CreateFile_Error_FindDuplicate() {
+	res := &reporterpb.CreateFileResponse{}
+	params, _ := json.Marshal(map[string]interface{}{reporterpb.ParamsFieldCountry: "RU"})
+	report := &reporterpb.ReportFile{
+		ReportType: reporterpb.ReportTypeVat,
+		FileType:   reporterpb.OutputExtensionPdf,
+		MerchantId: "ffffffffffffffffffffffff",
+		Params:     params,
+	}
+
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.
+		On("FindByFingerprint", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, errs.New("error"))
+	suite.service.reportFileRepository = reportFileRepository
+	suite.service.cfg.Idempotency = config.IdempotencyConfig{Window: 600}
+
+	err := suite.service.CreateFile(context.TODO(), report, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusSystemError, res.Status)
+	assert.Equal(suite.T(), errors.ErrorDatabaseQueryFailed, res.Message)
+	reportFileRepository.AssertNotCalled(suite.T(), "Insert", mock.Anything, mock.Anything)
+}
+
+func (suite *ReportTestSuite) TestReport_GetReportFileFingerprint() {
+	file := &reporterpb.ReportFile{
+		MerchantId: "ffffffffffffffffffffffff",
+		ReportType: reporterpb.ReportTypeRoyalty,
+		FileType:   reporterpb.OutputExtensionPdf,
+		Template:   "fffffffffffffffffffffff1",
+		Params:     []byte(`{"id": "1", "country": "RU"}`),
+	}
+	fingerprint := getReportFileFingerprint(file)
+	assert.Len(suite.T(), fingerprint, 64)
+
+	file.Params = []byte(`{"country":"RU","id":"1"}`)
+	file.UserId = "fffffffffffffffffffffff2"
+	assert.Equal(suite.T(), fingerprint, getReportFileFingerprint(file))
+
+	file.FileType = reporterpb.OutputExtensionCsv
+	assert.NotEqual(suite.T(), fingerprint, getReportFileFingerprint(file))
+
+	file.IdempotencyKey = "key"
+	keyFingerprint := getReportFileFingerprint(file)
+	assert.NotEqual(suite.T(), fingerprint, keyFingerprint)
+
+	file.Params = []byte(`{"country":"DE"}`)
+	assert.Equal(suite.T(), keyFingerprint, getReportFileFingerprint(file))
+
+	file.MerchantId = "fffffffffffffffffffffff3"
+	assert.NotEqual(suite.T(), keyFingerprint, getReportFileFingerprint(file))
+	assert.Equal(suite.T(), getReportFileParamsFingerprint(file), getReportFileFingerprint(&reporterpb.ReportFile{
+		MerchantId: file.MerchantId,
+		ReportType: file.ReportType,
+		FileType:   file.FileType,
+		Template:   file.Template,
+		Params:     file.Params,
+	}))
+}
+
+func (suite *ReportTestSuite) TestReport_CreateFile_Ok_IdempotencyKeyDuplicate() {
+	res := &reporterpb.CreateFileResponse{}
+	params, _ := json.Marshal(map[string]interface{}{reporterpb.ParamsFieldCountry: "RU"})
+	report := &reporterpb.ReportFile{
+		ReportType:     reporterpb.ReportTypeVat,
+		FileType:       reporterpb.OutputExtensionPdf,
+		MerchantId:     "ffffffffffffffffffffffff",
+		Params:         params,
+		IdempotencyKey: "dashboard-click-1",
+	}
+	job := &proto.ReportFileJob{
+		Id:                primitive.NewObjectID(),
+		Status:            pkg.ReportFileStatusCompleted,
+		ParamsFingerprint: getReportFileParamsFingerprint(report),
+	}
+
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.
+		On("FindByFingerprint", mock.Anything, report.MerchantId, getReportFileFingerprint(report), mock.Anything).
+		Return(job, nil)
+	suite.service.reportFileRepository = reportFileRepository
+	suite.service.cfg.Idempotency = config.IdempotencyConfig{Window: 600, KeyTtl: 86400}
+
+	err := suite.service.CreateFile(context.TODO(), report, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusOk, res.Status)
+	assert.Equal(suite.T(), job.Id.Hex(), res.FileId)
+	reportFileRepository.AssertNotCalled(suite.T(), "Insert", mock.Anything, mock.Anything)
+}
+
+func (suite *ReportTestSuite) TestReport_CreateFile_Error_IdempotencyKeyConflict() {
+	res := &reporterpb.CreateFileResponse{}
+	params, _ := json.Marshal(map[string]interface{}{reporterpb.ParamsFieldCountry: "RU"})
+	report := &reporterpb.ReportFile{
+		ReportType:     reporterpb.ReportTypeVat,
+		FileType:       reporterpb.OutputExtensionPdf,
+		MerchantId:     "ffffffffffffffffffffffff",
+		Params:         params,
+		IdempotencyKey: "dashboard-click-1",
+	}
+	job := &proto.ReportFileJob{
+		Id:     primitive.NewObjectID(),
+		Status: pkg.ReportFileStatusCompleted,
+		ParamsFingerprint: getReportFileParamsFingerprint(&reporterpb.ReportFile{
+			ReportType: reporterpb.ReportTypeVat,
+			FileType:   reporterpb.OutputExtensionPdf,
+			MerchantId: report.MerchantId,
+			Params:     []byte(`{"country":"DE"}`),
+		}),
+	}
+
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.
+		On("FindByFingerprint", mock.Anything, report.MerchantId, getReportFileFingerprint(report), mock.Anything).
+		Return(job, nil)
+	suite.service.reportFileRepository = reportFileRepository
+	suite.service.cfg.Idempotency = config.IdempotencyConfig{Window: 600, KeyTtl: 86400}
+
+	err := suite.service.CreateFile(context.TODO(), report, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusConflict, res.Status)
+	assert.Equal(suite.T(), errors.ErrorIdempotencyKeyConflict, res.Message)
+	assert.Empty(suite.T(), res.FileId)
+	reportFileRepository.AssertNotCalled(suite.T(), "Insert", mock.Anything, mock.Anything)
+}
+
+func (suite *ReportTestSuite) TestReport_CreateFile_Ok_InteractiveLane() {
+	res := &reporterpb.CreateFileResponse{}
+	params, _ := json.Marshal(map[string]interface{}{reporterpb.ParamsFieldCountry: "RU"})
+	report := &reporterpb.ReportFile{
+		ReportType: reporterpb.ReportTypeVat,
+		FileType:   reporterpb.OutputExtensionPdf,
+		MerchantId: "ffffffffffffffffffffffff",
+		Params:     params,
+		Priority:   pkg.ReportFilePriorityInteractive,
+	}
+
+	broker := &rabbitmqMock.BrokerInterface{}
+	broker.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	suite.service.generateReportBroker = broker
+
+	interactiveBroker := &rabbitmqMock.BrokerInterface{}
+	interactiveBroker.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	suite.service.generateInteractiveBroker = interactiveBroker
+
+	err := suite.service.CreateFile(context.TODO(), report, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusOk, res.Status)
+	interactiveBroker.AssertCalled(suite.T(), "Publish", pkg.BrokerGenerateReportInteractiveTopicName, report, mock.Anything)
+	broker.AssertNotCalled(suite.T(), "Publish", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *ReportTestSuite) TestReport_CreateFile_Error_PriorityInvalid() {
+	res := &reporterpb.CreateFileResponse{}
+	report := &reporterpb.ReportFile{
+		ReportType: reporterpb.ReportTypeVat,
+		FileType:   reporterpb.OutputExtensionPdf,
+		MerchantId: "ffffffffffffffffffffffff",
+		Priority:   "urgent",
+	}
+
+	err := suite.service.CreateFile(context.TODO(), report, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusBadData, res.Status)
+	assert.Equal(suite.T(), errors.ErrorReportFilePriorityInvalid, res.Message)
+}
+
+func (suite *ReportTestSuite) TestReport_GetReportFilePriority() {
+	assert.Equal(
+		suite.T(),
+		pkg.ReportFilePriorityInteractive,
+		getReportFilePriority(&reporterpb.ReportFile{ReportType: reporterpb.ReportTypeAgreement}),
+	)
+	assert.Equal(
+		suite.T(),
+		pkg.ReportFilePriorityInteractive,
+		getReportFilePriority(&reporterpb.ReportFile{ReportType: reporterpb.ReportTypePayout}),
+	)
+	assert.Equal(
+		suite.T(),
+		pkg.ReportFilePriorityBulk,
+		getReportFilePriority(&reporterpb.ReportFile{ReportType: reporterpb.ReportTypeTransactions}),
+	)
+	assert.Equal(
+		suite.T(),
+		pkg.ReportFilePriorityBulk,
+		getReportFilePriority(&reporterpb.ReportFile{ReportType: reporterpb.ReportTypeAgreement, Priority: pkg.ReportFilePriorityBulk}),
+	)
+	assert.Equal(suite.T(), pkg.ReportFilePriorityBulk, getReportFilePriority(&reporterpb.ReportFile{ReportType: "unknown"}))
+}
+
+func (suite *ReportTestSuite) TestReport_GetFile_Ok() {
+	job := suite.getReportFileJobTemplate()
+	expiresAt := time.Now().Add(time.Hour)
+	job.ExpiresAt = &expiresAt
+
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.On("GetById", mock.Anything, job.Id.Hex()).Return(job, nil)
+	suite.service.reportFileRepository = reportFileRepository
+
+	req := &reporterpb.GetFileRequest{FileId: job.Id.Hex(), MerchantId: job.MerchantId}
+	res := &reporterpb.GetFileResponse{}
+	err := suite.service.GetFile(context.TODO(), req, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusOk, res.Status)
+	assert.NotNil(suite.T(), res.Item)
+	assert.Equal(suite.T(), job.Id.Hex(), res.Item.Id)
+	assert.Equal(suite.T(), job.Status, res.Item.Status)
+	assert.Equal(suite.T(), job.FileName, res.Item.FileName)
+	assert.Equal(suite.T(), job.Size, res.Item.Size)
+	assert.NotNil(suite.T(), res.Item.ExpiresAt)
+	assert.Nil(suite.T(), res.Item.Error)
+}
+
+func (suite *ReportTestSuite) TestReport_GetFile_Failed_WithError() {
+	job := suite.getReportFileJobTemplate()
+	job.Status = pkg.ReportFileStatusFailed
+	job.LastError = &proto.ReportFileJobError{
+		Code:    errors.ErrorDocumentGeneratorRender.Code,
+		Message: errors.ErrorDocumentGeneratorRender.Message,
+		Details: "connection refused",
+	}
+
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.On("GetById", mock.Anything, job.Id.Hex()).Return(job, nil)
+	suite.service.reportFileRepository = reportFileRepository
+
+	res := &reporterpb.GetFileResponse{}
+	err := suite.service.GetFile(context.TODO(), &reporterpb.GetFileRequest{FileId: job.Id.Hex(), MerchantId: job.MerchantId}, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusOk, res.Status)
+	assert.Equal(suite.T(), pkg.ReportFileStatusFailed, res.Item.Status)
+	assert.Equal(suite.T(), errors.ErrorDocumentGeneratorRender, res.Item.Error)
+}
+
+func (suite *ReportTestSuite) TestReport_GetFile_Error_InvalidId() {
+	res := &reporterpb.GetFileResponse{}
+	err := suite.service.GetFile(context.TODO(), &reporterpb.GetFileRequest{FileId: "invalid", MerchantId: "ffffffffffffffffffffffff"}, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusNotFound, res.Status)
+	assert.Equal(suite.T(), errors.ErrorReportFileNotFound, res.Message)
+	assert.Nil(suite.T(), res.Item)
+}
+
+func (suite *ReportTestSuite) TestReport_GetFile_Error_NotFound() {
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.On("GetById", mock.Anything, mock.Anything).Return(nil, mongo.ErrNoDocuments)
+	suite.service.reportFileRepository = reportFileRepository
+
+	req := &reporterpb.GetFileRequest{FileId: primitive.NewObjectID().Hex(), MerchantId: "ffffffffffffffffffffffff"}
+	res := &reporterpb.GetFileResponse{}
+	err := suite.service.GetFile(context.TODO(), req, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusNotFound, res.Status)
+	assert.Equal(suite.T(), errors.ErrorReportFileNotFound, res.Message)
+}
+
+func (suite *ReportTestSuite) TestReport_GetFile_Error_Database() {
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.On("GetById", mock.Anything, mock.Anything).Return(nil, errs.New("error"))
+	suite.service.reportFileRepository = reportFileRepository
+
+	req := &reporterpb.GetFileRequest{FileId: primitive.NewObjectID().Hex(), MerchantId: "ffffffffffffffffffffffff"}
+	res := &reporterpb.GetFileResponse{}
+	err := suite.service.GetFile(context.TODO(), req, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusSystemError, res.Status)
+	assert.Equal(suite.T(), errors.ErrorDatabaseQueryFailed, res.Message)
+}
+
+func (suite *ReportTestSuite) TestReport_GetFile_Error_MerchantId() {
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	suite.service.reportFileRepository = reportFileRepository
+
+	res := &reporterpb.GetFileResponse{}
+	err := suite.service.GetFile(context.TODO(), &reporterpb.GetFileRequest{FileId: primitive.NewObjectID().Hex()}, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusBadData, res.Status)
+	assert.Equal(suite.T(), errors.ErrorParamMerchantIdNotFound, res.Message)
+	reportFileRepository.AssertNotCalled(suite.T(), "GetById", mock.Anything, mock.Anything)
+}
+
+func (suite *ReportTestSuite) TestReport_GetFile_Error_OtherMerchant() {
+	job := suite.getReportFileJobTemplate()
+
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.On("GetById", mock.Anything, job.Id.Hex()).Return(job, nil)
+	suite.service.reportFileRepository = reportFileRepository
+
+	req := &reporterpb.GetFileRequest{FileId: job.Id.Hex(), MerchantId: primitive.NewObjectID().Hex()}
+	res := &reporterpb.GetFileResponse{}
+	err := suite.service.GetFile(context.TODO(), req, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusNotFound, res.Status)
+	assert.Equal(suite.T(), errors.ErrorReportFileNotFound, res.Message)
+	assert.Nil(suite.T(), res.Item)
+}
+
+func (suite *ReportTestSuite) TestReport_ListFiles_Ok() {
+	jobs := []*proto.ReportFileJob{suite.getReportFileJobTemplate(), suite.getReportFileJobTemplate()}
+
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.On("FindCount", mock.Anything, jobs[0].MerchantId, "", reporterpb.ReportTypeVat).
+		Return(int64(5), nil)
+	reportFileRepository.On("Find", mock.Anything, jobs[0].MerchantId, "", reporterpb.ReportTypeVat, int64(2), int64(2)).
+		Return(jobs, nil)
+	suite.service.reportFileRepository = reportFileRepository
+
+	req := &reporterpb.ListFilesRequest{
+		MerchantId: jobs[0].MerchantId,
+		ReportType: reporterpb.ReportTypeVat,
+		Limit:      2,
+		Offset:     2,
+	}
+	res := &reporterpb.ListFilesResponse{}
+	err := suite.service.ListFiles(context.TODO(), req, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusOk, res.Status)
+	assert.EqualValues(suite.T(), 5, res.Item.Count)
+	assert.Len(suite.T(), res.Item.Items, 2)
+	assert.Equal(suite.T(), jobs[1].Id.Hex(), res.Item.Items[1].Id)
+}
+
+func (suite *ReportTestSuite) TestReport_ListFiles_DefaultLimit() {
+	merchantId := primitive.NewObjectID().Hex()
+
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.On("FindCount", mock.Anything, merchantId, "", "").Return(int64(1), nil)
+	reportFileRepository.On("Find", mock.Anything, merchantId, "", "", int64(0), pkg.ListFilesDefaultLimit).
+		Return([]*proto.ReportFileJob{suite.getReportFileJobTemplate()}, nil)
+	suite.service.reportFileRepository = reportFileRepository
+
+	res := &reporterpb.ListFilesResponse{}
+	err := suite.service.ListFiles(context.TODO(), &reporterpb.ListFilesRequest{MerchantId: merchantId}, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusOk, res.Status)
+	assert.Len(suite.T(), res.Item.Items, 1)
+	reportFileRepository.AssertExpectations(suite.T())
+}
+
+func (suite *ReportTestSuite) TestReport_ListFiles_MaxLimit() {
+	merchantId := primitive.NewObjectID().Hex()
+
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.On("FindCount", mock.Anything, merchantId, "", "").Return(int64(1), nil)
+	reportFileRepository.On("Find", mock.Anything, merchantId, "", "", int64(0), pkg.ListFilesMaxLimit).
+		Return([]*proto.ReportFileJob{}, nil)
+	suite.service.reportFileRepository = reportFileRepository
+
+	req := &reporterpb.ListFilesRequest{MerchantId: merchantId, Limit: pkg.ListFilesMaxLimit + 1}
+	res := &reporterpb.ListFilesResponse{}
+	err := suite.service.ListFiles(context.TODO(), req, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusOk, res.Status)
+	reportFileRepository.AssertExpectations(suite.T())
+}
+
+func (suite *ReportTestSuite) TestReport_ListFiles_Empty() {
+	merchantId := primitive.NewObjectID().Hex()
+
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.On("FindCount", mock.Anything, merchantId, "", "").Return(int64(0), nil)
+	suite.service.reportFileRepository = reportFileRepository
+
+	res := &reporterpb.ListFilesResponse{}
+	err := suite.service.ListFiles(context.TODO(), &reporterpb.ListFilesRequest{MerchantId: merchantId}, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusOk, res.Status)
+	assert.EqualValues(suite.T(), 0, res.Item.Count)
+	assert.Empty(suite.T(), res.Item.Items)
+	reportFileRepository.AssertNotCalled(suite.T(), "Find", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *ReportTestSuite) TestReport_ListFiles_Error_MerchantId() {
+	res := &reporterpb.ListFilesResponse{}
+	err := suite.service.ListFiles(context.TODO(), &reporterpb.ListFilesRequest{}, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusBadData, res.Status)
+	assert.Equal(suite.T(), errors.ErrorParamMerchantIdNotFound, res.Message)
+}
+
+func (suite *ReportTestSuite) TestReport_ListFiles_Error_Database() {
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.On("FindCount", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(int64(0), errs.New("error"))
+	suite.service.reportFileRepository = reportFileRepository
+
+	req := &reporterpb.ListFilesRequest{MerchantId: primitive.NewObjectID().Hex()}
+	res := &reporterpb.ListFilesResponse{}
+	err := suite.service.ListFiles(context.TODO(), req, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusSystemError, res.Status)
+	assert.Equal(suite.T(), errors.ErrorDatabaseQueryFailed, res.Message)
+	assert.Nil(suite.T(), res.Item)
+}
+
+func (suite *ReportTestSuite) TestReport_GetFileDownloadUrl_Ok() {
+	job := suite.getReportFileJobTemplate()
+	expiresAt := time.Now().Add(30 * time.Minute)
+	job.ExpiresAt = &expiresAt
+	suite.service.cfg.S3.DownloadUrlTtl = 3600
+
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.On("GetById", mock.Anything, job.Id.Hex()).Return(job, nil)
+	suite.service.reportFileRepository = reportFileRepository
+
+	storage := &mocks.StorageInterface{}
+	storage.On("Presign", job.FileName, job.ContentType, mock.MatchedBy(func(expire time.Duration) bool {
+		return expire > 29*time.Minute && expire <= 30*time.Minute
+	})).Return("https://bucket/report.pdf", nil)
+	suite.setStorages(storage, &mocks.StorageInterface{})
+
+	req := &reporterpb.GetFileDownloadUrlRequest{FileId: job.Id.Hex(), MerchantId: job.MerchantId}
+	res := &reporterpb.GetFileDownloadUrlResponse{}
+	err := suite.service.GetFileDownloadUrl(context.TODO(), req, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusOk, res.Status)
+	assert.Equal(suite.T(), "https://bucket/report.pdf", res.Item.Url)
+	assert.NotNil(suite.T(), res.Item.ExpiresAt)
+	storage.AssertExpectations(suite.T())
+}
+
+func (suite *ReportTestSuite) TestReport_GetFileDownloadUrl_RequestedTtl() {
+	job := suite.getReportFileJobTemplate()
+	suite.service.cfg.S3.DownloadUrlTtl = 3600
+
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.On("GetById", mock.Anything, job.Id.Hex()).Return(job, nil)
+	suite.service.reportFileRepository = reportFileRepository
+
+	storage := &mocks.StorageInterface{}
+	storage.On("Presign", job.FileName, job.ContentType, 5*time.Minute).Return("https://bucket/report.pdf", nil)
+	suite.setStorages(storage, &mocks.StorageInterface{})
+
+	req := &reporterpb.GetFileDownloadUrlRequest{FileId: job.Id.Hex(), MerchantId: job.MerchantId, Ttl: 300}
+	res := &reporterpb.GetFileDownloadUrlResponse{}
+	err := suite.service.GetFileDownloadUrl(context.TODO(), req, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusOk, res.Status)
+	storage.AssertExpectations(suite.T())
+}
+
+func (suite *ReportTestSuite) TestReport_GetFileDownloadUrl_Agreement() {
+	job := suite.getReportFileJobTemplate()
+	job.ReportType = reporterpb.ReportTypeAgreement
+	suite.service.cfg.S3.DownloadUrlTtl = 3600
+
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.On("GetById", mock.Anything, job.Id.Hex()).Return(job, nil)
+	suite.service.reportFileRepository = reportFileRepository
+
+	storage := &mocks.StorageInterface{}
+	agreementStorage := &mocks.StorageInterface{}
+	agreementStorage.On("Presign", job.FileName, job.ContentType, time.Hour).Return("https://agreement/report.pdf", nil)
+	suite.setStorages(storage, agreementStorage)
+
+	req := &reporterpb.GetFileDownloadUrlRequest{FileId: job.Id.Hex(), MerchantId: job.MerchantId}
+	res := &reporterpb.GetFileDownloadUrlResponse{}
+	err := suite.service.GetFileDownloadUrl(context.TODO(), req, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusOk, res.Status)
+	assert.Equal(suite.T(), "https://agreement/report.pdf", res.Item.Url)
+	storage.AssertNotCalled(suite.T(), "Presign", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *ReportTestSuite) TestReport_GetFileDownloadUrl_Bucket() {
+	job := suite.getReportFileJobTemplate()
+	job.ReportType = reporterpb.ReportTypeRoyalty
+	job.Bucket = "agreements"
+	suite.service.cfg.S3.DownloadUrlTtl = 3600
+
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.On("GetById", mock.Anything, job.Id.Hex()).Return(job, nil)
+	suite.service.reportFileRepository = reportFileRepository
+
+	storage := &mocks.StorageInterface{}
+	storage.On("Bucket").Return("reports")
+	agreementStorage := &mocks.StorageInterface{}
+	agreementStorage.On("Bucket").Return("agreements")
+	agreementStorage.On("Presign", job.FileName, job.ContentType, time.Hour).Return("https://agreement/report.pdf", nil)
+	suite.setStorages(storage, agreementStorage)
+
+	req := &reporterpb.GetFileDownloadUrlRequest{FileId: job.Id.Hex(), MerchantId: job.MerchantId}
+	res := &reporterpb.GetFileDownloadUrlResponse{}
+	err := suite.service.GetFileDownloadUrl(context.TODO(), req, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusOk, res.Status)
+	assert.Equal(suite.T(), "https://agreement/report.pdf", res.Item.Url)
+	storage.AssertNotCalled(suite.T(), "Presign", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *ReportTestSuite) TestReport_GetFileDownloadUrl_Error_MerchantId() {
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	suite.service.reportFileRepository = reportFileRepository
+
+	req := &reporterpb.GetFileDownloadUrlRequest{FileId: primitive.NewObjectID().Hex()}
+	res := &reporterpb.GetFileDownloadUrlResponse{}
+	err := suite.service.GetFileDownloadUrl(context.TODO(), req, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusBadData, res.Status)
+	assert.Equal(suite.T(), errors.ErrorParamMerchantIdNotFound, res.Message)
+	reportFileRepository.AssertNotCalled(suite.T(), "GetById", mock.Anything, mock.Anything)
+}
+
+func (suite *ReportTestSuite) TestReport_GetFileDownloadUrl_Error_NotFound() {
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.On("GetById", mock.Anything, mock.Anything).Return(nil, mongo.ErrNoDocuments)
+	suite.service.reportFileRepository = reportFileRepository
+
+	req := &reporterpb.GetFileDownloadUrlRequest{FileId: primitive.NewObjectID().Hex(), MerchantId: "ffffffffffffffffffffffff"}
+	res := &reporterpb.GetFileDownloadUrlResponse{}
+	err := suite.service.GetFileDownloadUrl(context.TODO(), req, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusNotFound, res.Status)
+	assert.Equal(suite.T(), errors.ErrorReportFileNotFound, res.Message)
+}
+
+func (suite *ReportTestSuite) TestReport_GetFileDownloadUrl_Error_NotReady() {
+	job := suite.getReportFileJobTemplate()
+	job.Status = pkg.ReportFileStatusRendering
+	job.FileName = ""
+
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.On("GetById", mock.Anything, job.Id.Hex()).Return(job, nil)
+	suite.service.reportFileRepository = reportFileRepository
+
+	req := &reporterpb.GetFileDownloadUrlRequest{FileId: job.Id.Hex(), MerchantId: job.MerchantId}
+	res := &reporterpb.GetFileDownloadUrlResponse{}
+	err := suite.service.GetFileDownloadUrl(context.TODO(), req, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusBadData, res.Status)
+	assert.Equal(suite.T(), errors.ErrorReportFileNotReady, res.Message)
+}
+
+func (suite *ReportTestSuite) TestReport_GetFileDownloadUrl_Error_Expired() {
+	job := suite.getReportFileJobTemplate()
+	expiresAt := time.Now().Add(-time.Minute)
+	job.ExpiresAt = &expiresAt
+
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.On("GetById", mock.Anything, job.Id.Hex()).Return(job, nil)
+	suite.service.reportFileRepository = reportFileRepository
+
+	req := &reporterpb.GetFileDownloadUrlRequest{FileId: job.Id.Hex(), MerchantId: job.MerchantId}
+	res := &reporterpb.GetFileDownloadUrlResponse{}
+	err := suite.service.GetFileDownloadUrl(context.TODO(), req, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusNotFound, res.Status)
+	assert.Equal(suite.T(), errors.ErrorReportFileExpired, res.Message)
+}
+
+func (suite *ReportTestSuite) TestReport_GetFileDownloadUrl_Error_ExpiredStatus() {
+	job := suite.getReportFileJobTemplate()
+	job.Status = pkg.ReportFileStatusExpired
+
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.On("GetById", mock.Anything, job.Id.Hex()).Return(job, nil)
+	suite.service.reportFileRepository = reportFileRepository
+
+	req := &reporterpb.GetFileDownloadUrlRequest{FileId: job.Id.Hex(), MerchantId: job.MerchantId}
+	res := &reporterpb.GetFileDownloadUrlResponse{}
+	err := suite.service.GetFileDownloadUrl(context.TODO(), req, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusNotFound, res.Status)
+	assert.Equal(suite.T(), errors.ErrorReportFileExpired, res.Message)
+}
+
+func (suite *ReportTestSuite) TestReport_GetFileDownloadUrl_Error_Presign() {
+	job := suite.getReportFileJobTemplate()
+	suite.service.cfg.S3.DownloadUrlTtl = 3600
+
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.On("GetById", mock.Anything, job.Id.Hex()).Return(job, nil)
+	suite.service.reportFileRepository = reportFileRepository
+
+	storage := &mocks.StorageInterface{}
+	storage.On("Presign", mock.Anything, mock.Anything, mock.Anything).Return("", errs.New("error"))
+	suite.setStorages(storage, &mocks.StorageInterface{})
+
+	req := &reporterpb.GetFileDownloadUrlRequest{FileId: job.Id.Hex(), MerchantId: job.MerchantId}
+	res := &reporterpb.GetFileDownloadUrlResponse{}
+	err := suite.service.GetFileDownloadUrl(context.TODO(), req, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusSystemError, res.Status)
+	assert.Equal(suite.T(), errors.ErrorDownloadUrlFailed, res.Message)
+	assert.Nil(suite.T(), res.Item)
+}
+
+func (suite *ReportTestSuite) TestReport_GetFileDownloadUrl_Error_Cancelled() {
+	job := suite.getReportFileJobTemplate()
+	job.Status = pkg.ReportFileStatusCancelled
+
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.On("GetById", mock.Anything, job.Id.Hex()).Return(job, nil)
+	suite.service.reportFileRepository = reportFileRepository
+
+	req := &reporterpb.GetFileDownloadUrlRequest{FileId: job.Id.Hex(), MerchantId: job.MerchantId}
+	res := &reporterpb.GetFileDownloadUrlResponse{}
+	err := suite.service.GetFileDownloadUrl(context.TODO(), req, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusNotFound, res.Status)
+	assert.Equal(suite.T(), errors.ErrorReportFileNotFound, res.Message)
+}
+
+func (suite *ReportTestSuite) TestReport_GetFileDownloadUrl_Error_Erased() {
+	job := suite.getReportFileJobTemplate()
+	job.Status = pkg.ReportFileStatusErased
+	job.FileName = ""
+
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.On("GetById", mock.Anything, job.Id.Hex()).Return(job, nil)
+	suite.service.reportFileRepository = reportFileRepository
+
+	req := &reporterpb.GetFileDownloadUrlRequest{FileId: job.Id.Hex(), MerchantId: job.MerchantId}
+	res := &reporterpb.GetFileDownloadUrlResponse{}
+	err := suite.service.GetFileDownloadUrl(context.TODO(), req, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusNotFound, res.Status)
+	assert.Equal(suite.T(), errors.ErrorReportFileNotFound, res.Message)
+}
+
+func (suite *ReportTestSuite) TestReport_CancelFile_Ok() {
+	job := suite.getReportFileJobTemplate()
+	job.Status = pkg.ReportFileStatusRendering
+	job.FileName = ""
+
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.On("GetById", mock.Anything, job.Id.Hex()).Return(job, nil)
+	reportFileRepository.On("Cancel", mock.Anything, job.Id.Hex()).Return(nil)
+	suite.service.reportFileRepository = reportFileRepository
+
+	storage := &mocks.StorageInterface{}
+	suite.setStorages(storage, &mocks.StorageInterface{})
+
+	req := &reporterpb.CancelFileRequest{FileId: job.Id.Hex(), MerchantId: job.MerchantId}
+	res := &reporterpb.CancelFileResponse{}
+	err := suite.service.CancelFile(context.TODO(), req, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusOk, res.Status)
+	reportFileRepository.AssertCalled(suite.T(), "Cancel", mock.Anything, job.Id.Hex())
+	storage.AssertNotCalled(suite.T(), "Delete", mock.Anything, mock.Anything)
+}
+
+func (suite *ReportTestSuite) TestReport_CancelFile_Uploaded_DeleteFile() {
+	job := suite.getReportFileJobTemplate()
+	job.Status = pkg.ReportFileStatusPostProcessing
+
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.On("GetById", mock.Anything, job.Id.Hex()).Return(job, nil)
+	reportFileRepository.On("Cancel", mock.Anything, job.Id.Hex()).Return(nil)
+	suite.service.reportFileRepository = reportFileRepository
+
+	storage := &mocks.StorageInterface{}
+	storage.On("Delete", mock.Anything, job.FileName).Return(nil)
+	suite.setStorages(storage, &mocks.StorageInterface{})
+
+	res := &reporterpb.CancelFileResponse{}
+	err := suite.service.CancelFile(context.TODO(), &reporterpb.CancelFileRequest{FileId: job.Id.Hex(), MerchantId: job.MerchantId}, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusOk, res.Status)
+	storage.AssertExpectations(suite.T())
+}
+
+func (suite *ReportTestSuite) TestReport_CancelFile_AlreadyCancelled() {
+	job := suite.getReportFileJobTemplate()
+	job.Status = pkg.ReportFileStatusCancelled
+
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.On("GetById", mock.Anything, job.Id.Hex()).Return(job, nil)
+	suite.service.reportFileRepository = reportFileRepository
+
+	res := &reporterpb.CancelFileResponse{}
+	err := suite.service.CancelFile(context.TODO(), &reporterpb.CancelFileRequest{FileId: job.Id.Hex(), MerchantId: job.MerchantId}, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusOk, res.Status)
+	reportFileRepository.AssertNotCalled(suite.T(), "Cancel", mock.Anything, mock.Anything)
+}
+
+func (suite *ReportTestSuite) TestReport_CancelFile_Error_Completed() {
+	job := suite.getReportFileJobTemplate()
+
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.On("GetById", mock.Anything, job.Id.Hex()).Return(job, nil)
+	suite.service.reportFileRepository = reportFileRepository
+
+	res := &reporterpb.CancelFileResponse{}
+	err := suite.service.CancelFile(context.TODO(), &reporterpb.CancelFileRequest{FileId: job.Id.Hex(), MerchantId: job.MerchantId}, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusBadData, res.Status)
+	assert.Equal(suite.T(), errors.ErrorReportFileCancelNotAllowed, res.Message)
+}
+
+func (suite *ReportTestSuite) TestReport_CancelFile_Error_Expired() {
+	job := suite.getReportFileJobTemplate()
+	job.Status = pkg.ReportFileStatusExpired
+
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.On("GetById", mock.Anything, job.Id.Hex()).Return(job, nil)
+	suite.service.reportFileRepository = reportFileRepository
+
+	res := &reporterpb.CancelFileResponse{}
+	err := suite.service.CancelFile(context.TODO(), &reporterpb.CancelFileRequest{FileId: job.Id.Hex(), MerchantId: job.MerchantId}, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusBadData, res.Status)
+	assert.Equal(suite.T(), errors.ErrorReportFileCancelNotAllowed, res.Message)
+	reportFileRepository.AssertNotCalled(suite.T(), "Cancel", mock.Anything, mock.Anything)
+}
+
+func (suite *ReportTestSuite) TestReport_CancelFile_Error_Erased() {
+	job := suite.getReportFileJobTemplate()
+	job.Status = pkg.ReportFileStatusErased
+
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.On("GetById", mock.Anything, job.Id.Hex()).Return(job, nil)
+	suite.service.reportFileRepository = reportFileRepository
+
+	res := &reporterpb.CancelFileResponse{}
+	err := suite.service.CancelFile(context.TODO(), &reporterpb.CancelFileRequest{FileId: job.Id.Hex(), MerchantId: job.MerchantId}, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusNotFound, res.Status)
+	assert.Equal(suite.T(), errors.ErrorReportFileNotFound, res.Message)
+	reportFileRepository.AssertNotCalled(suite.T(), "Cancel", mock.Anything, mock.Anything)
+}
+
+func (suite *ReportTestSuite) TestReport_CancelFile_Error_CompletedConcurrently() {
+	job := suite.getReportFileJobTemplate()
+	job.Status = pkg.ReportFileStatusUploaded
+
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.On("GetById", mock.Anything, job.Id.Hex()).Return(job, nil)
+	reportFileRepository.On("Cancel", mock.Anything, job.Id.Hex()).Return(mongo.ErrNoDocuments)
+	suite.service.reportFileRepository = reportFileRepository
+
+	res := &reporterpb.CancelFileResponse{}
+	err := suite.service.CancelFile(context.TODO(), &reporterpb.CancelFileRequest{FileId: job.Id.Hex(), MerchantId: job.MerchantId}, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusBadData, res.Status)
+	assert.Equal(suite.T(), errors.ErrorReportFileCancelNotAllowed, res.Message)
+}
+
+func (suite *ReportTestSuite) TestReport_CancelFile_Error_Database() {
+	job := suite.getReportFileJobTemplate()
+	job.Status = pkg.ReportFileStatusQueued
+
+	reportFileRepository := &mocks.ReportFileRepositoryInterface{}
+	reportFileRepository.On("GetById", mock.Anything, job.Id.Hex()).Return(job, nil)
+	reportFileRepository.On("Cancel", mock.Anything, job.Id.Hex()).Return(errs.New("error"))
+	suite.service.reportFileRepository = reportFileRepository
+
+	res := &reporterpb.CancelFileResponse{}
+	err := suite.service.CancelFile(context.TODO(), &reporterpb.CancelFileRequest{FileId: job.Id.Hex(), MerchantId: job.MerchantId}, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusSystemError, res.Status)
+	assert.Equal(suite.T(), errors.ErrorDatabaseQueryFailed, res.Message)
+}
+
+func (suite *ReportTestSuite) TestReport_CancelFile_Error_NotFound() {
+	res := &reporterpb.CancelFileResponse{}
+	err := suite.service.CancelFile(context.TODO(), &reporterpb.CancelFileRequest{FileId: "invalid", MerchantId: "ffffffffffffffffffffffff"}, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pkg.ResponseStatusNotFound, res.Status)
+	assert.Equal(suite.T(), errors.ErrorReportFileNotFound, res.Message)
+}
+
 func (suite *ReportTestSuite) TestReport_getTemplate_NotEmptyTemplate() {
 	report := &reporterpb.ReportFile{Template: "test"}
 	name, err := suite.service.getTemplate(report)
@@ -168,3 +1127,33 @@ func (suite *ReportTestSuite) TestReport_getTemplate_DefaultTransactionsTemplate
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), "transactions", name)
 }
+
+func (suite *ReportTestSuite) getReportFileJobTemplate() *proto.ReportFileJob {
+	return &proto.ReportFileJob{
+		Id:          primitive.NewObjectID(),
+		UserId:      "ffffffffffffffffffffffff",
+		MerchantId:  "ffffffffffffffffffffffff",
+		ReportType:  reporterpb.ReportTypeVat,
+		FileType:    reporterpb.OutputExtensionPdf,
+		Status:      pkg.ReportFileStatusCompleted,
+		FileName:    "report.pdf",
+		Size:        1024,
+		ContentType: pkg.OutputContentTypePdf,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+}
+
+func (suite *ReportTestSuite) setStorages(storage, agreementStorage StorageInterface) {
+	storages := map[string]StorageInterface{
+		pkg.StorageTargetReports:    storage,
+		pkg.StorageTargetAgreements: agreementStorage,
+	}
+	routes := config.StorageRoutes{
+		{ReportType: reporterpb.ReportTypeAgreement, Target: pkg.StorageTargetAgreements, Expiry: pkg.StorageExpiryNone},
+	}
+	router, err := newStorageRouter(storages, routes)
+	assert.NoError(suite.T(), err)
+
+	suite.service.storages = router
+}
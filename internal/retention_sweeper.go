@@ -0,0 +1,316 @@
+package internal
+
+import (
+	"context"
+	"github.com/paysuper/paysuper-reporter/internal/config"
+	"github.com/paysuper/paysuper-reporter/pkg/proto"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	retentionStageStorage = "storage"
+	retentionStageDelete  = "delete"
+	retentionStageUpdate  = "update"
+	retentionStageHold    = "hold"
+)
+
+var (
+	retentionPurgedFiles = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "reporter_retention_purged_files_total",
+			Help: "Count of the report files purged by the retention sweeper.",
+		},
+		[]string{"report_type", "dry_run"},
+	)
+	retentionPurgedBytes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "reporter_retention_purged_bytes_total",
+			Help: "Size of the report files purged by the retention sweeper in bytes.",
+		},
+		[]string{"report_type", "dry_run"},
+	)
+	retentionErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "reporter_retention_errors_total",
+			Help: "Count of the report files the retention sweeper failed to purge.",
+		},
+		[]string{"stage"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(retentionPurgedFiles, retentionPurgedBytes, retentionErrors)
+}
+
+// RetentionSweepResult is the count and the size of the report files purged by the sweep and the count of
+// the files failed to purge.
+type RetentionSweepResult struct {
+	Files  int64
+	Bytes  int64
+	Errors int64
+}
+
+// RetentionSweeper deletes the report files whose retention time has expired from the storages and marks
+// their jobs as expired. The file is deleted before the job is updated, so the failed sweep is repeated
+// on the next run. Files under the legal hold are skipped until the hold is released. In the dry run mode
+// the expired files are only logged and counted.
+type RetentionSweeper struct {
+	cfg        *config.RetentionConfig
+	repository ReportFileRepositoryInterface
+	holds      LegalHoldRepositoryInterface
+	storages   *StorageRouter
+	now        func() time.Time
+
+	started bool
+	stopped bool
+	stop    chan struct{}
+	done    chan struct{}
+	mx      sync.Mutex
+}
+
+func newRetentionSweeper(
+	cfg *config.RetentionConfig,
+	repository ReportFileRepositoryInterface,
+	holds LegalHoldRepositoryInterface,
+	storages *StorageRouter,
+) *RetentionSweeper {
+	return &RetentionSweeper{
+		cfg:        cfg,
+		repository: repository,
+		holds:      holds,
+		storages:   storages,
+		now:        time.Now,
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+}
+
+// Start sweeps the expired report files in background every interval, zero interval disables the sweeper.
+func (s *RetentionSweeper) Start(ctx context.Context) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if s.cfg.SweepInterval <= 0 || s.started || s.stopped {
+		return
+	}
+
+	s.started = true
+	go s.run(ctx)
+}
+
+// Stop stops the sweeper and waits for the sweep in progress.
+func (s *RetentionSweeper) Stop() {
+	s.mx.Lock()
+
+	if s.stopped {
+		s.mx.Unlock()
+		return
+	}
+
+	s.stopped = true
+	close(s.stop)
+	started := s.started
+	s.mx.Unlock()
+
+	if started {
+		<-s.done
+	}
+}
+
+func (s *RetentionSweeper) run(ctx context.Context) {
+	defer close(s.done)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		select {
+		case <-s.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	ticker := time.NewTicker(time.Duration(s.cfg.SweepInterval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		res, err := s.Sweep(ctx)
+
+		if err != nil && ctx.Err() == nil {
+			zap.L().Error("Retention sweep failed", zap.Error(err))
+		}
+
+		zap.L().Info(
+			"Retention sweep finished",
+			zap.Int64("files", res.Files),
+			zap.Int64("bytes", res.Bytes),
+			zap.Int64("errors", res.Errors),
+			zap.Bool("dry_run", s.cfg.DryRun),
+		)
+	}
+}
+
+// Sweep purges the expired report files by batches until no expired files are left. The dry run processes
+// the single batch, because nothing is purged and the same files would be found again.
+func (s *RetentionSweeper) Sweep(ctx context.Context) (*RetentionSweepResult, error) {
+	res := &RetentionSweepResult{}
+	query, err := s.getExpiredQuery(ctx)
+
+	if err != nil {
+		return res, err
+	}
+
+	for {
+		query.ExpiredAt = s.now()
+		jobs, err := s.repository.FindExpired(ctx, query)
+
+		if err != nil {
+			return res, err
+		}
+
+		for _, job := range jobs {
+			if err = ctx.Err(); err != nil {
+				return res, err
+			}
+
+			held, err := s.isHeld(ctx, job)
+
+			if err != nil || held {
+				query.ExcludeIds = append(query.ExcludeIds, job.Id)
+
+				if err != nil {
+					res.Errors++
+				}
+
+				continue
+			}
+
+			if !s.purge(ctx, job) {
+				// Files failed to purge are retried by the next sweep, not by the next batch of this one
+				query.ExcludeIds = append(query.ExcludeIds, job.Id)
+				res.Errors++
+				continue
+			}
+
+			res.Files++
+			res.Bytes += job.Size
+		}
+
+		if s.cfg.DryRun || int64(len(jobs)) < query.Limit {
+			return res, nil
+		}
+	}
+}
+
+// getExpiredQuery returns the query of the expired jobs excluding the jobs under the active legal holds. Holds
+// are loaded once per sweep, the hold set during the sweep is found by isHeld before the file is purged.
+func (s *RetentionSweeper) getExpiredQuery(ctx context.Context) (*proto.ReportFileJobExpiredQuery, error) {
+	query := &proto.ReportFileJobExpiredQuery{Limit: s.cfg.BatchSize}
+
+	if query.Limit <= 0 {
+		query.Limit = 1
+	}
+
+	holds, err := s.holds.FindActive(ctx, "")
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, hold := range holds {
+		if hold.FileId == "" {
+			query.ExcludeMerchantIds = append(query.ExcludeMerchantIds, hold.MerchantId)
+			continue
+		}
+
+		if oid, err := primitive.ObjectIDFromHex(hold.FileId); err == nil {
+			query.ExcludeIds = append(query.ExcludeIds, oid)
+		}
+	}
+
+	return query, nil
+}
+
+// isHeld reports whether the job is under the active legal hold. The holds are checked right before the purge
+// of every file, so the file is never deleted after its hold has been set.
+func (s *RetentionSweeper) isHeld(ctx context.Context, job *proto.ReportFileJob) (bool, error) {
+	holds, err := s.holds.FindActive(ctx, job.MerchantId)
+
+	if err != nil {
+		zap.L().Error("Unable to check legal holds of the expired report file", zap.Error(err), zap.String("id", job.Id.Hex()))
+		retentionErrors.WithLabelValues(retentionStageHold).Inc()
+
+		return false, err
+	}
+
+	for _, hold := range holds {
+		if hold.FileId == "" || hold.FileId == job.Id.Hex() {
+			zap.L().Info("Expired report file is under the legal hold, skipped", zap.String("id", job.Id.Hex()))
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (s *RetentionSweeper) purge(ctx context.Context, job *proto.ReportFileJob) bool {
+	dryRun := strconv.FormatBool(s.cfg.DryRun)
+	fields := []zap.Field{
+		zap.String("id", job.Id.Hex()),
+		zap.String("report_type", job.ReportType),
+		zap.String("bucket", job.Bucket),
+		zap.String("file_name", job.FileName),
+		zap.Int64("size", job.Size),
+	}
+
+	if s.cfg.DryRun {
+		zap.L().Info("Expired report file found, dry run", fields...)
+		retentionPurgedFiles.WithLabelValues(job.ReportType, dryRun).Inc()
+		retentionPurgedBytes.WithLabelValues(job.ReportType, dryRun).Add(float64(job.Size))
+
+		return true
+	}
+
+	storage, err := s.storages.GetStorage(job.Bucket, job.ReportType)
+
+	if err != nil {
+		zap.L().Error("Unable to get storage of the expired report file", append(fields, zap.Error(err))...)
+		retentionErrors.WithLabelValues(retentionStageStorage).Inc()
+
+		return false
+	}
+
+	if err = storage.Delete(ctx, job.FileName); err != nil {
+		zap.L().Error("Unable to delete expired report file from the storage", append(fields, zap.Error(err))...)
+		retentionErrors.WithLabelValues(retentionStageDelete).Inc()
+
+		return false
+	}
+
+	// The job is no longer found when it has already been swept by the other instance of the service
+	if err = s.repository.SetExpired(ctx, job.Id.Hex()); err != nil && err != mongo.ErrNoDocuments {
+		zap.L().Error("Unable to mark report file job as expired", append(fields, zap.Error(err))...)
+		retentionErrors.WithLabelValues(retentionStageUpdate).Inc()
+
+		return false
+	}
+
+	zap.L().Info("Expired report file purged", fields...)
+	retentionPurgedFiles.WithLabelValues(job.ReportType, dryRun).Inc()
+	retentionPurgedBytes.WithLabelValues(job.ReportType, dryRun).Add(float64(job.Size))
+
+	return true
+}
@@ -0,0 +1,254 @@
+package internal
+
+import (
+	"context"
+	errs "errors"
+	"github.com/paysuper/paysuper-reporter/internal/config"
+	"github.com/paysuper/paysuper-reporter/internal/mocks"
+	"github.com/paysuper/paysuper-reporter/pkg"
+	"github.com/paysuper/paysuper-reporter/pkg/proto"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"testing"
+	"time"
+)
+
+type RetentionSweeperTestSuite struct {
+	suite.Suite
+	cfg              *config.RetentionConfig
+	repository       *mocks.ReportFileRepositoryInterface
+	holds            *mocks.LegalHoldRepositoryInterface
+	storage          *mocks.StorageInterface
+	agreementStorage *mocks.StorageInterface
+	sweeper          *RetentionSweeper
+	now              time.Time
+}
+
+func Test_RetentionSweeper(t *testing.T) {
+	suite.Run(t, new(RetentionSweeperTestSuite))
+}
+
+func (suite *RetentionSweeperTestSuite) SetupTest() {
+	suite.cfg = &config.RetentionConfig{SweepInterval: 3600, BatchSize: 2}
+	suite.repository = &mocks.ReportFileRepositoryInterface{}
+	suite.holds = &mocks.LegalHoldRepositoryInterface{}
+	suite.storage = &mocks.StorageInterface{}
+	suite.storage.On("Bucket").Return("reports")
+	suite.agreementStorage = &mocks.StorageInterface{}
+	suite.agreementStorage.On("Bucket").Return("agreements")
+	suite.holds.On("FindActive", mock.Anything, "").Return(nil, nil)
+
+	storages := map[string]StorageInterface{
+		pkg.StorageTargetReports:    suite.storage,
+		pkg.StorageTargetAgreements: suite.agreementStorage,
+	}
+	router, err := newStorageRouter(storages, nil)
+	assert.NoError(suite.T(), err)
+
+	suite.now = time.Date(2020, 1, 15, 10, 30, 0, 0, time.UTC)
+	suite.sweeper = newRetentionSweeper(suite.cfg, suite.repository, suite.holds, router)
+	suite.sweeper.now = func() time.Time { return suite.now }
+}
+
+func (suite *RetentionSweeperTestSuite) TestRetentionSweeper_Sweep_Ok() {
+	jobs := []*proto.ReportFileJob{
+		suite.getJob("reports", "report1.pdf", 100),
+		suite.getJob("agreements", "report2.pdf", 200),
+	}
+	last := suite.getJob("", "report3.pdf", 300)
+	suite.repository.On("FindExpired", mock.Anything, suite.getQuery()).Return(jobs, nil).Once()
+	suite.repository.On("FindExpired", mock.Anything, suite.getQuery()).Return([]*proto.ReportFileJob{last}, nil).Once()
+	suite.repository.On("SetExpired", mock.Anything, mock.Anything).Return(nil)
+	suite.storage.On("Delete", mock.Anything, mock.Anything).Return(nil)
+	suite.agreementStorage.On("Delete", mock.Anything, mock.Anything).Return(nil)
+
+	purged := testutil.ToFloat64(retentionPurgedFiles.WithLabelValues(reporterpb.ReportTypeVat, "false"))
+
+	res, err := suite.sweeper.Sweep(context.TODO())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), &RetentionSweepResult{Files: 3, Bytes: 600}, res)
+	suite.storage.AssertCalled(suite.T(), "Delete", mock.Anything, "report1.pdf")
+	suite.storage.AssertCalled(suite.T(), "Delete", mock.Anything, "report3.pdf")
+	suite.agreementStorage.AssertCalled(suite.T(), "Delete", mock.Anything, "report2.pdf")
+	suite.repository.AssertNumberOfCalls(suite.T(), "SetExpired", 3)
+	suite.repository.AssertNumberOfCalls(suite.T(), "FindExpired", 2)
+	assert.Equal(suite.T(), purged+3, testutil.ToFloat64(retentionPurgedFiles.WithLabelValues(reporterpb.ReportTypeVat, "false")))
+}
+
+func (suite *RetentionSweeperTestSuite) TestRetentionSweeper_Sweep_DryRun() {
+	suite.cfg.DryRun = true
+	jobs := []*proto.ReportFileJob{
+		suite.getJob("reports", "report1.pdf", 100),
+		suite.getJob("reports", "report2.pdf", 200),
+	}
+	suite.repository.On("FindExpired", mock.Anything, suite.getQuery()).Return(jobs, nil)
+
+	purged := testutil.ToFloat64(retentionPurgedBytes.WithLabelValues(reporterpb.ReportTypeVat, "true"))
+
+	res, err := suite.sweeper.Sweep(context.TODO())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), &RetentionSweepResult{Files: 2, Bytes: 300}, res)
+	suite.repository.AssertNumberOfCalls(suite.T(), "FindExpired", 1)
+	suite.repository.AssertNotCalled(suite.T(), "SetExpired", mock.Anything, mock.Anything)
+	suite.storage.AssertNotCalled(suite.T(), "Delete", mock.Anything, mock.Anything)
+	assert.Equal(suite.T(), purged+300, testutil.ToFloat64(retentionPurgedBytes.WithLabelValues(reporterpb.ReportTypeVat, "true")))
+}
+
+func (suite *RetentionSweeperTestSuite) TestRetentionSweeper_Sweep_Error_Delete() {
+	jobs := []*proto.ReportFileJob{
+		suite.getJob("reports", "report1.pdf", 100),
+		suite.getJob("reports", "report2.pdf", 200),
+	}
+	query := suite.getQuery()
+	query.ExcludeIds = []primitive.ObjectID{jobs[0].Id, jobs[1].Id}
+	suite.repository.On("FindExpired", mock.Anything, suite.getQuery()).Return(jobs, nil).Once()
+	suite.repository.On("FindExpired", mock.Anything, query).Return(nil, nil).Once()
+	suite.storage.On("Delete", mock.Anything, mock.Anything).Return(errs.New("error"))
+
+	errors := testutil.ToFloat64(retentionErrors.WithLabelValues(retentionStageDelete))
+
+	res, err := suite.sweeper.Sweep(context.TODO())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), &RetentionSweepResult{Errors: 2}, res)
+	suite.repository.AssertNumberOfCalls(suite.T(), "FindExpired", 2)
+	suite.repository.AssertNotCalled(suite.T(), "SetExpired", mock.Anything, mock.Anything)
+	assert.Equal(suite.T(), errors+2, testutil.ToFloat64(retentionErrors.WithLabelValues(retentionStageDelete)))
+}
+
+func (suite *RetentionSweeperTestSuite) TestRetentionSweeper_Sweep_Error_Bucket() {
+	jobs := []*proto.ReportFileJob{suite.getJob("unknown", "report1.pdf", 100)}
+	suite.repository.On("FindExpired", mock.Anything, suite.getQuery()).Return(jobs, nil)
+
+	res, err := suite.sweeper.Sweep(context.TODO())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), &RetentionSweepResult{Errors: 1}, res)
+	suite.storage.AssertNotCalled(suite.T(), "Delete", mock.Anything, mock.Anything)
+}
+
+func (suite *RetentionSweeperTestSuite) TestRetentionSweeper_Sweep_SetExpired() {
+	jobs := []*proto.ReportFileJob{
+		suite.getJob("reports", "report1.pdf", 100),
+		suite.getJob("reports", "report2.pdf", 200),
+	}
+	query := suite.getQuery()
+	query.ExcludeIds = []primitive.ObjectID{jobs[1].Id}
+	suite.repository.On("FindExpired", mock.Anything, suite.getQuery()).Return(jobs, nil).Once()
+	suite.repository.On("FindExpired", mock.Anything, query).Return(nil, nil).Once()
+	suite.repository.On("SetExpired", mock.Anything, jobs[0].Id.Hex()).Return(mongo.ErrNoDocuments)
+	suite.repository.On("SetExpired", mock.Anything, jobs[1].Id.Hex()).Return(errs.New("error"))
+	suite.storage.On("Delete", mock.Anything, mock.Anything).Return(nil)
+
+	res, err := suite.sweeper.Sweep(context.TODO())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), &RetentionSweepResult{Files: 1, Bytes: 100, Errors: 1}, res)
+	suite.repository.AssertNumberOfCalls(suite.T(), "FindExpired", 2)
+}
+
+func (suite *RetentionSweeperTestSuite) TestRetentionSweeper_Sweep_Error_FindExpired() {
+	suite.repository.On("FindExpired", mock.Anything, mock.Anything).Return(nil, errs.New("error"))
+
+	res, err := suite.sweeper.Sweep(context.TODO())
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), &RetentionSweepResult{}, res)
+}
+
+func (suite *RetentionSweeperTestSuite) TestRetentionSweeper_Sweep_Cancelled() {
+	jobs := []*proto.ReportFileJob{suite.getJob("reports", "report1.pdf", 100)}
+	suite.repository.On("FindExpired", mock.Anything, mock.Anything).Return(jobs, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := suite.sweeper.Sweep(ctx)
+	assert.Equal(suite.T(), context.Canceled, err)
+	suite.storage.AssertNotCalled(suite.T(), "Delete", mock.Anything, mock.Anything)
+}
+
+func (suite *RetentionSweeperTestSuite) TestRetentionSweeper_Sweep_LegalHold() {
+	fileId := primitive.NewObjectID()
+	holds := []*proto.LegalHold{
+		{MerchantId: "ffffffffffffffffffffffff"},
+		{MerchantId: "fffffffffffffffffffffff1", FileId: fileId.Hex()},
+	}
+	suite.holds.ExpectedCalls = nil
+	suite.holds.On("FindActive", mock.Anything, "").Return(holds, nil)
+
+	query := suite.getQuery()
+	query.ExcludeMerchantIds = []string{"ffffffffffffffffffffffff"}
+	query.ExcludeIds = []primitive.ObjectID{fileId}
+	suite.repository.On("FindExpired", mock.Anything, query).Return(nil, nil)
+
+	res, err := suite.sweeper.Sweep(context.TODO())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), &RetentionSweepResult{}, res)
+	suite.repository.AssertNumberOfCalls(suite.T(), "FindExpired", 1)
+}
+
+func (suite *RetentionSweeperTestSuite) TestRetentionSweeper_Sweep_LegalHold_SetDuringSweep() {
+	held := suite.getJob("reports", "report1.pdf", 100)
+	held.MerchantId = "fffffffffffffffffffffff2"
+	failed := suite.getJob("reports", "report2.pdf", 200)
+	failed.MerchantId = "fffffffffffffffffffffff3"
+	suite.holds.On("FindActive", mock.Anything, held.MerchantId).
+		Return([]*proto.LegalHold{{MerchantId: held.MerchantId, FileId: held.Id.Hex()}}, nil)
+	suite.holds.On("FindActive", mock.Anything, failed.MerchantId).Return(nil, errs.New("error"))
+
+	query := suite.getQuery()
+	query.ExcludeIds = []primitive.ObjectID{held.Id, failed.Id}
+	suite.repository.On("FindExpired", mock.Anything, suite.getQuery()).Return([]*proto.ReportFileJob{held, failed}, nil).Once()
+	suite.repository.On("FindExpired", mock.Anything, query).Return(nil, nil).Once()
+
+	errors := testutil.ToFloat64(retentionErrors.WithLabelValues(retentionStageHold))
+
+	res, err := suite.sweeper.Sweep(context.TODO())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), &RetentionSweepResult{Errors: 1}, res)
+	suite.storage.AssertNotCalled(suite.T(), "Delete", mock.Anything, mock.Anything)
+	suite.repository.AssertNotCalled(suite.T(), "SetExpired", mock.Anything, mock.Anything)
+	assert.Equal(suite.T(), errors+1, testutil.ToFloat64(retentionErrors.WithLabelValues(retentionStageHold)))
+}
+
+func (suite *RetentionSweeperTestSuite) TestRetentionSweeper_Sweep_Error_FindActive() {
+	suite.holds.ExpectedCalls = nil
+	suite.holds.On("FindActive", mock.Anything, "").Return(nil, errs.New("error"))
+
+	_, err := suite.sweeper.Sweep(context.TODO())
+	assert.Error(suite.T(), err)
+	suite.repository.AssertNotCalled(suite.T(), "FindExpired", mock.Anything, mock.Anything)
+}
+
+func (suite *RetentionSweeperTestSuite) TestRetentionSweeper_StartStop() {
+	suite.cfg.SweepInterval = 0
+	suite.sweeper.Start(context.Background())
+	suite.sweeper.Stop()
+	suite.sweeper.Stop()
+
+	suite.cfg.SweepInterval = 3600
+	sweeper := newRetentionSweeper(suite.cfg, suite.repository, suite.holds, suite.sweeper.storages)
+	sweeper.Start(context.Background())
+	sweeper.Stop()
+	suite.repository.AssertNotCalled(suite.T(), "FindExpired", mock.Anything, mock.Anything)
+}
+
+func (suite *RetentionSweeperTestSuite) getQuery() *proto.ReportFileJobExpiredQuery {
+	return &proto.ReportFileJobExpiredQuery{ExpiredAt: suite.now, Limit: 2}
+}
+
+func (suite *RetentionSweeperTestSuite) getJob(bucket, fileName string, size int64) *proto.ReportFileJob {
+	expiresAt := suite.now.Add(-time.Hour)
+
+	return &proto.ReportFileJob{
+		Id:         primitive.NewObjectID(),
+		ReportType: reporterpb.ReportTypeVat,
+		Status:     pkg.ReportFileStatusCompleted,
+		Bucket:     bucket,
+		FileName:   fileName,
+		Size:       size,
+		ExpiresAt:  &expiresAt,
+	}
+}
@@ -0,0 +1,161 @@
+package internal
+
+import (
+	"fmt"
+	protobufProto "github.com/golang/protobuf/proto"
+	"github.com/paysuper/paysuper-reporter/internal/config"
+	"github.com/paysuper/paysuper-reporter/pkg"
+	"github.com/streadway/amqp"
+	"math"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RetryPolicy describes how many times and with which delay a failed message will be processed again.
+type RetryPolicy struct {
+	MaxCount  int32
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	Jitter    float64
+
+	rnd *rand.Rand
+	mx  sync.Mutex
+}
+
+type RetryQueueInterface interface {
+	Publish(topic string, msg protobufProto.Message, retryCount int32, delay time.Duration) error
+	Close() error
+}
+
+// RetryQueue holds failed messages in the per attempt delay queues. When the message ttl expires
+// RabbitMQ returns it to the exchange of the original topic.
+type RetryQueue struct {
+	channel  *BrokerChannel
+	declared map[string]bool
+	mx       sync.Mutex
+}
+
+func newRetryPolicies(cfg *config.RetryConfig) map[string]*RetryPolicy {
+	return map[string]*RetryPolicy{
+		pkg.BrokerGenerateReportTopicName: newRetryPolicy(
+			cfg.GenerateMaxCount,
+			cfg.GenerateBaseDelay,
+			cfg.GenerateMaxDelay,
+			cfg.GenerateJitter,
+		),
+		pkg.BrokerGenerateReportInteractiveTopicName: newRetryPolicy(
+			cfg.GenerateMaxCount,
+			cfg.GenerateBaseDelay,
+			cfg.GenerateMaxDelay,
+			cfg.GenerateJitter,
+		),
+		pkg.BrokerPostProcessTopicName: newRetryPolicy(
+			cfg.PostProcessMaxCount,
+			cfg.PostProcessBaseDelay,
+			cfg.PostProcessMaxDelay,
+			cfg.PostProcessJitter,
+		),
+	}
+}
+
+func newRetryPolicy(maxCount int32, baseDelay, maxDelay int64, jitter float64) *RetryPolicy {
+	return &RetryPolicy{
+		MaxCount:  maxCount,
+		BaseDelay: time.Duration(baseDelay) * time.Millisecond,
+		MaxDelay:  time.Duration(maxDelay) * time.Millisecond,
+		Jitter:    math.Max(0, math.Min(jitter, 1)),
+		rnd:       rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Delay returns the exponentially growing delay before the next attempt, randomized by the jitter
+// so that messages failed together are not retried at the same moment.
+func (p *RetryPolicy) Delay(retryCount int32) time.Duration {
+	delay := float64(p.BaseDelay) * math.Pow(2, float64(retryCount))
+
+	if delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+
+	if p.Jitter > 0 {
+		p.mx.Lock()
+		delay = delay * (1 - p.Jitter + 2*p.Jitter*p.rnd.Float64())
+		p.mx.Unlock()
+	}
+
+	if delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+
+	return time.Duration(delay)
+}
+
+func newRetryQueue(address string) (RetryQueueInterface, error) {
+	channel, err := newBrokerChannel(address, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &RetryQueue{channel: channel, declared: make(map[string]bool)}, nil
+}
+
+func (q *RetryQueue) Publish(topic string, msg protobufProto.Message, retryCount int32, delay time.Duration) error {
+	body, err := protobufProto.Marshal(msg)
+
+	if err != nil {
+		return err
+	}
+
+	q.mx.Lock()
+	defer q.mx.Unlock()
+
+	// Every attempt has its own delay queue, so messages with a long delay do not block the expiration
+	// of the messages with a short one
+	name := fmt.Sprintf(pkg.BrokerRetryQueueNameMask, topic, retryCount)
+
+	return q.channel.Publish(func(ch *amqp.Channel, dialed bool) error {
+		// The queues may have been deleted while the connection was lost, so they are declared on the new one again
+		if dialed {
+			q.declared = make(map[string]bool)
+		}
+
+		if !q.declared[name] {
+			args := amqp.Table{
+				"x-dead-letter-exchange":    topic,
+				"x-dead-letter-routing-key": topic,
+			}
+
+			if _, err := ch.QueueDeclare(name, true, false, false, false, args); err != nil {
+				return err
+			}
+
+			q.declared[name] = true
+		}
+
+		return ch.Publish(
+			"",
+			name,
+			false,
+			false,
+			amqp.Publishing{
+				ContentType:  "application/protobuf",
+				DeliveryMode: amqp.Persistent,
+				Expiration:   strconv.FormatInt(delay.Milliseconds(), 10),
+				Body:         body,
+				Headers: amqp.Table{
+					"x-retry-count": retryCount,
+				},
+			},
+		)
+	})
+}
+
+func (q *RetryQueue) Close() error {
+	q.mx.Lock()
+	defer q.mx.Unlock()
+
+	return q.channel.Close()
+}
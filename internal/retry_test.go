@@ -0,0 +1,65 @@
+package internal
+
+import (
+	"github.com/paysuper/paysuper-reporter/internal/config"
+	"github.com/paysuper/paysuper-reporter/pkg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type RetryTestSuite struct {
+	suite.Suite
+}
+
+func Test_Retry(t *testing.T) {
+	suite.Run(t, new(RetryTestSuite))
+}
+
+func (suite *RetryTestSuite) TestRetry_newRetryPolicies_Ok() {
+	cfg := &config.RetryConfig{
+		GenerateMaxCount:     5,
+		GenerateBaseDelay:    1000,
+		GenerateMaxDelay:     60000,
+		GenerateJitter:       0.1,
+		PostProcessMaxCount:  3,
+		PostProcessBaseDelay: 500,
+		PostProcessMaxDelay:  10000,
+		PostProcessJitter:    2,
+	}
+	policies := newRetryPolicies(cfg)
+
+	assert.Len(suite.T(), policies, 3)
+	assert.EqualValues(suite.T(), 5, policies[pkg.BrokerGenerateReportTopicName].MaxCount)
+	assert.Equal(suite.T(), time.Second, policies[pkg.BrokerGenerateReportTopicName].BaseDelay)
+	assert.Equal(suite.T(), time.Minute, policies[pkg.BrokerGenerateReportTopicName].MaxDelay)
+	assert.EqualValues(suite.T(), 5, policies[pkg.BrokerGenerateReportInteractiveTopicName].MaxCount)
+	assert.Equal(suite.T(), time.Second, policies[pkg.BrokerGenerateReportInteractiveTopicName].BaseDelay)
+	assert.EqualValues(suite.T(), 3, policies[pkg.BrokerPostProcessTopicName].MaxCount)
+	assert.Equal(suite.T(), 500*time.Millisecond, policies[pkg.BrokerPostProcessTopicName].BaseDelay)
+	assert.Equal(suite.T(), float64(1), policies[pkg.BrokerPostProcessTopicName].Jitter)
+}
+
+func (suite *RetryTestSuite) TestRetry_Delay_Exponential() {
+	policy := newRetryPolicy(10, 1000, 60000, 0)
+
+	assert.Equal(suite.T(), time.Second, policy.Delay(0))
+	assert.Equal(suite.T(), 2*time.Second, policy.Delay(1))
+	assert.Equal(suite.T(), 8*time.Second, policy.Delay(3))
+	assert.Equal(suite.T(), time.Minute, policy.Delay(6))
+	assert.Equal(suite.T(), time.Minute, policy.Delay(9))
+}
+
+func (suite *RetryTestSuite) TestRetry_Delay_Jitter() {
+	policy := newRetryPolicy(10, 1000, 60000, 0.5)
+
+	for i := 0; i < 100; i++ {
+		delay := policy.Delay(2)
+		assert.True(suite.T(), delay >= 2*time.Second && delay <= 6*time.Second, "delay %s out of range", delay)
+	}
+
+	for i := 0; i < 100; i++ {
+		assert.True(suite.T(), policy.Delay(8) <= time.Minute)
+	}
+}
@@ -0,0 +1,96 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/paysuper/paysuper-reporter/pkg/proto"
+	"io/ioutil"
+	"path"
+	"time"
+)
+
+// S3Storage stores the report files in the bucket of AWS S3 or of the S3 compatible storage, like MinIO.
+type S3Storage struct {
+	client *s3.S3
+	bucket string
+}
+
+func newS3Storage(cfg *aws.Config, bucket string) (StorageInterface, error) {
+	sess, err := session.NewSession(cfg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &S3Storage{client: s3.New(sess), bucket: bucket}, nil
+}
+
+func (c *S3Storage) Upload(ctx context.Context, fileName string, content []byte, opts *proto.StorageUploadOptions) error {
+	in := &s3.PutObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(fileName),
+		Body:   bytes.NewReader(content),
+	}
+
+	if opts != nil && !opts.Expires.IsZero() {
+		in.Expires = aws.Time(opts.Expires)
+	}
+
+	if opts != nil && opts.Acl != "" {
+		in.ACL = aws.String(opts.Acl)
+	}
+
+	_, err := c.client.PutObjectWithContext(ctx, in)
+
+	return err
+}
+
+func (c *S3Storage) Presign(fileName, contentType string, expire time.Duration) (string, error) {
+	in := &s3.GetObjectInput{
+		Bucket:                     aws.String(c.bucket),
+		Key:                        aws.String(fileName),
+		ResponseContentDisposition: aws.String(fmt.Sprintf("attachment; filename=\"%s\"", path.Base(fileName))),
+	}
+
+	if contentType != "" {
+		in.ResponseContentType = aws.String(contentType)
+	}
+
+	req, _ := c.client.GetObjectRequest(in)
+
+	return req.Presign(expire)
+}
+
+func (c *S3Storage) Delete(ctx context.Context, fileName string) error {
+	in := &s3.DeleteObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(fileName),
+	}
+	_, err := c.client.DeleteObjectWithContext(ctx, in)
+
+	return err
+}
+
+func (c *S3Storage) Get(ctx context.Context, fileName string) ([]byte, error) {
+	in := &s3.GetObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(fileName),
+	}
+	out, err := c.client.GetObjectWithContext(ctx, in)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer out.Body.Close()
+
+	return ioutil.ReadAll(out.Body)
+}
+
+func (c *S3Storage) Bucket() string {
+	return c.bucket
+}
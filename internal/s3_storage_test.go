@@ -0,0 +1,39 @@
+package internal
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type S3StorageTestSuite struct {
+	suite.Suite
+}
+
+func Test_S3Storage(t *testing.T) {
+	suite.Run(t, new(S3StorageTestSuite))
+}
+
+func (suite *S3StorageTestSuite) TestS3Storage_newS3Storage_Ok() {
+	s3Storage, err := newS3Storage(getS3StorageConfig(&StorageBucket{Name: "bucket", AccessKeyId: "key", SecretKey: "secret", Region: "eu-west-1"}), "bucket")
+	assert.NoError(suite.T(), err)
+	assert.IsType(suite.T(), &S3Storage{}, s3Storage)
+}
+
+func (suite *S3StorageTestSuite) TestS3Storage_Presign_Ok() {
+	s3Storage, err := newS3Storage(getS3StorageConfig(&StorageBucket{Name: "bucket", AccessKeyId: "key", SecretKey: "secret", Region: "eu-west-1"}), "bucket")
+	assert.NoError(suite.T(), err)
+
+	url, err := s3Storage.Presign("report.pdf", "application/pdf", time.Hour)
+	assert.NoError(suite.T(), err)
+	assert.Contains(suite.T(), url, "bucket")
+	assert.Contains(suite.T(), url, "report.pdf")
+	assert.Contains(suite.T(), url, "X-Amz-Expires=3600")
+}
+
+func (suite *S3StorageTestSuite) TestS3Storage_Bucket_Ok() {
+	s3Storage, err := newS3Storage(getS3StorageConfig(&StorageBucket{Name: "bucket", AccessKeyId: "key", SecretKey: "secret", Region: "eu-west-1"}), "bucket")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "bucket", s3Storage.Bucket())
+}
@@ -0,0 +1,90 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/paysuper/paysuper-reporter/internal/config"
+	"github.com/paysuper/paysuper-reporter/pkg"
+	"github.com/paysuper/paysuper-reporter/pkg/proto"
+	"time"
+)
+
+var (
+	errStorageBucketEmpty      = errors.New("storage bucket name is empty")
+	errStorageCredentialsEmpty = errors.New("storage credentials are empty")
+	errStorageRegionEmpty      = errors.New("storage region is empty")
+	errStorageEndpointEmpty    = errors.New("storage endpoint is empty")
+)
+
+// StorageInterface stores the report files in the single bucket of the storage.
+type StorageInterface interface {
+	// Upload saves the file content with the options, nil options upload the private file without expiration.
+	Upload(ctx context.Context, fileName string, content []byte, opts *proto.StorageUploadOptions) error
+	Get(ctx context.Context, fileName string) ([]byte, error)
+	Delete(ctx context.Context, fileName string) error
+	Presign(fileName, contentType string, expire time.Duration) (string, error)
+	Bucket() string
+}
+
+// StorageBucket defines the bucket of the storage and the credentials to access it.
+type StorageBucket struct {
+	Name        string
+	AccessKeyId string
+	SecretKey   string
+	Region      string
+}
+
+// newStorage creates the storage of the bucket by the driver set in the config.
+func newStorage(cfg *config.StorageConfig, bucket *StorageBucket) (StorageInterface, error) {
+	if bucket.Name == "" {
+		return nil, errStorageBucketEmpty
+	}
+
+	switch cfg.Driver {
+	case pkg.StorageDriverS3:
+		if bucket.AccessKeyId == "" || bucket.SecretKey == "" {
+			return nil, errStorageCredentialsEmpty
+		}
+
+		if bucket.Region == "" {
+			return nil, errStorageRegionEmpty
+		}
+
+		return newS3Storage(getS3StorageConfig(bucket), bucket.Name)
+	case pkg.StorageDriverS3Compatible:
+		if bucket.AccessKeyId == "" || bucket.SecretKey == "" {
+			return nil, errStorageCredentialsEmpty
+		}
+
+		if cfg.Endpoint == "" {
+			return nil, errStorageEndpointEmpty
+		}
+
+		awsCfg := getS3StorageConfig(bucket)
+		awsCfg.Endpoint = aws.String(cfg.Endpoint)
+		awsCfg.S3ForcePathStyle = aws.Bool(true)
+		awsCfg.DisableSSL = aws.Bool(cfg.DisableSsl)
+
+		return newS3Storage(awsCfg, bucket.Name)
+	case pkg.StorageDriverLocal:
+		return newLocalStorage(cfg.LocalDir, cfg.LocalUrl, bucket.Name)
+	}
+
+	return nil, fmt.Errorf("unknown storage driver %s", cfg.Driver)
+}
+
+func getS3StorageConfig(bucket *StorageBucket) *aws.Config {
+	region := bucket.Region
+
+	if region == "" {
+		region = pkg.StorageDefaultRegion
+	}
+
+	return &aws.Config{
+		Credentials: credentials.NewStaticCredentials(bucket.AccessKeyId, bucket.SecretKey, ""),
+		Region:      aws.String(region),
+	}
+}
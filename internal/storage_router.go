@@ -0,0 +1,190 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"github.com/paysuper/paysuper-reporter/internal/config"
+	"github.com/paysuper/paysuper-reporter/pkg"
+	"github.com/paysuper/paysuper-reporter/pkg/proto"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
+	"strings"
+	"time"
+)
+
+var (
+	errStorageRouteReportTypeEmpty = errors.New("storage route report type is empty")
+
+	storageKeyPrefixPlaceholders = []string{
+		"{merchant_id}",
+		"{user_id}",
+		"{report_type}",
+		"{file_type}",
+		"{year}",
+		"{month}",
+		"{day}",
+	}
+
+	storageCannedAcls = map[string]bool{
+		pkg.StorageAclPrivate:       true,
+		"public-read":               true,
+		"public-read-write":         true,
+		"authenticated-read":        true,
+		"aws-exec-read":             true,
+		"bucket-owner-read":         true,
+		"bucket-owner-full-control": true,
+	}
+)
+
+// StorageDestination is the storage, the key and the upload options of the report file.
+type StorageDestination struct {
+	Storage StorageInterface
+	Key     string
+	Options *proto.StorageUploadOptions
+}
+
+// StorageRouter routes the report files to the storage targets by the rules of the report types. Report types
+// without the rule are stored in the reports target with the private ACL until the retention time expires.
+type StorageRouter struct {
+	storages map[string]StorageInterface
+	routes   map[string]*config.StorageRoute
+}
+
+func newStorageRouter(storages map[string]StorageInterface, routes config.StorageRoutes) (*StorageRouter, error) {
+	if storages[pkg.StorageTargetReports] == nil {
+		return nil, fmt.Errorf("storage target %s is not configured", pkg.StorageTargetReports)
+	}
+
+	router := &StorageRouter{storages: storages, routes: make(map[string]*config.StorageRoute)}
+
+	for _, route := range routes {
+		if route.ReportType == "" {
+			return nil, errStorageRouteReportTypeEmpty
+		}
+
+		if _, ok := router.routes[route.ReportType]; ok {
+			return nil, fmt.Errorf("storage route of report type %s is duplicated", route.ReportType)
+		}
+
+		if route.Target != "" && storages[route.Target] == nil {
+			return nil, fmt.Errorf("storage target %s is not configured", route.Target)
+		}
+
+		if route.Expiry != "" && route.Expiry != pkg.StorageExpiryRetention && route.Expiry != pkg.StorageExpiryNone {
+			return nil, fmt.Errorf("storage expiry policy %s is unknown", route.Expiry)
+		}
+
+		if route.Acl != "" && !storageCannedAcls[route.Acl] {
+			return nil, fmt.Errorf("storage ACL %s is unknown", route.Acl)
+		}
+
+		if err := validateStorageKeyPrefix(route.KeyPrefix); err != nil {
+			return nil, err
+		}
+
+		router.routes[route.ReportType] = route
+	}
+
+	return router, nil
+}
+
+// Resolve returns the destination of the report file, the key is the file name prefixed by the key prefix
+// template of the route. The retention time is used by the routes with the retention expiry policy.
+func (r *StorageRouter) Resolve(
+	file *reporterpb.ReportFile,
+	fileName string,
+	retention time.Duration,
+	now time.Time,
+) *StorageDestination {
+	route := r.getRoute(file.ReportType)
+	opts := &proto.StorageUploadOptions{Acl: route.Acl}
+
+	if opts.Acl == "" {
+		opts.Acl = pkg.StorageAclPrivate
+	}
+
+	if route.Expiry != pkg.StorageExpiryNone {
+		opts.Expires = now.Add(retention)
+	}
+
+	return &StorageDestination{
+		Storage: r.getTargetStorage(route),
+		Key:     getStorageKeyPrefix(route.KeyPrefix, file, now) + fileName,
+		Options: opts,
+	}
+}
+
+// GetStorage returns the storage of the bucket the report file was uploaded to. Jobs uploaded before the bucket
+// was stored have no bucket, so the storage is taken from the route of the report type.
+func (r *StorageRouter) GetStorage(bucket, reportType string) (StorageInterface, error) {
+	if bucket == "" {
+		return r.getTargetStorage(r.getRoute(reportType)), nil
+	}
+
+	for _, storage := range r.storages {
+		if storage.Bucket() == bucket {
+			return storage, nil
+		}
+	}
+
+	return nil, fmt.Errorf("report file bucket %s is not configured", bucket)
+}
+
+func (r *StorageRouter) getRoute(reportType string) *config.StorageRoute {
+	if route, ok := r.routes[reportType]; ok {
+		return route
+	}
+
+	return &config.StorageRoute{ReportType: reportType}
+}
+
+func (r *StorageRouter) getTargetStorage(route *config.StorageRoute) StorageInterface {
+	if route.Target == "" {
+		return r.storages[pkg.StorageTargetReports]
+	}
+
+	return r.storages[route.Target]
+}
+
+// getStorageKeyPrefix replaces the placeholders of the key prefix template, the date is taken in UTC.
+func getStorageKeyPrefix(template string, file *reporterpb.ReportFile, now time.Time) string {
+	if template == "" {
+		return ""
+	}
+
+	now = now.UTC()
+	prefix := strings.NewReplacer(
+		"{merchant_id}", file.MerchantId,
+		"{user_id}", file.UserId,
+		"{report_type}", file.ReportType,
+		"{file_type}", file.FileType,
+		"{year}", fmt.Sprintf("%04d", now.Year()),
+		"{month}", fmt.Sprintf("%02d", now.Month()),
+		"{day}", fmt.Sprintf("%02d", now.Day()),
+	).Replace(strings.TrimLeft(template, "/"))
+
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	return prefix
+}
+
+func validateStorageKeyPrefix(template string) error {
+	prefix := template
+
+	for _, placeholder := range storageKeyPrefixPlaceholders {
+		prefix = strings.Replace(prefix, placeholder, "", -1)
+	}
+
+	if strings.ContainsAny(prefix, "{}") {
+		return fmt.Errorf("storage key prefix %s contains unknown placeholder", template)
+	}
+
+	for _, part := range strings.Split(template, "/") {
+		if part == "." || part == ".." {
+			return fmt.Errorf("storage key prefix %s contains relative path", template)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package internal
+
+import (
+	"github.com/paysuper/paysuper-reporter/internal/config"
+	"github.com/paysuper/paysuper-reporter/internal/mocks"
+	"github.com/paysuper/paysuper-reporter/pkg"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type StorageRouterTestSuite struct {
+	suite.Suite
+	storage          *mocks.StorageInterface
+	agreementStorage *mocks.StorageInterface
+	storages         map[string]StorageInterface
+	now              time.Time
+	file             *reporterpb.ReportFile
+}
+
+func Test_StorageRouter(t *testing.T) {
+	suite.Run(t, new(StorageRouterTestSuite))
+}
+
+func (suite *StorageRouterTestSuite) SetupTest() {
+	suite.storage = &mocks.StorageInterface{}
+	suite.storage.On("Bucket").Return("reports")
+	suite.agreementStorage = &mocks.StorageInterface{}
+	suite.agreementStorage.On("Bucket").Return("agreements")
+	suite.storages = map[string]StorageInterface{
+		pkg.StorageTargetReports:    suite.storage,
+		pkg.StorageTargetAgreements: suite.agreementStorage,
+	}
+	suite.now = time.Date(2020, 3, 5, 10, 30, 0, 0, time.UTC)
+	suite.file = &reporterpb.ReportFile{
+		MerchantId: "ffffffffffffffffffffffff",
+		UserId:     "fffffffffffffffffffffff1",
+		ReportType: reporterpb.ReportTypePayout,
+		FileType:   reporterpb.OutputExtensionPdf,
+	}
+}
+
+func (suite *StorageRouterTestSuite) TestStorageRouter_Resolve_Default() {
+	router, err := newStorageRouter(suite.storages, nil)
+	assert.NoError(suite.T(), err)
+
+	dst := router.Resolve(suite.file, "report.pdf", time.Hour, suite.now)
+	assert.Equal(suite.T(), suite.storage, dst.Storage)
+	assert.Equal(suite.T(), "report.pdf", dst.Key)
+	assert.Equal(suite.T(), suite.now.Add(time.Hour), dst.Options.Expires)
+	assert.Equal(suite.T(), pkg.StorageAclPrivate, dst.Options.Acl)
+}
+
+func (suite *StorageRouterTestSuite) TestStorageRouter_Resolve_Route() {
+	routes := config.StorageRoutes{
+		{
+			ReportType: reporterpb.ReportTypePayout,
+			Target:     pkg.StorageTargetAgreements,
+			KeyPrefix:  "payouts/{merchant_id}/{year}/{month}/{day}",
+			Expiry:     pkg.StorageExpiryNone,
+			Acl:        "bucket-owner-full-control",
+		},
+	}
+	router, err := newStorageRouter(suite.storages, routes)
+	assert.NoError(suite.T(), err)
+
+	dst := router.Resolve(suite.file, "report.pdf", time.Hour, suite.now)
+	assert.Equal(suite.T(), suite.agreementStorage, dst.Storage)
+	assert.Equal(suite.T(), "payouts/ffffffffffffffffffffffff/2020/03/05/report.pdf", dst.Key)
+	assert.True(suite.T(), dst.Options.Expires.IsZero())
+	assert.Equal(suite.T(), "bucket-owner-full-control", dst.Options.Acl)
+
+	suite.file.ReportType = reporterpb.ReportTypeRoyalty
+	dst = router.Resolve(suite.file, "report.pdf", time.Hour, suite.now)
+	assert.Equal(suite.T(), suite.storage, dst.Storage)
+	assert.Equal(suite.T(), "report.pdf", dst.Key)
+}
+
+func (suite *StorageRouterTestSuite) TestStorageRouter_Resolve_KeyPrefix() {
+	routes := config.StorageRoutes{
+		{ReportType: reporterpb.ReportTypePayout, KeyPrefix: "/{report_type}/{user_id}.{file_type}/"},
+	}
+	router, err := newStorageRouter(suite.storages, routes)
+	assert.NoError(suite.T(), err)
+
+	dst := router.Resolve(suite.file, "report.pdf", time.Hour, suite.now)
+	assert.Equal(suite.T(), suite.storage, dst.Storage)
+	assert.Equal(suite.T(), "payout/fffffffffffffffffffffff1.pdf/report.pdf", dst.Key)
+	assert.False(suite.T(), dst.Options.Expires.IsZero())
+}
+
+func (suite *StorageRouterTestSuite) TestStorageRouter_GetStorage() {
+	routes := config.StorageRoutes{
+		{ReportType: reporterpb.ReportTypeAgreement, Target: pkg.StorageTargetAgreements},
+	}
+	router, err := newStorageRouter(suite.storages, routes)
+	assert.NoError(suite.T(), err)
+
+	storage, err := router.GetStorage("agreements", reporterpb.ReportTypePayout)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), suite.agreementStorage, storage)
+
+	storage, err = router.GetStorage("", reporterpb.ReportTypeAgreement)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), suite.agreementStorage, storage)
+
+	storage, err = router.GetStorage("", reporterpb.ReportTypePayout)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), suite.storage, storage)
+
+	_, err = router.GetStorage("unknown", reporterpb.ReportTypePayout)
+	assert.EqualError(suite.T(), err, "report file bucket unknown is not configured")
+}
+
+func (suite *StorageRouterTestSuite) TestStorageRouter_Error_Routes() {
+	cases := []struct {
+		route *config.StorageRoute
+		err   string
+	}{
+		{&config.StorageRoute{}, errStorageRouteReportTypeEmpty.Error()},
+		{&config.StorageRoute{ReportType: "payout", Target: "archive"}, "storage target archive is not configured"},
+		{&config.StorageRoute{ReportType: "payout", Expiry: "never"}, "storage expiry policy never is unknown"},
+		{&config.StorageRoute{ReportType: "payout", Acl: "public"}, "storage ACL public is unknown"},
+		{
+			&config.StorageRoute{ReportType: "payout", KeyPrefix: "{merchant}/"},
+			"storage key prefix {merchant}/ contains unknown placeholder",
+		},
+		{
+			&config.StorageRoute{ReportType: "payout", KeyPrefix: "payouts/../{merchant_id}"},
+			"storage key prefix payouts/../{merchant_id} contains relative path",
+		},
+	}
+
+	for _, c := range cases {
+		_, err := newStorageRouter(suite.storages, config.StorageRoutes{c.route})
+		assert.EqualError(suite.T(), err, c.err)
+	}
+
+	routes := config.StorageRoutes{{ReportType: "payout"}, {ReportType: "payout"}}
+	_, err := newStorageRouter(suite.storages, routes)
+	assert.EqualError(suite.T(), err, "storage route of report type payout is duplicated")
+
+	_, err = newStorageRouter(map[string]StorageInterface{}, nil)
+	assert.EqualError(suite.T(), err, "storage target reports is not configured")
+}
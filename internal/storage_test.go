@@ -0,0 +1,81 @@
+package internal
+
+import (
+	"github.com/paysuper/paysuper-reporter/internal/config"
+	"github.com/paysuper/paysuper-reporter/pkg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+type StorageTestSuite struct {
+	suite.Suite
+	bucket *StorageBucket
+}
+
+func Test_Storage(t *testing.T) {
+	suite.Run(t, new(StorageTestSuite))
+}
+
+func (suite *StorageTestSuite) SetupTest() {
+	suite.bucket = &StorageBucket{Name: "bucket", AccessKeyId: "key", SecretKey: "secret", Region: "eu-west-1"}
+}
+
+func (suite *StorageTestSuite) TestStorage_newStorage_S3_Ok() {
+	storage, err := newStorage(&config.StorageConfig{Driver: pkg.StorageDriverS3}, suite.bucket)
+	assert.NoError(suite.T(), err)
+	assert.IsType(suite.T(), &S3Storage{}, storage)
+	assert.Equal(suite.T(), "bucket", storage.Bucket())
+}
+
+func (suite *StorageTestSuite) TestStorage_newStorage_S3_Error_Credentials() {
+	suite.bucket.SecretKey = ""
+
+	_, err := newStorage(&config.StorageConfig{Driver: pkg.StorageDriverS3}, suite.bucket)
+	assert.Equal(suite.T(), errStorageCredentialsEmpty, err)
+}
+
+func (suite *StorageTestSuite) TestStorage_newStorage_S3_Error_Region() {
+	suite.bucket.Region = ""
+
+	_, err := newStorage(&config.StorageConfig{Driver: pkg.StorageDriverS3}, suite.bucket)
+	assert.Equal(suite.T(), errStorageRegionEmpty, err)
+}
+
+func (suite *StorageTestSuite) TestStorage_newStorage_S3Compatible_Ok() {
+	suite.bucket.Region = ""
+	cfg := &config.StorageConfig{Driver: pkg.StorageDriverS3Compatible, Endpoint: "http://127.0.0.1:9000"}
+
+	storage, err := newStorage(cfg, suite.bucket)
+	assert.NoError(suite.T(), err)
+	assert.IsType(suite.T(), &S3Storage{}, storage)
+}
+
+func (suite *StorageTestSuite) TestStorage_newStorage_S3Compatible_Error_Endpoint() {
+	_, err := newStorage(&config.StorageConfig{Driver: pkg.StorageDriverS3Compatible}, suite.bucket)
+	assert.Equal(suite.T(), errStorageEndpointEmpty, err)
+}
+
+func (suite *StorageTestSuite) TestStorage_newStorage_Local_Ok() {
+	dir, err := ioutil.TempDir("", "storage")
+	assert.NoError(suite.T(), err)
+	defer os.RemoveAll(dir)
+
+	storage, err := newStorage(&config.StorageConfig{Driver: pkg.StorageDriverLocal, LocalDir: dir}, &StorageBucket{Name: "bucket"})
+	assert.NoError(suite.T(), err)
+	assert.IsType(suite.T(), &LocalStorage{}, storage)
+}
+
+func (suite *StorageTestSuite) TestStorage_newStorage_Error_BucketEmpty() {
+	suite.bucket.Name = ""
+
+	_, err := newStorage(&config.StorageConfig{Driver: pkg.StorageDriverS3}, suite.bucket)
+	assert.Equal(suite.T(), errStorageBucketEmpty, err)
+}
+
+func (suite *StorageTestSuite) TestStorage_newStorage_Error_UnknownDriver() {
+	_, err := newStorage(&config.StorageConfig{Driver: "ftp"}, suite.bucket)
+	assert.EqualError(suite.T(), err, "unknown storage driver ftp")
+}
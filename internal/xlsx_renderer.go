@@ -0,0 +1,389 @@
+package internal
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/paysuper/paysuper-reporter/pkg/proto"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	xlsxNumFmtGeneral   = 0
+	xlsxNumFmtInteger   = 3
+	xlsxNumFmtNumber    = 4
+	xlsxNumFmtCustomMin = 164
+
+	xlsxStyleDefault = 0
+	xlsxStyleHeader  = 1
+
+	xlsxCurrencyFormatMask = "#,##0.00 \"%s\""
+
+	xlsxNamespaceMain          = "http://schemas.openxmlformats.org/spreadsheetml/2006/main"
+	xlsxNamespaceRelationships = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+	xlsxNamespacePackageRels   = "http://schemas.openxmlformats.org/package/2006/relationships"
+	xlsxRelTypeWorksheet       = xlsxNamespaceRelationships + "/worksheet"
+	xlsxRelTypeStyles          = xlsxNamespaceRelationships + "/styles"
+	xlsxRelTypeOfficeDocument  = xlsxNamespaceRelationships + "/officeDocument"
+)
+
+var errXlsxReportUnsupported = errors.New("report type has no xlsx sheets definition")
+
+// XlsxRenderer renders the report files in the XLSX format without the document generator service.
+// Every section of the report is written to the separate sheet with the typed cells and frozen header.
+type XlsxRenderer struct{}
+
+// xlsxStyles collects the number formats used by the cells of the workbook.
+type xlsxStyles struct {
+	formats   map[string]int
+	codes     []string
+	styles    map[int]int
+	numFmtIds []int
+}
+
+func newXlsxRenderer() DocumentGeneratorInterface {
+	return &XlsxRenderer{}
+}
+
+func (r *XlsxRenderer) Render(_ context.Context, payload *proto.GeneratorPayload) ([]byte, error) {
+	sections, ok := reportFileSections[payload.ReportType]
+
+	if !ok || len(sections) < 1 {
+		return nil, errXlsxReportUnsupported
+	}
+
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	styles := newXlsxStyles()
+
+	for i, section := range sections {
+		rows, err := getSectionRows(payload.Data, section)
+
+		if err != nil {
+			return nil, err
+		}
+
+		w, err := zw.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1))
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err = writeXlsxSheet(w, payload.Data, section, rows, styles); err != nil {
+			return nil, err
+		}
+	}
+
+	parts := []struct {
+		name  string
+		write func(w io.Writer) error
+	}{
+		{"[Content_Types].xml", func(w io.Writer) error { return writeXlsxContentTypes(w, len(sections)) }},
+		{"_rels/.rels", writeXlsxRootRels},
+		{"xl/workbook.xml", func(w io.Writer) error { return writeXlsxWorkbook(w, sections) }},
+		{"xl/_rels/workbook.xml.rels", func(w io.Writer) error { return writeXlsxWorkbookRels(w, len(sections)) }},
+		{"xl/styles.xml", styles.write},
+	}
+
+	for _, part := range parts {
+		w, err := zw.Create(part.name)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err = part.write(w); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeXlsxSheet(
+	w io.Writer,
+	data interface{},
+	section *ReportSection,
+	rows []map[string]interface{},
+	styles *xlsxStyles,
+) error {
+	bw := bufio.NewWriter(w)
+
+	bw.WriteString(xml.Header)
+	bw.WriteString(`<worksheet xmlns="` + xlsxNamespaceMain + `" xmlns:r="` + xlsxNamespaceRelationships + `">`)
+	bw.WriteString(`<sheetViews><sheetView workbookViewId="0">`)
+	bw.WriteString(`<pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/>`)
+	bw.WriteString(`</sheetView></sheetViews>`)
+	bw.WriteString(`<cols>`)
+
+	for i, column := range section.Columns {
+		width := len(column.Title) + 4
+
+		if width < 12 {
+			width = 12
+		}
+
+		fmt.Fprintf(bw, `<col min="%d" max="%d" width="%d" customWidth="1"/>`, i+1, i+1, width)
+	}
+
+	bw.WriteString(`</cols><sheetData><row r="1">`)
+
+	for i, column := range section.Columns {
+		writeXlsxStringCell(bw, xlsxCellName(i, 1), xlsxStyleHeader, column.Title)
+	}
+
+	bw.WriteString(`</row>`)
+
+	for n, row := range rows {
+		fmt.Fprintf(bw, `<row r="%d">`, n+2)
+
+		for i, column := range section.Columns {
+			val, ok := row[column.Field]
+
+			if !ok || val == nil {
+				continue
+			}
+
+			ref := xlsxCellName(i, n+2)
+			number, isNumber := getXlsxNumber(val)
+
+			if !isNumber || column.Type == "" || column.Type == ReportColumnTypeText {
+				writeXlsxStringCell(bw, ref, xlsxStyleDefault, fmt.Sprintf("%v", val))
+				continue
+			}
+
+			style := styles.get(getXlsxNumberFormat(column, row, data))
+			fmt.Fprintf(bw, `<c r="%s" s="%d"><v>%s</v></c>`, ref, style, strconv.FormatFloat(number, 'f', -1, 64))
+		}
+
+		bw.WriteString(`</row>`)
+	}
+
+	bw.WriteString(`</sheetData></worksheet>`)
+
+	return bw.Flush()
+}
+
+func writeXlsxStringCell(w *bufio.Writer, ref string, style int, val string) {
+	fmt.Fprintf(w, `<c r="%s" s="%d" t="inlineStr"><is><t xml:space="preserve">`, ref, style)
+	_ = xml.EscapeText(w, []byte(val))
+	w.WriteString(`</t></is></c>`)
+}
+
+func writeXlsxContentTypes(w io.Writer, sheets int) error {
+	b := &strings.Builder{}
+	b.WriteString(xml.Header)
+	b.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	b.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	b.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	b.WriteString(`<Override PartName="/xl/workbook.xml" ` +
+		`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	b.WriteString(`<Override PartName="/xl/styles.xml" ` +
+		`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+
+	for i := 1; i <= sheets; i++ {
+		fmt.Fprintf(b, `<Override PartName="/xl/worksheets/sheet%d.xml" `+
+			`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i)
+	}
+
+	b.WriteString(`</Types>`)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeXlsxRootRels(w io.Writer) error {
+	_, err := io.WriteString(w, xml.Header+`<Relationships xmlns="`+xlsxNamespacePackageRels+`">`+
+		`<Relationship Id="rId1" Type="`+xlsxRelTypeOfficeDocument+`" Target="xl/workbook.xml"/>`+
+		`</Relationships>`)
+	return err
+}
+
+func writeXlsxWorkbook(w io.Writer, sections []*ReportSection) error {
+	b := &strings.Builder{}
+	b.WriteString(xml.Header)
+	b.WriteString(`<workbook xmlns="` + xlsxNamespaceMain + `" xmlns:r="` + xlsxNamespaceRelationships + `"><sheets>`)
+
+	for i, section := range sections {
+		b.WriteString(`<sheet name="`)
+		_ = xml.EscapeText(b, []byte(section.Name))
+		fmt.Fprintf(b, `" sheetId="%d" r:id="rId%d"/>`, i+1, i+1)
+	}
+
+	b.WriteString(`</sheets></workbook>`)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeXlsxWorkbookRels(w io.Writer, sheets int) error {
+	b := &strings.Builder{}
+	b.WriteString(xml.Header)
+	b.WriteString(`<Relationships xmlns="` + xlsxNamespacePackageRels + `">`)
+
+	for i := 1; i <= sheets; i++ {
+		fmt.Fprintf(b, `<Relationship Id="rId%d" Type="%s" Target="worksheets/sheet%d.xml"/>`, i, xlsxRelTypeWorksheet, i)
+	}
+
+	fmt.Fprintf(b, `<Relationship Id="rId%d" Type="%s" Target="styles.xml"/>`, sheets+1, xlsxRelTypeStyles)
+	b.WriteString(`</Relationships>`)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func newXlsxStyles() *xlsxStyles {
+	return &xlsxStyles{
+		formats: make(map[string]int),
+		styles:  make(map[int]int),
+		// The default and the header styles are always present
+		numFmtIds: []int{xlsxNumFmtGeneral, xlsxNumFmtGeneral},
+	}
+}
+
+// get returns the index of the cell style with the number format. The format is either the id of the built-in
+// number format or the custom format code.
+func (s *xlsxStyles) get(format interface{}) int {
+	numFmtId := xlsxNumFmtGeneral
+
+	switch v := format.(type) {
+	case int:
+		numFmtId = v
+	case string:
+		id, ok := s.formats[v]
+
+		if !ok {
+			id = xlsxNumFmtCustomMin + len(s.codes)
+			s.formats[v] = id
+			s.codes = append(s.codes, v)
+		}
+
+		numFmtId = id
+	}
+
+	style, ok := s.styles[numFmtId]
+
+	if !ok {
+		style = len(s.numFmtIds)
+		s.styles[numFmtId] = style
+		s.numFmtIds = append(s.numFmtIds, numFmtId)
+	}
+
+	return style
+}
+
+func (s *xlsxStyles) write(w io.Writer) error {
+	b := &strings.Builder{}
+	b.WriteString(xml.Header)
+	b.WriteString(`<styleSheet xmlns="` + xlsxNamespaceMain + `">`)
+
+	if len(s.codes) > 0 {
+		fmt.Fprintf(b, `<numFmts count="%d">`, len(s.codes))
+
+		for i, code := range s.codes {
+			fmt.Fprintf(b, `<numFmt numFmtId="%d" formatCode="`, xlsxNumFmtCustomMin+i)
+			_ = xml.EscapeText(b, []byte(code))
+			b.WriteString(`"/>`)
+		}
+
+		b.WriteString(`</numFmts>`)
+	}
+
+	b.WriteString(`<fonts count="2">`)
+	b.WriteString(`<font><sz val="11"/><name val="Calibri"/></font>`)
+	b.WriteString(`<font><b/><sz val="11"/><name val="Calibri"/></font>`)
+	b.WriteString(`</fonts>`)
+	b.WriteString(`<fills count="2"><fill><patternFill patternType="none"/></fill>`)
+	b.WriteString(`<fill><patternFill patternType="gray125"/></fill></fills>`)
+	b.WriteString(`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>`)
+	b.WriteString(`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>`)
+	fmt.Fprintf(b, `<cellXfs count="%d">`, len(s.numFmtIds))
+
+	for i, numFmtId := range s.numFmtIds {
+		if i == xlsxStyleHeader {
+			b.WriteString(`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>`)
+			continue
+		}
+
+		fmt.Fprintf(b, `<xf numFmtId="%d" fontId="0" fillId="0" borderId="0" xfId="0"`, numFmtId)
+
+		if numFmtId != xlsxNumFmtGeneral {
+			b.WriteString(` applyNumberFormat="1"`)
+		}
+
+		b.WriteString(`/>`)
+	}
+
+	b.WriteString(`</cellXfs>`)
+	b.WriteString(`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>`)
+	b.WriteString(`</styleSheet>`)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func getXlsxNumberFormat(column *ReportColumn, row map[string]interface{}, data interface{}) interface{} {
+	switch column.Type {
+	case ReportColumnTypeInteger:
+		return xlsxNumFmtInteger
+	case ReportColumnTypeCurrency:
+		currency, ok := row[column.CurrencyField].(string)
+
+		if !ok || currency == "" {
+			if fields, isMap := data.(map[string]interface{}); isMap {
+				currency, _ = fields[column.CurrencyField].(string)
+			}
+		}
+
+		if currency == "" {
+			return xlsxNumFmtNumber
+		}
+
+		return fmt.Sprintf(xlsxCurrencyFormatMask, strings.ReplaceAll(currency, "\"", ""))
+	}
+
+	return xlsxNumFmtNumber
+}
+
+func getXlsxNumber(val interface{}) (float64, bool) {
+	switch v := val.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	}
+
+	return 0, false
+}
+
+// xlsxCellName returns the reference of the cell, the column index is zero based.
+func xlsxCellName(column, row int) string {
+	name := ""
+
+	for column >= 0 {
+		name = string(rune('A'+column%26)) + name
+		column = column/26 - 1
+	}
+
+	return name + strconv.Itoa(row)
+}
@@ -0,0 +1,155 @@
+package internal
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"github.com/paysuper/paysuper-reporter/pkg/proto"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+type XlsxRendererTestSuite struct {
+	suite.Suite
+	renderer DocumentGeneratorInterface
+}
+
+func Test_XlsxRenderer(t *testing.T) {
+	suite.Run(t, new(XlsxRendererTestSuite))
+}
+
+func (suite *XlsxRendererTestSuite) SetupTest() {
+	suite.renderer = newXlsxRenderer()
+}
+
+func (suite *XlsxRendererTestSuite) TestXlsxRenderer_Render_Ok_Royalty() {
+	b, err := suite.renderer.Render(context.Background(), &proto.GeneratorPayload{
+		ReportType: reporterpb.ReportTypeRoyalty,
+		Data: map[string]interface{}{
+			"currency": "EUR",
+			"products": []map[string]interface{}{
+				{"product": "Game <Deluxe>", "region": "EU", "total_end_user_sales": int32(2), "license_fee": 10.25},
+			},
+			"corrections": []map[string]interface{}{
+				{"entry_date": "2020-01-01T00:00:00", "amount": float64(-5), "reason": "chargeback"},
+			},
+			"products_total": map[string]interface{}{"total_end_user_sales": int32(2), "license_fee": 10.25},
+		},
+	})
+	assert.NoError(suite.T(), err)
+
+	files := suite.unzip(b)
+	assert.Contains(suite.T(), files, "[Content_Types].xml")
+	assert.Contains(suite.T(), files, "_rels/.rels")
+	assert.Contains(suite.T(), files, "xl/_rels/workbook.xml.rels")
+	assert.Contains(suite.T(), files, "xl/worksheets/sheet1.xml")
+	assert.Contains(suite.T(), files, "xl/worksheets/sheet2.xml")
+	assert.Contains(suite.T(), files, "xl/worksheets/sheet3.xml")
+
+	for name, content := range files {
+		suite.assertWellFormed(name, content)
+	}
+
+	assert.Contains(suite.T(), files["xl/workbook.xml"], `<sheet name="Products" sheetId="1" r:id="rId1"/>`)
+	assert.Contains(suite.T(), files["xl/workbook.xml"], `<sheet name="Corrections" sheetId="2" r:id="rId2"/>`)
+	assert.Contains(suite.T(), files["xl/workbook.xml"], `<sheet name="Totals" sheetId="3" r:id="rId3"/>`)
+
+	products := files["xl/worksheets/sheet1.xml"]
+	assert.Contains(suite.T(), products, `state="frozen"`)
+	assert.Contains(suite.T(), products, `Game &lt;Deluxe&gt;`)
+	assert.Contains(suite.T(), products, `<c r="C2" s="2"><v>2</v></c>`)
+	assert.Contains(suite.T(), products, `<c r="K2" s="3"><v>10.25</v></c>`)
+
+	assert.Contains(suite.T(), files["xl/worksheets/sheet2.xml"], `<c r="B2" s="3"><v>-5</v></c>`)
+	assert.Contains(suite.T(), files["xl/worksheets/sheet3.xml"], `<c r="I2" s="3"><v>10.25</v></c>`)
+
+	styles := files["xl/styles.xml"]
+	assert.Contains(suite.T(), styles, `<numFmt numFmtId="164" formatCode="#,##0.00 &#34;EUR&#34;"/>`)
+	assert.Contains(suite.T(), styles, `<cellXfs count="4">`)
+}
+
+func (suite *XlsxRendererTestSuite) TestXlsxRenderer_Render_Ok_RowCurrency() {
+	b, err := suite.renderer.Render(context.Background(), &proto.GeneratorPayload{
+		ReportType: reporterpb.ReportTypeTransactions,
+		Data: map[string]interface{}{
+			"transactions": []map[string]interface{}{
+				{"transaction_id": "1", "net_amount": 1.5, "currency": "USD"},
+				{"transaction_id": "2", "net_amount": 2.5, "currency": "RUB"},
+				{"transaction_id": "3", "net_amount": 3.5},
+			},
+		},
+	})
+	assert.NoError(suite.T(), err)
+
+	files := suite.unzip(b)
+	assert.Contains(suite.T(), files["xl/styles.xml"], `formatCode="#,##0.00 &#34;USD&#34;"`)
+	assert.Contains(suite.T(), files["xl/styles.xml"], `formatCode="#,##0.00 &#34;RUB&#34;"`)
+
+	sheet := files["xl/worksheets/sheet1.xml"]
+	assert.Contains(suite.T(), sheet, `<c r="G2" s="2"><v>1.5</v></c>`)
+	assert.Contains(suite.T(), sheet, `<c r="G3" s="3"><v>2.5</v></c>`)
+	assert.Contains(suite.T(), sheet, `<c r="G4" s="4"><v>3.5</v></c>`)
+	assert.Contains(suite.T(), sheet, `<c r="F2" s="0" t="inlineStr"><is><t xml:space="preserve">1</t></is></c>`)
+}
+
+func (suite *XlsxRendererTestSuite) TestXlsxRenderer_Render_Error_ReportTypeUnsupported() {
+	_, err := suite.renderer.Render(context.Background(), &proto.GeneratorPayload{ReportType: reporterpb.ReportTypeAgreement})
+	assert.Equal(suite.T(), errXlsxReportUnsupported, err)
+}
+
+func (suite *XlsxRendererTestSuite) TestXlsxRenderer_Render_Error_RowsInvalid() {
+	_, err := suite.renderer.Render(context.Background(), &proto.GeneratorPayload{
+		ReportType: reporterpb.ReportTypeRoyalty,
+		Data:       map[string]interface{}{"products": 1},
+	})
+	assert.Equal(suite.T(), errReportSectionRowsInvalid, err)
+}
+
+func (suite *XlsxRendererTestSuite) TestXlsxRenderer_xlsxCellName() {
+	assert.Equal(suite.T(), "A1", xlsxCellName(0, 1))
+	assert.Equal(suite.T(), "Z2", xlsxCellName(25, 2))
+	assert.Equal(suite.T(), "AA3", xlsxCellName(26, 3))
+	assert.Equal(suite.T(), "AZ4", xlsxCellName(51, 4))
+	assert.Equal(suite.T(), "BA5", xlsxCellName(52, 5))
+}
+
+func (suite *XlsxRendererTestSuite) unzip(b []byte) map[string]string {
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	assert.NoError(suite.T(), err)
+
+	files := make(map[string]string)
+
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		assert.NoError(suite.T(), err)
+
+		content, err := ioutil.ReadAll(rc)
+		assert.NoError(suite.T(), err)
+		assert.NoError(suite.T(), rc.Close())
+
+		files[f.Name] = string(content)
+	}
+
+	return files
+}
+
+func (suite *XlsxRendererTestSuite) assertWellFormed(name, content string) {
+	decoder := xml.NewDecoder(bytes.NewBufferString(content))
+
+	for {
+		_, err := decoder.Token()
+
+		if err == io.EOF {
+			return
+		}
+
+		if !assert.NoError(suite.T(), err, name) {
+			return
+		}
+	}
+}
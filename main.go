@@ -1,10 +1,30 @@
 package main
 
-import "github.com/paysuper/paysuper-reporter/internal"
+import (
+	"github.com/paysuper/paysuper-reporter/internal"
+	"os"
+)
 
 func main() {
 	app := internal.NewApplication()
+	err := run(app)
+	app.Stop()
+
+	if err != nil {
+		os.Exit(1)
+	}
+}
+
+func run(app *internal.Application) error {
+	if len(os.Args) > 1 && os.Args[1] == internal.CommandDeadLetter {
+		return app.RunDeadLetterCommand(os.Args[2:])
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == internal.CommandCompliance {
+		return app.RunComplianceCommand(os.Args[2:])
+	}
 
-	defer app.Stop()
 	app.Run()
+
+	return nil
 }
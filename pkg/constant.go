@@ -4,10 +4,14 @@ const (
 	LoggerName = "PAYSUPER_REPORTER"
 
 	MIMEApplicationJSON = "application/json"
+	MIMETextHtml        = "text/html"
 
-	ResponseStatusOk          = int32(200)
-	ResponseStatusBadData     = int32(400)
-	ResponseStatusSystemError = int32(500)
+	ResponseStatusOk              = int32(200)
+	ResponseStatusBadData         = int32(400)
+	ResponseStatusNotFound        = int32(404)
+	ResponseStatusConflict        = int32(409)
+	ResponseStatusTooManyRequests = int32(429)
+	ResponseStatusSystemError     = int32(500)
 
 	OutputContentTypeXlsx = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
 	OutputContentTypeCsv  = "text/csv"
@@ -16,9 +20,69 @@ const (
 	RecipeXlsx = "html-to-xlsx"
 	RecipeCsv  = "text"
 	RecipePdf  = "chrome-pdf"
+	RecipeHtml = "html"
 
-	BrokerMessageRetryMaxCount = 10
+	RendererJsReport      = "jsreport"
+	RendererNative        = "native"
+	RendererHtmlPdf       = "html_pdf"
+	RendererRouteMask     = "%s.%s"
+	RendererRouteWildcard = "*"
 
-	BrokerGenerateReportTopicName = "reporter-generate"
-	BrokerPostProcessTopicName    = "reporter-post-process"
+	StorageDriverS3           = "s3"
+	StorageDriverS3Compatible = "s3_compatible"
+	StorageDriverLocal        = "local"
+	StorageDefaultRegion      = "us-east-1"
+	StorageTargetReports      = "reports"
+	StorageTargetAgreements   = "agreements"
+	StorageExpiryRetention    = "retention"
+	StorageExpiryNone         = "none"
+	StorageAclPrivate         = "private"
+
+	EncryptionKeyProviderLocal = "local"
+	EncryptionDownloadPath     = "/download"
+
+	BrokerRetryQueueNameMask = "%s.retry.%d"
+	BrokerQueueNameMask      = "%s.queue"
+	BrokerConsumerTagMask    = "%s.%d"
+	BrokerExchangeKind       = "topic"
+	BrokerQueueBindKey       = "*"
+	BrokerContentType        = "application/protobuf"
+
+	BrokerGenerateReportTopicName            = "reporter-generate"
+	BrokerGenerateReportInteractiveTopicName = "reporter-generate-interactive"
+	BrokerPostProcessTopicName               = "reporter-post-process"
+	BrokerDeadLetterTopicName                = "reporter-dead-letter"
+
+	ReportFilePriorityInteractive = "interactive"
+	ReportFilePriorityBulk        = "bulk"
+
+	CollectionReportFile     = "report_file"
+	CollectionLegalHold      = "report_legal_hold"
+	CollectionErasureReceipt = "report_erasure_receipt"
+
+	ReportFileStatusQueued         = "queued"
+	ReportFileStatusBuilding       = "building"
+	ReportFileStatusRendering      = "rendering"
+	ReportFileStatusUploading      = "uploading"
+	ReportFileStatusUploaded       = "uploaded"
+	ReportFileStatusPostProcessing = "post_processing"
+	ReportFileStatusCompleted      = "completed"
+	ReportFileStatusFailed         = "failed"
+	ReportFileStatusRetrying       = "retrying"
+	ReportFileStatusCancelled      = "cancelled"
+	ReportFileStatusExpired        = "expired"
+	ReportFileStatusErased         = "erased"
+
+	ReportTempFileMask = "reporter_%s.%s"
+
+	ErasureStatusCompleted = "completed"
+	ErasureStatusPartial   = "partial"
+	ErasureResultErased    = "erased"
+	ErasureResultRetained  = "retained"
+	ErasureResultFailed    = "failed"
+
+	ListFilesDefaultLimit = int64(100)
+	ListFilesMaxLimit     = int64(1000)
+
+	DownloadUrlMaxTtl = int64(604800)
 )
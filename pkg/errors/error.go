@@ -1,7 +1,7 @@
 package errors
 
 import (
-	"github.com/paysuper/paysuper-proto/go/reporterpb"
+	"github.com/paysuper/paysuper-reporter/pkg/reporterpb"
 )
 
 var (
@@ -19,6 +19,24 @@ var (
 	ErrorParamMerchantIdNotFound      = newErrorMsg("rf000012", "unable to find the param <merchant_id>.")
 	ErrorDatabaseQueryFailed          = newErrorMsg("rf000013", "query to database collection failed")
 	ErrorMongoDbOidIncorrect          = newErrorMsg("rf000014", "mongodb object id incorrect")
+	ErrorDocumentBuildFailed          = newErrorMsg("rf000015", "unable to build document data.")
+	ErrorAgreementNameFailed          = newErrorMsg("rf000016", "unable to generate agreement file name.")
+	ErrorTemporaryFileFailed          = newErrorMsg("rf000017", "unable to process temporary file.")
+	ErrorStorageUploadFailed          = newErrorMsg("rf000018", "unable to upload report file to the storage.")
+	ErrorPostProcessFailed            = newErrorMsg("rf000019", "report file post processing failed.")
+	ErrorReportFileNotFound           = newErrorMsg("rf000020", "report file not found.")
+	ErrorReportFileNotReady           = newErrorMsg("rf000021", "report file is not ready for download yet.")
+	ErrorReportFileExpired            = newErrorMsg("rf000022", "report file retention time has expired.")
+	ErrorDownloadUrlFailed            = newErrorMsg("rf000023", "unable to generate report file download url.")
+	ErrorReportFileCancelNotAllowed   = newErrorMsg("rf000024", "completed report file can not be cancelled.")
+	ErrorVatTransactionsCountMismatch = newErrorMsg("rf000025", "count of vat report transactions does not match the vat report.")
+	ErrorReportFilePriorityInvalid    = newErrorMsg("rf000026", "invalid report file priority.")
+	ErrorReportFileLimitExceeded      = newErrorMsg("rf000027", "report file requests limit exceeded.")
+	ErrorIdempotencyKeyConflict       = newErrorMsg("rf000028", "idempotency key has already been used for the other report file.")
+	ErrorLegalHoldNotFound            = newErrorMsg("rf000029", "legal hold not found.")
+	ErrorLegalHoldReasonEmpty         = newErrorMsg("rf000030", "legal hold reason is required.")
+	ErrorMerchantErasureFailed        = newErrorMsg("rf000031", "unable to erase report files of the merchant.")
+	ErrorErasureReasonEmpty           = newErrorMsg("rf000032", "erasure reason is required.")
 )
 
 func newErrorMsg(code, msg string, details ...string) *reporterpb.ResponseErrorMessage {
@@ -1,9 +1,16 @@
 package proto
 
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
 type GeneratorPayload struct {
-	Template *GeneratorTemplate `json:"template"`
-	Options  *GeneratorOptions  `json:"options"`
-	Data     interface{}        `json:"data"`
+	Template   *GeneratorTemplate `json:"template"`
+	Options    *GeneratorOptions  `json:"options"`
+	Data       interface{}        `json:"data"`
+	ReportType string             `json:"-"`
+	FileType   string             `json:"-"`
 }
 
 type GeneratorTemplate struct {
@@ -14,5 +21,111 @@ type GeneratorTemplate struct {
 }
 
 type GeneratorOptions struct {
-	Timeout string `json:"timeout,omitempty"`
+	Timeout int64 `json:"timeout,omitempty"`
+}
+
+// ReportFileJob is the persisted state of the report file generation job.
+type ReportFileJob struct {
+	Id                primitive.ObjectID      `bson:"_id"`
+	UserId            string                  `bson:"user_id"`
+	MerchantId        string                  `bson:"merchant_id"`
+	ReportType        string                  `bson:"report_type"`
+	FileType          string                  `bson:"file_type"`
+	Params            []byte                  `bson:"params"`
+	Template          string                  `bson:"template"`
+	RetentionTime     int32                   `bson:"retention_time"`
+	SendNotification  bool                    `bson:"send_notification"`
+	Status            string                  `bson:"status"`
+	FileName          string                  `bson:"file_name"`
+	Size              int64                   `bson:"size"`
+	ContentType       string                  `bson:"content_type"`
+	ExpiresAt         *time.Time              `bson:"expires_at"`
+	LastError         *ReportFileJobError     `bson:"last_error"`
+	History           []*ReportFileJobHistory `bson:"history"`
+	Bucket            string                  `bson:"bucket"`
+	Fingerprint       string                  `bson:"fingerprint"`
+	ParamsFingerprint string                  `bson:"params_fingerprint,omitempty"`
+	IdempotencyKey    string                  `bson:"idempotency_key,omitempty"`
+	CreatedAt         time.Time               `bson:"created_at"`
+	UpdatedAt         time.Time               `bson:"updated_at"`
+}
+
+type ReportFileJobError struct {
+	Code    string `bson:"code"`
+	Message string `bson:"message"`
+	Details string `bson:"details"`
+}
+
+// ReportFileJobHistory is a single status transition of the report file job.
+type ReportFileJobHistory struct {
+	Status    string              `bson:"status"`
+	Error     *ReportFileJobError `bson:"error,omitempty"`
+	CreatedAt time.Time           `bson:"created_at"`
+}
+
+// ReportFileJobCountQuery defines the filter of the jobs counted by the limits of the report files. Empty fields
+// are not used in the filter.
+type ReportFileJobCountQuery struct {
+	MerchantId  string
+	UserId      string
+	ReportTypes []string
+	Statuses    []string
+	CreatedFrom time.Time
+}
+
+// StorageUploadOptions defines the expiration time and the ACL of the uploaded report file. The zero expiration
+// time means the file does not expire and the empty ACL keeps the default ACL of the bucket.
+type StorageUploadOptions struct {
+	Expires time.Time
+	Acl     string
+}
+
+// ReportFileJobExpiredQuery defines the filter of the jobs purged by the retention sweeper. Jobs under the legal
+// hold of the merchant or of the file are excluded.
+type ReportFileJobExpiredQuery struct {
+	ExpiredAt          time.Time
+	ExcludeMerchantIds []string
+	ExcludeIds         []primitive.ObjectID
+	Limit              int64
+}
+
+// LegalHold stops the retention sweeper from purging all report files of the merchant or the single report file
+// until the hold is released. Released holds are kept for the audit.
+type LegalHold struct {
+	Id         primitive.ObjectID `bson:"_id"`
+	MerchantId string             `bson:"merchant_id"`
+	FileId     string             `bson:"file_id"`
+	Reason     string             `bson:"reason"`
+	UserId     string             `bson:"user_id"`
+	CreatedAt  time.Time          `bson:"created_at"`
+	ReleasedAt *time.Time         `bson:"released_at"`
+	ReleasedBy string             `bson:"released_by"`
+}
+
+// ErasureReceipt is the audit record of the erasure of the report files of the merchant. File keys may contain
+// the personal data, so only their hashes are recorded.
+type ErasureReceipt struct {
+	Id               primitive.ObjectID    `bson:"_id"`
+	MerchantId       string                `bson:"merchant_id"`
+	UserId           string                `bson:"user_id"`
+	Reason           string                `bson:"reason"`
+	Status           string                `bson:"status"`
+	Erased           int32                 `bson:"erased"`
+	Retained         int32                 `bson:"retained"`
+	Failed           int32                 `bson:"failed"`
+	DeadLetterPurged int32                 `bson:"dead_letter_purged"`
+	Items            []*ErasureReceiptItem `bson:"items"`
+	CreatedAt        time.Time             `bson:"created_at"`
+	CompletedAt      time.Time             `bson:"completed_at"`
+}
+
+// ErasureReceiptItem is the result of the erasure of the single report file.
+type ErasureReceiptItem struct {
+	FileId     string `bson:"file_id"`
+	ReportType string `bson:"report_type"`
+	Bucket     string `bson:"bucket"`
+	KeyHash    string `bson:"key_hash"`
+	Size       int64  `bson:"size"`
+	Result     string `bson:"result"`
+	Error      string `bson:"error,omitempty"`
 }
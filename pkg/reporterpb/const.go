@@ -0,0 +1,52 @@
+package reporterpb
+
+import (
+	published "github.com/paysuper/paysuper-proto/go/reporterpb"
+)
+
+// The constants of the published reporterpb package. The service contract generated from api/proto/proto.proto
+// lives in this package until the new RPCs are published to paysuper-proto, the package is then replaced
+// by the published one without changes of its users.
+const (
+	ServiceName    = published.ServiceName
+	ServiceVersion = published.ServiceVersion
+
+	ReportTypeVat                 = published.ReportTypeVat
+	ReportTypeVatTransactions     = published.ReportTypeVatTransactions
+	ReportTypeRoyalty             = published.ReportTypeRoyalty
+	ReportTypeRoyaltyTransactions = published.ReportTypeRoyaltyTransactions
+	ReportTypeTransactions        = published.ReportTypeTransactions
+	ReportTypePayout              = published.ReportTypePayout
+	ReportTypeAgreement           = published.ReportTypeAgreement
+
+	OutputExtensionXlsx = published.OutputExtensionXlsx
+	OutputExtensionCsv  = published.OutputExtensionCsv
+	OutputExtensionPdf  = published.OutputExtensionPdf
+
+	FileMask          = published.FileMask
+	FileMaskAgreement = published.FileMaskAgreement
+
+	ParamsFieldId            = published.ParamsFieldId
+	ParamsFieldCountry       = published.ParamsFieldCountry
+	ParamsFieldStatus        = published.ParamsFieldStatus
+	ParamsFieldPaymentMethod = published.ParamsFieldPaymentMethod
+	ParamsFieldDateFrom      = published.ParamsFieldDateFrom
+	ParamsFieldDateTo        = published.ParamsFieldDateTo
+
+	RequestParameterAgreementNumber                             = published.RequestParameterAgreementNumber
+	RequestParameterAgreementLegalName                          = published.RequestParameterAgreementLegalName
+	RequestParameterAgreementAddress                            = published.RequestParameterAgreementAddress
+	RequestParameterAgreementRegistrationNumber                 = published.RequestParameterAgreementRegistrationNumber
+	RequestParameterAgreementPayoutCost                         = published.RequestParameterAgreementPayoutCost
+	RequestParameterAgreementMinimalPayoutLimit                 = published.RequestParameterAgreementMinimalPayoutLimit
+	RequestParameterAgreementPayoutCurrency                     = published.RequestParameterAgreementPayoutCurrency
+	RequestParameterAgreementPSRate                             = published.RequestParameterAgreementPSRate
+	RequestParameterAgreementHomeRegion                         = published.RequestParameterAgreementHomeRegion
+	RequestParameterAgreementMerchantAuthorizedName             = published.RequestParameterAgreementMerchantAuthorizedName
+	RequestParameterAgreementMerchantAuthorizedPosition         = published.RequestParameterAgreementMerchantAuthorizedPosition
+	RequestParameterAgreementOperatingCompanyLegalName          = published.RequestParameterAgreementOperatingCompanyLegalName
+	RequestParameterAgreementOperatingCompanyAddress            = published.RequestParameterAgreementOperatingCompanyAddress
+	RequestParameterAgreementOperatingCompanyRegistrationNumber = published.RequestParameterAgreementOperatingCompanyRegistrationNumber
+	RequestParameterAgreementOperatingCompanyAuthorizedName     = published.RequestParameterAgreementOperatingCompanyAuthorizedName
+	RequestParameterAgreementOperatingCompanyAuthorizedPosition = published.RequestParameterAgreementOperatingCompanyAuthorizedPosition
+)
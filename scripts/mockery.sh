@@ -9,4 +9,10 @@ fi
 . $ROOT_DIR/scripts/common.sh
 
 mockery -recursive=true -name=CentrifugoInterface -dir=${ROOT_DIR}/internal/ -output ${ROOT_DIR}/internal/mocks
-mockery -recursive=true -name=DocumentGeneratorInterface -dir=${ROOT_DIR}/internal/ -output ${ROOT_DIR}/internal/mocks
\ No newline at end of file
+mockery -recursive=true -name=DocumentGeneratorInterface -dir=${ROOT_DIR}/internal/ -output ${ROOT_DIR}/internal/mocks
+mockery -recursive=true -name=ReportFileRepositoryInterface -dir=${ROOT_DIR}/internal/ -output ${ROOT_DIR}/internal/mocks
+mockery -recursive=true -name=StorageInterface -dir=${ROOT_DIR}/internal/ -output ${ROOT_DIR}/internal/mocks
+mockery -recursive=true -name=DeadLetterQueueInterface -dir=${ROOT_DIR}/internal/ -output ${ROOT_DIR}/internal/mocks
+mockery -recursive=true -name=RetryQueueInterface -dir=${ROOT_DIR}/internal/ -output ${ROOT_DIR}/internal/mocks
+mockery -recursive=true -name=LegalHoldRepositoryInterface -dir=${ROOT_DIR}/internal/ -output ${ROOT_DIR}/internal/mocks
+mockery -recursive=true -name=ErasureReceiptRepositoryInterface -dir=${ROOT_DIR}/internal/ -output ${ROOT_DIR}/internal/mocks
\ No newline at end of file